@@ -17,7 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -80,6 +83,125 @@ func (c *CommonSpec) GetTargetNamespace() string {
 	return c.TargetNamespace
 }
 
+// UpgradeRollback configures rolling back a component to the installer sets
+// of previous release when an upgrade doesn't become ready
+type UpgradeRollback struct {
+	// Timeout after which the upgrade is rolled back if the installer sets
+	// of new release are not ready
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// UpgradeRollbackSpec is implemented by the specs of components
+// which support rolling back an upgrade
+type UpgradeRollbackSpec interface {
+	GetUpgradeRollback() *UpgradeRollback
+}
+
+// IsEnabled returns true if the upgrade has to be rolled back after timeout
+func (r *UpgradeRollback) IsEnabled() bool {
+	return r != nil && r.Timeout != nil && r.Timeout.Duration > 0
+}
+
+func (r *UpgradeRollback) validate(path string) (errs *apis.FieldError) {
+	if r != nil && r.Timeout != nil && r.Timeout.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(r.Timeout.Duration.String(), path+".timeout"))
+	}
+	return errs
+}
+
+// InstallerSetOptions configures how the installer sets of a component
+// apply its resources
+type InstallerSetOptions struct {
+	// ContinueOnError applies the rest of the resources when a resource
+	// fails to apply, the failed resources are reported in status
+	// +optional
+	ContinueOnError bool `json:"continueOnError,omitempty"`
+	// ServerSideApply applies the resources using server-side apply, fields
+	// owned by other field managers are reported as conflict in status
+	// instead of being overwritten
+	// +optional
+	ServerSideApply bool `json:"serverSideApply,omitempty"`
+	// DriftPolicy decides what to do when a resource on cluster is changed
+	// manually, one of enforce, report or ignore (default)
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+}
+
+// InstallerSetOptionsSpec is implemented by the specs of components
+// whose installer sets can be configured
+type InstallerSetOptionsSpec interface {
+	GetInstallerSetOptions() *InstallerSetOptions
+}
+
+func (o *InstallerSetOptions) validate(path string) (errs *apis.FieldError) {
+	if o == nil {
+		return errs
+	}
+	switch o.DriftPolicy {
+	case "", DriftPolicyEnforce, DriftPolicyReport, DriftPolicyIgnore:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(o.DriftPolicy, path+".driftPolicy"))
+	}
+	return errs
+}
+
+// Upgrade configures how a component is upgraded when the
+// release version changes
+type Upgrade struct {
+	// Strategy of the upgrade, one of recreate and staged. With recreate the
+	// CRDs, webhook and controllers are rolled out at once. With staged the
+	// CRDs are rolled out first, then the webhook and the controllers only
+	// after the webhook passes a health check
+	// +optional
+	Strategy string `json:"strategy,omitempty"`
+}
+
+// UpgradeSpec is implemented by the specs of components
+// which support choosing the upgrade strategy
+type UpgradeSpec interface {
+	GetUpgrade() *Upgrade
+}
+
+// IsStaged returns true if the upgrade is rolled out in stages
+func (u *Upgrade) IsStaged() bool {
+	return u != nil && u.Strategy == UpgradeStrategyStaged
+}
+
+func (u *Upgrade) validate(path string) (errs *apis.FieldError) {
+	if u != nil && u.Strategy != "" && !isValueInArray(UpgradeStrategies, u.Strategy) {
+		errs = errs.Also(apis.ErrInvalidValue(u.Strategy, path+".strategy"))
+	}
+	return errs
+}
+
+type shippedVersionsKey struct{}
+
+// WithShippedVersions returns a copy of ctx with the versions of components
+// shipped with the operator, keyed by the kind of component, which are used
+// to validate spec.version
+func WithShippedVersions(ctx context.Context, versions map[string][]string) context.Context {
+	return context.WithValue(ctx, shippedVersionsKey{}, versions)
+}
+
+// validateVersion validates the version against the versions of component
+// shipped with the operator. The version is not validated if the shipped
+// versions are not known in ctx
+func validateVersion(ctx context.Context, kind, version, path string) *apis.FieldError {
+	if version == "" {
+		return nil
+	}
+	shipped, ok := ctx.Value(shippedVersionsKey{}).(map[string][]string)
+	if !ok || len(shipped[kind]) == 0 {
+		return nil
+	}
+	if !isValueInArray(shipped[kind], version) {
+		return apis.ErrInvalidValue(version, path,
+			fmt.Sprintf("version is not shipped with the operator, supported versions: %s", strings.Join(shipped[kind], ", ")))
+	}
+	return nil
+}
+
 // Param declares an string value to use for the parameter called name.
 type Param struct {
 	Name  string `json:"name,omitempty"`
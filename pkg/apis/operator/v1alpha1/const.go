@@ -32,6 +32,40 @@ const (
 	ProfileBasic = "basic"
 	ProfileLite  = "lite"
 
+	// Components of TektonConfig
+	ComponentPipeline  = "pipeline"
+	ComponentTrigger   = "trigger"
+	ComponentChain     = "chain"
+	ComponentResult    = "result"
+	ComponentDashboard = "dashboard"
+	ComponentAddon     = "addon"
+	ComponentEnabled   = "enabled"
+	ComponentDisabled  = "disabled"
+
+	// Upgrade strategies
+	UpgradeStrategyRecreate = "recreate"
+	UpgradeStrategyStaged   = "staged"
+
+	// Pruner groupings and statuses
+	PruneGroupByNamespace = "namespace"
+	PruneGroupByName      = "name"
+	PruneStatusSucceeded  = "succeeded"
+	PruneStatusFailed     = "failed"
+	PruneStatusCancelled  = "cancelled"
+
+	// Pruner archive sinks
+	PruneArchiveSinkPVC     = "pvc"
+	PruneArchiveSinkS3      = "s3"
+	PruneArchiveSinkResults = "results"
+
+	// ResultsDefaultDBName is the name of the database of Results
+	// when spec.db.name is not set
+	ResultsDefaultDBName = "tekton-results"
+
+	// Results log storages
+	ResultsLogsTypeFile = "File"
+	ResultsLogsTypeS3   = "S3"
+
 	// Addon Params
 	ClusterTasksParam      = "clusterTasks"
 	PipelineTemplatesParam = "pipelineTemplates"
@@ -43,15 +77,64 @@ const (
 	LastAppliedHashKey     = "operator.tekton.dev/last-applied-hash"
 	CreatedByKey           = "operator.tekton.dev/created-by"
 	ReleaseVersionKey      = "operator.tekton.dev/release-version"
+	ComponentVersionKey    = "operator.tekton.dev/component-version"
 	Component              = "operator.tekton.dev/component" // Used in case a component has sub-components eg TektonHub
 	ReleaseMinorVersionKey = "operator.tekton.dev/release-minor-version"
 	TargetNamespaceKey     = "operator.tekton.dev/target-namespace"
 	InstallerSetType       = "operator.tekton.dev/type"
 	LabelOperandName       = "operator.tekton.dev/operand-name"
 	DbSecretHash           = "operator.tekton.dev/db-secret-hash"
+	// SecretsHashKey is the annotation on the pod template of a Deployment
+	// with the hash of the Secrets it references, so that it is rolled out
+	// when they change
+	SecretsHashKey = "operator.tekton.dev/secrets-hash"
+	// RolledBackFromKey is the annotation on installer sets restored by a
+	// rollback, with the release version which failed to become ready
+	RolledBackFromKey = "operator.tekton.dev/rolled-back-from"
+
+	// UpgradeStartTimeKey is the annotation on TektonConfig with the time an
+	// upgrade of the operator started, until TektonConfig is ready
+	UpgradeStartTimeKey = "operator.tekton.dev/upgrade-start-time"
+
+	// RotateSigningKeysKey is the annotation on TektonChain requesting a
+	// rotation of the generated signing keys whenever its value changes
+	RotateSigningKeysKey = "operator.tekton.dev/rotate-signing-keys"
+	// SigningKeysRotatedKey and SigningKeysRotationRequestKey are the
+	// annotations on the signing Secret with the time of the latest rotation
+	// and the value of the rotate annotation it handled
+	SigningKeysRotatedKey         = "operator.tekton.dev/signing-keys-rotated"
+	SigningKeysRotationRequestKey = "operator.tekton.dev/signing-keys-rotation-request"
+
+	// ReplicasOverrideKey is the annotation on a Deployment whose replicas
+	// are set by options.deployments, the replicas on cluster are then
+	// reverted to the override instead of being kept
+	ReplicasOverrideKey = "operator.tekton.dev/replicas-override"
+
+	// ManagedByInstallerSetKey is the label set to true on the resources
+	// applied by an installer set, the operator only watches the resources
+	// with it
+	ManagedByInstallerSetKey = "operator.tekton.dev/managed-by-installer-set"
+
+	// UpgradeStageKey is the annotation on the main deployment installer set
+	// with the stage reached by a staged upgrade
+	UpgradeStageKey = "operator.tekton.dev/upgrade-stage"
+
+	// ReadinessRoleKey is the annotation on a workload in TektonInstallerSet
+	// manifests which decides the condition reporting its readiness
+	ReadinessRoleKey        = "operator.tekton.dev/readiness-role"
+	ReadinessRoleWebhook    = "webhook"
+	ReadinessRoleController = "controller"
+	ReadinessRoleWorkload   = "workload"
+	// ReadinessRoleNone skips the readiness check of a workload
+	ReadinessRoleNone = "none"
 
 	UpgradePending = "upgrade pending"
 	Reinstalling   = "reinstalling"
+	RollingBack    = "upgrade not ready, rolling back"
+	RolledBack     = "upgrade rolled back, update the spec to retry"
+	UpgradeStaging = "staged upgrade in progress"
+	UpgradePaused  = "staged upgrade paused"
+	UpgradeWaiting = "staged upgrade waiting"
 
 	RequeueDelay = 10 * time.Second
 )
@@ -85,11 +168,85 @@ var (
 		ProfileAll,
 	}
 
+	// ProfileComponents lists the components installed by each profile, the
+	// dashboard is only installed on Kubernetes and the addon on OpenShift
+	ProfileComponents = map[string][]string{
+		ProfileLite:  {ComponentPipeline},
+		ProfileBasic: {ComponentPipeline, ComponentTrigger},
+		ProfileAll:   {ComponentPipeline, ComponentTrigger, ComponentDashboard, ComponentAddon},
+	}
+
+	// Components are the components which may be set in spec.components
+	// of TektonConfig
+	Components = []string{
+		ComponentPipeline,
+		ComponentTrigger,
+		ComponentChain,
+		ComponentResult,
+		ComponentDashboard,
+		ComponentAddon,
+	}
+
+	ComponentStates = []string{
+		ComponentEnabled,
+		ComponentDisabled,
+	}
+
+	UpgradeStrategies = []string{
+		UpgradeStrategyRecreate,
+		UpgradeStrategyStaged,
+	}
+
 	PruningResource = []string{
 		"taskrun",
 		"pipelinerun",
 	}
 
+	PruneGroupBy = []string{
+		PruneGroupByNamespace,
+		PruneGroupByName,
+	}
+
+	PruneStatuses = []string{
+		PruneStatusSucceeded,
+		PruneStatusFailed,
+		PruneStatusCancelled,
+	}
+
+	PruneArchiveSinks = []string{
+		PruneArchiveSinkPVC,
+		PruneArchiveSinkS3,
+		PruneArchiveSinkResults,
+	}
+
+	// PolicyArchiveSinks are the sinks allowed in a TektonPrunerPolicy, the
+	// pvc and s3 sinks write to storage the cluster admin sets up in
+	// TektonConfig, the volume of the operator or any endpoint it can reach
+	PolicyArchiveSinks = []string{
+		PruneArchiveSinkResults,
+	}
+
+	ResultsLogsTypes = []string{
+		ResultsLogsTypeFile,
+		ResultsLogsTypeS3,
+	}
+
+	ResultsDBSSLModes = []string{
+		"disable",
+		"allow",
+		"prefer",
+		"require",
+		"verify-ca",
+		"verify-full",
+	}
+
+	ResultsLogLevels = []string{
+		"debug",
+		"info",
+		"warn",
+		"error",
+	}
+
 	AddonParams = map[string]ParamValue{
 		ClusterTasksParam:      defaultParamValue,
 		PipelineTemplatesParam: defaultParamValue,
@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+// AdditionalOptions holds the overrides merged into the resources shipped
+// with a component, on top of the transformations done by the operator
+type AdditionalOptions struct {
+	// ConfigMaps holds the overrides of ConfigMaps, keyed by name
+	// +optional
+	ConfigMaps map[string]ConfigMapOverride `json:"configMaps,omitempty"`
+	// Deployments holds the overrides of Deployments, keyed by name
+	// +optional
+	Deployments map[string]DeploymentOverride `json:"deployments,omitempty"`
+}
+
+// ConfigMapOverride is merged into the ConfigMap with the same name
+type ConfigMapOverride struct {
+	// Data is merged into the data of the ConfigMap, replacing the values
+	// of the same keys
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// DeploymentOverride is merged into the Deployment with the same name
+type DeploymentOverride struct {
+	// Labels are added to the Deployment and its pod template
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are added to the Deployment and its pod template
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Replicas replaces the replicas of the Deployment
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Affinity replaces the affinity of the pods
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// TopologySpreadConstraints replace the topology spread constraints of the pods
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// Containers holds the overrides of containers, keyed by name
+	// +optional
+	Containers map[string]ContainerOverride `json:"containers,omitempty"`
+}
+
+// ContainerOverride is merged into the container with the same name
+type ContainerOverride struct {
+	// Resources replaces the resource requirements of the container
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Env is merged into the env of the container by name
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// Args is merged into the args of the container, replacing the
+	// args setting the same flag, the other args are appended
+	// +optional
+	Args []string `json:"args,omitempty"`
+}
+
+// knownConfigMapKeys are the keys of the ConfigMaps shipped with the
+// components. The overrides of other ConfigMaps and keys are merged as is,
+// with a warning on validation. A key ending with "*" matches the keys with
+// the prefix, "*" matches any key
+var knownConfigMapKeys = map[string][]string{
+	// Pipelines
+	"feature-flags": {
+		"disable-affinity-assistant", "disable-creds-init", "await-sidecar-readiness",
+		"running-in-environment-with-injected-sidecars", "require-git-ssh-secret-known-hosts",
+		"enable-tekton-oci-bundles", "enable-custom-tasks", "enable-api-fields", "embedded-status",
+		"send-cloudevents-for-runs", "scope-when-expressions-to-task", "enable-spire",
+		"resource-verification-mode", "disable-home-env-overwrite", "disable-working-directory-overwrite",
+	},
+	"config-defaults": {
+		"default-timeout-minutes", "default-service-account", "default-managed-by-label-value",
+		"default-pod-template", "default-cloud-events-sink", "default-affinity-assistant-pod-template",
+		"default-task-run-workspace-binding", "default-max-matrix-combinations-count",
+		"default-forbidden-env", "default-resolver-type",
+	},
+	"config-observability": {
+		"metrics.backend-destination", "metrics.stackdriver-project-id", "metrics.allow-stackdriver-custom-metrics",
+		"metrics.taskrun.level", "metrics.taskrun.duration-type", "metrics.pipelinerun.level",
+		"metrics.pipelinerun.duration-type", "metrics.count.enable-reason", "profiling.enable",
+	},
+	"config-logging":         {"zap-logger-config", "loglevel.*"},
+	"config-leader-election": {"lease-duration", "renew-deadline", "retry-period", "buckets"},
+	"config-artifact-bucket": {
+		"location", "bucket.service.account.secret.name", "bucket.service.account.secret.key",
+		"bucket.service.account.field.name",
+	},
+	"config-artifact-pvc":  {"size", "storageClassName"},
+	"config-registry-cert": {"*"},
+	// Triggers
+	"feature-flags-triggers":        {"enable-api-fields", "labels-exclusion-pattern"},
+	"config-defaults-triggers":      {"default-service-account"},
+	"config-logging-triggers":       {"zap-logger-config", "loglevel.*"},
+	"config-observability-triggers": {"metrics.backend-destination", "metrics.stackdriver-project-id", "metrics.allow-stackdriver-custom-metrics"},
+	// Chains
+	"chains-config": {"artifacts.*", "storage.*", "builder.id", "signers.*", "transparency.*"},
+}
+
+// isKnownConfigMapKey returns true if the key is known for the ConfigMap,
+// the keys starting with "_" like "_example" are ignored by the components
+func isKnownConfigMapKey(known []string, key string) bool {
+	if strings.HasPrefix(key, "_") {
+		return true
+	}
+	for _, k := range known {
+		if k == key || (strings.HasSuffix(k, "*") && strings.HasPrefix(key, strings.TrimSuffix(k, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *AdditionalOptions) validate(path string) (errs *apis.FieldError) {
+	if o == nil {
+		return nil
+	}
+	for name, configMap := range o.ConfigMaps {
+		configMapPath := fmt.Sprintf("%s.configMaps.%s", path, name)
+		known, ok := knownConfigMapKeys[name]
+		if !ok {
+			errs = errs.Also(apis.ErrGeneric("ConfigMap is not known to be shipped with the components, the data is merged as is", configMapPath).At(apis.WarningLevel))
+			continue
+		}
+		// sorted for stable messages
+		var unknown []string
+		for key := range configMap.Data {
+			if !isKnownConfigMapKey(known, key) {
+				unknown = append(unknown, key)
+			}
+		}
+		sort.Strings(unknown)
+		for _, key := range unknown {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("key %q is not known for the ConfigMap, the data is merged as is", key), configMapPath+".data").At(apis.WarningLevel))
+		}
+	}
+	for name, deployment := range o.Deployments {
+		deploymentPath := fmt.Sprintf("%s.deployments.%s", path, name)
+		if deployment.Replicas != nil && *deployment.Replicas < 0 {
+			errs = errs.Also(apis.ErrInvalidValue(*deployment.Replicas, deploymentPath+".replicas"))
+		}
+		for containerName, container := range deployment.Containers {
+			for i, env := range container.Env {
+				if env.Name == "" {
+					errs = errs.Also(apis.ErrMissingField(fmt.Sprintf("%s.containers.%s.env[%d].name", deploymentPath, containerName, i)))
+				}
+			}
+		}
+	}
+	return errs
+}
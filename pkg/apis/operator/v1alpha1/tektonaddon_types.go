@@ -51,6 +51,9 @@ type TektonAddonSpec struct {
 	// Config holds the configuration for resources created by Addon
 	// +optional
 	Config Config `json:"config,omitempty"`
+	// Options holds the overrides of resources created by Addon
+	// +optional
+	Options *AdditionalOptions `json:"options,omitempty"`
 }
 
 // TektonAddonStatus defines the observed state of TektonAddon
@@ -42,7 +42,7 @@ func (ta *TektonAddon) Validate(ctx context.Context) (errs *apis.FieldError) {
 		errs = errs.Also(validateAddonParams(ta.Spec.Params, "spec.params"))
 	}
 
-	return errs
+	return errs.Also(ta.Spec.Options.validate("spec.options"))
 }
 
 func validateAddonParams(params []Param, pathToParams string) *apis.FieldError {
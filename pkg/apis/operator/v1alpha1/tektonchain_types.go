@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
@@ -56,6 +57,40 @@ type TektonChainSpec struct {
 	// Config holds the configuration for resources created by TektonChain
 	// +optional
 	Config Config `json:"config,omitempty"`
+	// Options holds the overrides of resources created by TektonChain
+	// +optional
+	Options *AdditionalOptions `json:"options,omitempty"`
+	// SigningKeys lets the operator generate and rotate the x509 signing keys
+	// +optional
+	SigningKeys *SigningKeys `json:"signingKeys,omitempty"`
+}
+
+// ChainConfig defines the chains section of TektonConfig
+type ChainConfig struct {
+	// Enable installs Chains with the all and basic profiles
+	// +optional
+	Enable bool `json:"enable,omitempty"`
+	Chain  `json:",inline"`
+	// SigningKeys lets the operator generate and rotate the x509 signing keys
+	// +optional
+	SigningKeys *SigningKeys `json:"signingKeys,omitempty"`
+}
+
+// SigningKeys defines the key pair generated by the operator for the x509
+// signer of Chains
+type SigningKeys struct {
+	// Generate creates the key pair in the signing-secrets Secret, keys
+	// created by hand are never replaced
+	// +optional
+	Generate bool `json:"generate,omitempty"`
+	// RotationPeriod is the age after which the key pair is replaced,
+	// the key pair is only rotated on demand if not set
+	// +optional
+	RotationPeriod *metav1.Duration `json:"rotationPeriod,omitempty"`
+	// GracePeriod is how long the public keys of the replaced key pairs are
+	// kept for verifiers, 7 days by default
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
 }
 
 // Chain defines the field to provide chain configuration
@@ -99,6 +134,32 @@ type Chain struct {
 	// transparency config
 	TransparencyConfigEnabled *bool  `json:"transparency.enabled,omitempty"`
 	TransparencyConfigURL     string `json:"transparency.url,omitempty"`
+
+	// Secrets references the credentials passed to the chains controller,
+	// instead of setting them in the spec
+	// +optional
+	Secrets *ChainSecrets `json:"secrets,omitempty"`
+}
+
+// ChainSecrets references Secrets in the target namespace holding the
+// credentials of Chains
+type ChainSecrets struct {
+	// KMSAuthToken is the token to authenticate to the KMS, set as
+	// VAULT_TOKEN in place of signers.kms.auth.token
+	// +optional
+	KMSAuthToken *corev1.SecretKeySelector `json:"kmsAuthToken,omitempty"`
+	// DocDBURL is the url of the MongoDB server with its credentials, set as
+	// MONGO_SERVER_URL for the docdb storage
+	// +optional
+	DocDBURL *corev1.SecretKeySelector `json:"docdbURL,omitempty"`
+	// GrafeasCredentials is the key of a Google service account, mounted
+	// and set as GOOGLE_APPLICATION_CREDENTIALS for the grafeas storage
+	// +optional
+	GrafeasCredentials *corev1.SecretKeySelector `json:"grafeasCredentials,omitempty"`
+	// RegistryCredentials is a Secret of type kubernetes.io/dockerconfigjson,
+	// mounted and set as DOCKER_CONFIG for the oci storage
+	// +optional
+	RegistryCredentials *corev1.LocalObjectReference `json:"registryCredentials,omitempty"`
 }
 
 // TektonChainStatus defines the observed state of TektonChain
@@ -112,6 +173,25 @@ type TektonChainStatus struct {
 	// The current installer set name for TektonChain
 	// +optional
 	TektonInstallerSet string `json:"tektonInstallerSet,omitempty"`
+
+	// SigningKeys reports the rotation of the generated signing keys
+	// +optional
+	SigningKeys *SigningKeysStatus `json:"signingKeys,omitempty"`
+}
+
+// SigningKeysStatus defines the observed state of the generated signing keys
+type SigningKeysStatus struct {
+	// LastRotationTime is the time the current key pair was generated
+	// +optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+	// NextRotationTime is the time the current key pair is replaced, if
+	// spec.signingKeys.rotationPeriod is set
+	// +optional
+	NextRotationTime *metav1.Time `json:"nextRotationTime,omitempty"`
+	// PublicKeys is the number of public keys published for verifiers,
+	// the current one and the ones in their grace period
+	// +optional
+	PublicKeys int `json:"publicKeys,omitempty"`
 }
 
 // TektonChainList contains a list of TektonChain
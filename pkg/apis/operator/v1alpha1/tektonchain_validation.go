@@ -19,8 +19,11 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/pkg/apis"
 )
@@ -42,18 +45,27 @@ func (tc *TektonChain) Validate(ctx context.Context) (errs *apis.FieldError) {
 		errs = errs.Also(apis.ErrMissingField("spec.targetNamespace"))
 	}
 
+	errs = errs.Also(tc.Spec.Options.validate("spec.options"))
+	errs = errs.Also(tc.Spec.SigningKeys.validate("spec.signingKeys"))
+
 	return errs.Also(tc.Spec.ValidateChainConfig("spec"))
 }
 
-func (tcs *TektonChainSpec) ValidateChainConfig(path string) (errs *apis.FieldError) {
-	if tcs.ArtifactsTaskRunFormat != "" {
-		if tcs.ArtifactsTaskRunFormat != "tekton" && tcs.ArtifactsTaskRunFormat != "in-toto" {
-			errs = errs.Also(apis.ErrInvalidValue(tcs.ArtifactsTaskRunFormat, path+".artifacts.taskrun.format"))
+func (tcs *TektonChainSpec) ValidateChainConfig(path string) *apis.FieldError {
+	return tcs.Chain.validate(path)
+}
+
+// validate checks the values of the Chains config, then the combinations of
+// storages, formats and signers
+func (c Chain) validate(path string) (errs *apis.FieldError) {
+	if c.ArtifactsTaskRunFormat != "" {
+		if c.ArtifactsTaskRunFormat != "tekton" && c.ArtifactsTaskRunFormat != "in-toto" {
+			errs = errs.Also(apis.ErrInvalidValue(c.ArtifactsTaskRunFormat, path+".artifacts.taskrun.format"))
 		}
 	}
 
-	if tcs.ArtifactsTaskRunStorage != "" {
-		input := strings.Split(tcs.ArtifactsTaskRunStorage, ",")
+	if c.ArtifactsTaskRunStorage != "" {
+		input := strings.Split(c.ArtifactsTaskRunStorage, ",")
 		for i, v := range input {
 			input[i] = strings.TrimSpace(v)
 			if !allowedArtifactsStorage.Has(input[i]) {
@@ -62,20 +74,20 @@ func (tcs *TektonChainSpec) ValidateChainConfig(path string) (errs *apis.FieldEr
 		}
 	}
 
-	if tcs.ArtifactsTaskRunSigner != "" {
-		if tcs.ArtifactsTaskRunSigner != "x509" && tcs.ArtifactsTaskRunSigner != "kms" {
-			errs = errs.Also(apis.ErrInvalidValue(tcs.ArtifactsTaskRunSigner, path+".artifacts.taskrun.signer"))
+	if c.ArtifactsTaskRunSigner != "" {
+		if c.ArtifactsTaskRunSigner != "x509" && c.ArtifactsTaskRunSigner != "kms" {
+			errs = errs.Also(apis.ErrInvalidValue(c.ArtifactsTaskRunSigner, path+".artifacts.taskrun.signer"))
 		}
 	}
 
-	if tcs.ArtifactsOCIFormat != "" {
-		if tcs.ArtifactsOCIFormat != "simplesigning" {
-			errs = errs.Also(apis.ErrInvalidValue(tcs.ArtifactsOCIFormat, path+".artifacts.oci.format"))
+	if c.ArtifactsOCIFormat != "" {
+		if c.ArtifactsOCIFormat != "simplesigning" {
+			errs = errs.Also(apis.ErrInvalidValue(c.ArtifactsOCIFormat, path+".artifacts.oci.format"))
 		}
 	}
 
-	if tcs.ArtifactsOCIStorage != "" {
-		input := strings.Split(tcs.ArtifactsOCIStorage, ",")
+	if c.ArtifactsOCIStorage != "" {
+		input := strings.Split(c.ArtifactsOCIStorage, ",")
 		for i, v := range input {
 			input[i] = strings.TrimSpace(v)
 			if !allowedArtifactsStorage.Has(input[i]) {
@@ -84,11 +96,200 @@ func (tcs *TektonChainSpec) ValidateChainConfig(path string) (errs *apis.FieldEr
 		}
 	}
 
-	if tcs.ArtifactsOCISigner != "" {
-		if tcs.ArtifactsOCISigner != "x509" && tcs.ArtifactsOCISigner != "kms" {
-			errs = errs.Also(apis.ErrInvalidValue(tcs.ArtifactsOCISigner, path+".artifacts.oci.signer"))
+	if c.ArtifactsOCISigner != "" {
+		if c.ArtifactsOCISigner != "x509" && c.ArtifactsOCISigner != "kms" {
+			errs = errs.Also(apis.ErrInvalidValue(c.ArtifactsOCISigner, path+".artifacts.oci.signer"))
+		}
+	}
+
+	errs = errs.Also(c.validateCombinations(path))
+	return errs.Also(c.validateCredentials(path))
+}
+
+// chainField is a field of the Chains config and whether it is set
+type chainField struct {
+	name string
+	set  bool
+}
+
+// storageNames returns the storages listed in value, def if it is empty
+func storageNames(value, def string) []string {
+	if value == "" {
+		return []string{def}
+	}
+	names := strings.Split(value, ",")
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+	}
+	return names
+}
+
+// validateCombinations checks that the storages and the signers in use have
+// the fields they require, and warns about the fields which Chains ignores
+// as their storage or signer is not in use
+func (c Chain) validateCombinations(path string) (errs *apis.FieldError) {
+	secrets := c.Secrets
+	if secrets == nil {
+		secrets = &ChainSecrets{}
+	}
+	ignored := func(fields []chainField, reason string) {
+		for _, f := range fields {
+			if f.set {
+				errs = errs.Also(apis.ErrGeneric("ignored as "+reason, path+"."+f.name).At(apis.WarningLevel))
+			}
+		}
+	}
+
+	// chains stores TaskRuns in tekton and OCI artifacts in oci by default
+	taskRunStorages := sets.NewString(storageNames(c.ArtifactsTaskRunStorage, "tekton")...)
+	storages := taskRunStorages.Union(sets.NewString(storageNames(c.ArtifactsOCIStorage, "oci")...))
+	for _, storage := range []struct {
+		name     string
+		required []chainField
+		optional []chainField
+	}{{
+		name:     "gcs",
+		required: []chainField{{"storage.gcs.bucket", c.StorageGCSBucket != ""}},
+	}, {
+		name:     "docdb",
+		required: []chainField{{"storage.docdb.url", c.StorageDocDBURL != ""}},
+		optional: []chainField{{"secrets.docdbURL", secrets.DocDBURL != nil}},
+	}, {
+		name:     "grafeas",
+		required: []chainField{{"storage.grafeas.projectid", c.StorageGrafeasProjectID != ""}},
+		optional: []chainField{
+			{"storage.grafeas.noteid", c.StorageGrafeasNoteID != ""},
+			{"secrets.grafeasCredentials", secrets.GrafeasCredentials != nil},
+		},
+	}, {
+		name: "oci",
+		optional: []chainField{
+			{"storage.oci.repository", c.StorageOCIRepository != ""},
+			{"storage.oci.repository.insecure", c.StorageOCIRepositoryInsecure != nil},
+			{"secrets.registryCredentials", secrets.RegistryCredentials != nil},
+		},
+	}} {
+		if !storages.Has(storage.name) {
+			ignored(append(storage.required, storage.optional...), fmt.Sprintf("the %s storage is not used", storage.name))
+			continue
+		}
+		for _, f := range storage.required {
+			if !f.set {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("required by the %s storage", storage.name), path+"."+f.name))
+			}
+		}
+	}
+	if taskRunStorages.Has("grafeas") && c.ArtifactsTaskRunFormat == "tekton" {
+		errs = errs.Also(apis.ErrGeneric("the grafeas storage only stores in-toto attestations of TaskRuns",
+			path+".artifacts.taskrun.format").At(apis.WarningLevel))
+	}
+
+	// chains signs with x509 by default
+	signers := sets.NewString()
+	for _, signer := range []string{c.ArtifactsTaskRunSigner, c.ArtifactsOCISigner} {
+		if signer == "" {
+			signer = "x509"
+		}
+		signers.Insert(signer)
+	}
+	kmsFields := []chainField{
+		{"signers.kms.auth.address", c.KMSAuthAddress != ""},
+		{"signers.kms.auth.token", c.KMSAuthToken != ""},
+		{"signers.kms.auth.oidc.path", c.KMSAuthOIDCPath != ""},
+		{"signers.kms.auth.oidc.role", c.KMSAuthOIDCRole != ""},
+		{"signers.kms.auth.spire.sock", c.KMSAuthSpireSock != ""},
+		{"signers.kms.auth.spire.audience", c.KMSAuthSpireAudience != ""},
+		{"secrets.kmsAuthToken", secrets.KMSAuthToken != nil},
+	}
+	if signers.Has("kms") {
+		if c.KMSRef == "" {
+			errs = errs.Also(apis.ErrGeneric("required by the kms signer", path+".signers.kms.kmsref"))
+		}
+	} else {
+		ignored(append([]chainField{{"signers.kms.kmsref", c.KMSRef != ""}}, kmsFields...), "the kms signer is not used")
+	}
+
+	fulcioEnabled := c.X509SignerFulcioEnabled != nil && *c.X509SignerFulcioEnabled
+	fulcioFields := []chainField{
+		{"signers.x509.fulcio.address", c.X509SignerFulcioAddr != ""},
+		{"signers.x509.fulcio.issuer", c.X509SignerFulcioOIDCIssuer != ""},
+		{"signers.x509.fulcio.provider", c.X509SignerFulcioProvider != ""},
+	}
+	switch {
+	case fulcioEnabled && !signers.Has("x509"):
+		ignored([]chainField{{"signers.x509.fulcio.enabled", true}}, "the x509 signer is not used")
+	case fulcioEnabled && c.X509SignerFulcioAddr == "":
+		errs = errs.Also(apis.ErrGeneric("required when signers.x509.fulcio.enabled is true", path+".signers.x509.fulcio.address"))
+	case !fulcioEnabled:
+		ignored(fulcioFields, "signers.x509.fulcio.enabled is not true")
+	}
+
+	if c.TransparencyConfigEnabled == nil || !*c.TransparencyConfigEnabled {
+		ignored([]chainField{{"transparency.url", c.TransparencyConfigURL != ""}}, "transparency.enabled is not true")
+	}
+	return errs
+}
+
+// validateCredentials warns about the credentials set in clear text in the
+// spec, which have to be referenced from Secrets instead
+func (c Chain) validateCredentials(path string) (errs *apis.FieldError) {
+	if c.KMSAuthToken != "" {
+		if c.Secrets != nil && c.Secrets.KMSAuthToken != nil {
+			errs = errs.Also(apis.ErrMultipleOneOf(path+".signers.kms.auth.token", path+".secrets.kmsAuthToken"))
+		} else {
+			errs = errs.Also(apis.ErrGeneric("the token is stored in clear text, reference a Secret with secrets.kmsAuthToken instead",
+				path+".signers.kms.auth.token").At(apis.WarningLevel))
 		}
 	}
 
+	if u, err := url.Parse(c.StorageDocDBURL); err == nil && u.User != nil {
+		errs = errs.Also(apis.ErrGeneric("the credentials are stored in clear text, reference a Secret with secrets.docdbURL instead",
+			path+".storage.docdb.url").At(apis.WarningLevel))
+	}
+
+	return errs.Also(c.Secrets.validate(path + ".secrets"))
+}
+
+func (cs *ChainSecrets) validate(path string) (errs *apis.FieldError) {
+	if cs == nil {
+		return nil
+	}
+	keyRef := func(field string, ref *corev1.SecretKeySelector) {
+		if ref == nil {
+			return
+		}
+		if ref.Name == "" {
+			errs = errs.Also(apis.ErrMissingField(path + "." + field + ".name"))
+		}
+		if ref.Key == "" {
+			errs = errs.Also(apis.ErrMissingField(path + "." + field + ".key"))
+		}
+	}
+	keyRef("kmsAuthToken", cs.KMSAuthToken)
+	keyRef("docdbURL", cs.DocDBURL)
+	keyRef("grafeasCredentials", cs.GrafeasCredentials)
+	if cs.RegistryCredentials != nil && cs.RegistryCredentials.Name == "" {
+		errs = errs.Also(apis.ErrMissingField(path + ".registryCredentials.name"))
+	}
+	return errs
+}
+
+func (sk *SigningKeys) validate(path string) (errs *apis.FieldError) {
+	if sk == nil {
+		return nil
+	}
+	period := func(field string, d *metav1.Duration) {
+		if d == nil {
+			return
+		}
+		if !sk.Generate {
+			errs = errs.Also(apis.ErrGeneric("requires generate to be true", path+"."+field))
+		}
+		if d.Duration <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(d.Duration.String(), path+"."+field))
+		}
+	}
+	period("rotationPeriod", sk.RotationPeriod)
+	period("gracePeriod", sk.GracePeriod)
 	return errs
 }
@@ -17,6 +17,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/apis"
 )
@@ -90,6 +93,44 @@ func (tcs *TektonConfigStatus) MarkComponentNotReady(msg string) {
 		"Components not in ready state: %s", msg)
 }
 
+// SetComponents sets the status of the components and derives ComponentsReady
+// from it. The transition time of a component is kept while its readiness
+// does not change.
+func (tcs *TektonConfigStatus) SetComponents(components []ComponentStatus) {
+	previous := make(map[string]ComponentStatus, len(tcs.Components))
+	for _, c := range tcs.Components {
+		previous[c.Name] = c
+	}
+
+	var notReady []string
+	for i := range components {
+		c := &components[i]
+		if p, ok := previous[c.Name]; ok && p.Ready == c.Ready && p.LastTransitionTime != nil {
+			c.LastTransitionTime = p.LastTransitionTime
+		}
+		if c.Ready == corev1.ConditionTrue {
+			continue
+		}
+		msg := c.Kind
+		for _, s := range []string{c.Reason, c.Message} {
+			if s != "" {
+				msg += ": " + s
+			}
+		}
+		notReady = append(notReady, msg)
+	}
+	tcs.Components = components
+
+	if len(notReady) == 0 {
+		tcs.MarkComponentsReady()
+		return
+	}
+	configCondSet.Manage(tcs).MarkFalse(
+		ComponentsReady,
+		"ComponentsNotReady",
+		"Components not in ready state: %s", strings.Join(notReady, "; "))
+}
+
 func (tcs *TektonConfigStatus) MarkPostInstallFailed(msg string) {
 	tcs.MarkNotReady("PostReconciliation failed")
 	configCondSet.Manage(tcs).MarkFalse(
@@ -18,8 +18,12 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
 	apistest "knative.dev/pkg/apis/testing"
 )
 
@@ -96,3 +100,44 @@ func TestTektonConfigErrorPath(t *testing.T) {
 	}
 
 }
+
+func TestTektonConfigSetComponents(t *testing.T) {
+	tc := &TektonConfigStatus{}
+	tc.InitializeConditions()
+	tc.MarkPreInstallComplete()
+	tc.MarkPostInstallComplete()
+
+	before := metav1.NewTime(time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC))
+	tc.SetComponents([]ComponentStatus{
+		{Name: ComponentPipeline, Kind: KindTektonPipeline, Ready: corev1.ConditionTrue, LastTransitionTime: &before},
+		{Name: ComponentTrigger, Kind: KindTektonTrigger, Ready: corev1.ConditionUnknown, LastTransitionTime: &before},
+	})
+	apistest.CheckConditionFailed(tc, ComponentsReady, t)
+	if got, want := tc.GetCondition(apis.ConditionReady).Message, "Components not in ready state: TektonTrigger"; got != want {
+		t.Errorf("Ready message = %q, want %q", got, want)
+	}
+
+	after := metav1.NewTime(before.Add(time.Hour))
+	tc.SetComponents([]ComponentStatus{
+		{Name: ComponentPipeline, Kind: KindTektonPipeline, Ready: corev1.ConditionFalse, LastTransitionTime: &after, Reason: "Error", Message: "webhook not ready"},
+		{Name: ComponentTrigger, Kind: KindTektonTrigger, Ready: corev1.ConditionUnknown, LastTransitionTime: &after},
+	})
+	if got, want := tc.GetCondition(apis.ConditionReady).Message, "Components not in ready state: TektonPipeline: Error: webhook not ready; TektonTrigger"; got != want {
+		t.Errorf("Ready message = %q, want %q", got, want)
+	}
+	// the transition time only changes with the readiness of the component
+	if got := tc.Components[0].LastTransitionTime; !got.Equal(&after) {
+		t.Errorf("pipeline transition time = %v, want %v", got, after)
+	}
+	if got := tc.Components[1].LastTransitionTime; !got.Equal(&before) {
+		t.Errorf("trigger transition time = %v, want %v", got, before)
+	}
+
+	tc.SetComponents([]ComponentStatus{
+		{Name: ComponentPipeline, Kind: KindTektonPipeline, Ready: corev1.ConditionTrue, LastTransitionTime: &after},
+	})
+	apistest.CheckConditionSucceeded(tc, ComponentsReady, t)
+	if ready := tc.IsReady(); !ready {
+		t.Errorf("tc.IsReady() = %v, want true", ready)
+	}
+}
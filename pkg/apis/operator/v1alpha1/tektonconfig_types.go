@@ -61,6 +61,94 @@ type Prune struct {
 	KeepSince *uint `json:"keep-since,omitempty"`
 	// How frequent pruning should happen
 	Schedule string `json:"schedule,omitempty"`
+	// Selector restricts pruning to the resources matching the label selector
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// GroupBy sets how resources are counted for keep, either per
+	// `namespace` (default) or per `name` of their Pipeline or Task
+	// +optional
+	GroupBy string `json:"groupBy,omitempty"`
+	// Statuses restricts pruning to the resources which completed with one
+	// of the statuses (succeeded, failed, cancelled)
+	// +optional
+	Statuses []string `json:"statuses,omitempty"`
+	// Succeeded overrides keep/keep-since for succeeded resources
+	// +optional
+	Succeeded *PruneRetention `json:"succeeded,omitempty"`
+	// Failed overrides keep/keep-since for failed resources
+	// +optional
+	Failed *PruneRetention `json:"failed,omitempty"`
+	// Cancelled overrides keep/keep-since for cancelled resources
+	// +optional
+	Cancelled *PruneRetention `json:"cancelled,omitempty"`
+	// Archive stores the resources in a sink before they are deleted,
+	// resources which fail to be archived are not deleted
+	// +optional
+	Archive *PruneArchive `json:"archive,omitempty"`
+}
+
+// PruneArchive defines where pruned resources are archived
+type PruneArchive struct {
+	// Sink is where the resources are archived: `pvc`, `s3` or `results`
+	Sink string `json:"sink"`
+	// Logs archives the logs of the steps along with the resources,
+	// not supported by the results sink which archives logs itself
+	// +optional
+	Logs bool `json:"logs,omitempty"`
+	// S3 configures the s3 sink
+	// +optional
+	S3 *PruneArchiveS3 `json:"s3,omitempty"`
+}
+
+// PruneArchiveS3 defines an S3 compatible bucket to archive resources in
+type PruneArchiveS3 struct {
+	// Endpoint is the URL of the S3 API, e.g. https://s3.us-east-1.amazonaws.com
+	Endpoint string `json:"endpoint"`
+	// Bucket is the name of the bucket, addressed path style
+	Bucket string `json:"bucket"`
+	// Region is used to sign the requests, us-east-1 by default
+	// +optional
+	Region string `json:"region,omitempty"`
+	// Prefix is prepended to the keys of the archived objects
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// SecretName is the Secret holding the AWS_ACCESS_KEY_ID and
+	// AWS_SECRET_ACCESS_KEY, in the target namespace
+	SecretName string `json:"secretName"`
+}
+
+// PruneRetention defines how long resources with a given status are kept
+type PruneRetention struct {
+	// The number of resource to keep
+	// +optional
+	Keep *uint `json:"keep,omitempty"`
+	// KeepSince keeps the resources younger than the specified value
+	// Its value is taken in minutes
+	// +optional
+	KeepSince *uint `json:"keep-since,omitempty"`
+}
+
+// Retention returns the retention of the resources which completed with
+// status, and whether it is specific to that status
+func (p Prune) Retention(status string) (PruneRetention, bool) {
+	var r *PruneRetention
+	switch status {
+	case PruneStatusSucceeded:
+		r = p.Succeeded
+	case PruneStatusFailed:
+		r = p.Failed
+	case PruneStatusCancelled:
+		r = p.Cancelled
+	}
+	if r != nil {
+		return *r, true
+	}
+	return PruneRetention{Keep: p.Keep, KeepSince: p.KeepSince}, false
+}
+
+// Prunes returns whether resources which completed with status are pruned
+func (p Prune) Prunes(status string) bool {
+	return len(p.Statuses) == 0 || isValueInArray(p.Statuses, status)
 }
 
 func (p Prune) IsEmpty() bool {
@@ -69,7 +157,12 @@ func (p Prune) IsEmpty() bool {
 
 // TektonConfigSpec defines the desired state of TektonConfig
 type TektonConfigSpec struct {
+	// Profile is the preset of components to install: lite, basic or all
 	Profile string `json:"profile,omitempty"`
+	// Components enables or disables components on top of the profile,
+	// keyed by component name with the value enabled or disabled
+	// +optional
+	Components map[string]string `json:"components,omitempty"`
 	// Config holds the configuration for resources created by TektonConfig
 	// +optional
 	Config Config `json:"config,omitempty"`
@@ -92,9 +185,40 @@ type TektonConfigSpec struct {
 	// Dashboard holds the customizable options for dashboards component
 	// +optional
 	Dashboard Dashboard `json:"dashboard,omitempty"`
+	// Chain holds the customizable options for chains component
+	// +optional
+	Chain ChainConfig `json:"chain,omitempty"`
+	// Result holds the customizable options for results component
+	// +optional
+	Result ResultConfig `json:"result,omitempty"`
 	// Params is the list of params passed for all platforms
 	// +optional
 	Params []Param `json:"params,omitempty"`
+	// Options holds the overrides of resources of the components created by TektonConfig
+	// +optional
+	Options *AdditionalOptions `json:"options,omitempty"`
+	// Upgrade configures how Pipelines and Triggers are upgraded
+	// +optional
+	Upgrade *Upgrade `json:"upgrade,omitempty"`
+}
+
+// ComponentEnabled returns whether a component is installed, as set in
+// spec.components or else by the profile. Chain and Result are also
+// enabled by their own section with the basic and all profiles.
+func (tcs TektonConfigSpec) ComponentEnabled(name string) bool {
+	switch tcs.Components[name] {
+	case ComponentEnabled:
+		return true
+	case ComponentDisabled:
+		return false
+	}
+	switch name {
+	case ComponentChain:
+		return tcs.Chain.Enable && tcs.Profile != ProfileLite
+	case ComponentResult:
+		return tcs.Result.Enable && tcs.Profile != ProfileLite
+	}
+	return isValueInArray(ProfileComponents[tcs.Profile], name)
 }
 
 // TektonConfigStatus defines the observed state of TektonConfig
@@ -112,6 +236,69 @@ type TektonConfigStatus struct {
 	// The current installer set name
 	// +optional
 	TektonInstallerSet map[string]string `json:"tektonInstallerSets,omitempty"`
+
+	// Pruner holds the outcome of the latest pruner runs
+	// +optional
+	Pruner *PrunerStatus `json:"pruner,omitempty"`
+
+	// Components holds the health of the components installed by TektonConfig,
+	// ComponentsReady is true once all of them are ready
+	// +optional
+	Components []ComponentStatus `json:"components,omitempty"`
+}
+
+// ComponentStatus defines the observed state of a component installed by TektonConfig
+type ComponentStatus struct {
+	// Name of the component, as in spec.components
+	Name string `json:"name"`
+	// Kind of the CR of the component
+	Kind string `json:"kind"`
+	// DesiredVersion is the version the operator installs
+	// +optional
+	DesiredVersion string `json:"desiredVersion,omitempty"`
+	// Version is the version reported by the CR of the component
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Ready is the status of the Ready condition of the CR, True, False or Unknown
+	Ready corev1.ConditionStatus `json:"ready"`
+	// LastTransitionTime is the last time Ready changed
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason of the Ready condition when the component is not ready
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message of the Ready condition when the component is not ready
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PrunerStatus defines the observed state of the pruner
+type PrunerStatus struct {
+	// Namespaces holds the outcome of the latest pruner run per namespace
+	// +optional
+	Namespaces []NamespacePruneStatus `json:"namespaces,omitempty"`
+}
+
+// NamespacePruneStatus holds the outcome of the latest pruner run in a namespace
+type NamespacePruneStatus struct {
+	Namespace string `json:"namespace"`
+	// LastPruneTime is the time the latest pruner run finished
+	// +optional
+	LastPruneTime *metav1.Time `json:"lastPruneTime,omitempty"`
+	// Deleted holds the number of resources deleted by the latest run,
+	// keyed by resource (pipelinerun/taskrun)
+	// +optional
+	Deleted map[string]int32 `json:"deleted,omitempty"`
+	// NotArchived holds the number of expired resources kept by the latest
+	// run because they are not archived yet, keyed by resource
+	// +optional
+	NotArchived map[string]int32 `json:"notArchived,omitempty"`
+	// Errors is the number of failures in the latest run
+	// +optional
+	Errors int32 `json:"errors,omitempty"`
+	// Error holds the last failure of the latest run
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 func (in *TektonConfigStatus) MarkInstallerSetReady() {
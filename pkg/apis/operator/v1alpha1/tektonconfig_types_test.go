@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestComponentEnabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    TektonConfigSpec
+		enabled []string
+	}{{
+		name:    "lite profile",
+		spec:    TektonConfigSpec{Profile: ProfileLite},
+		enabled: []string{ComponentPipeline},
+	}, {
+		name:    "basic profile",
+		spec:    TektonConfigSpec{Profile: ProfileBasic},
+		enabled: []string{ComponentPipeline, ComponentTrigger},
+	}, {
+		name:    "all profile",
+		spec:    TektonConfigSpec{Profile: ProfileAll},
+		enabled: []string{ComponentPipeline, ComponentTrigger, ComponentDashboard, ComponentAddon},
+	}, {
+		name:    "chain and result flags",
+		spec:    TektonConfigSpec{Profile: ProfileBasic, Chain: ChainConfig{Enable: true}, Result: ResultConfig{Enable: true}},
+		enabled: []string{ComponentPipeline, ComponentTrigger, ComponentChain, ComponentResult},
+	}, {
+		name:    "chain flag ignored with the lite profile",
+		spec:    TektonConfigSpec{Profile: ProfileLite, Chain: ChainConfig{Enable: true}},
+		enabled: []string{ComponentPipeline},
+	}, {
+		name: "components override the profile",
+		spec: TektonConfigSpec{
+			Profile: ProfileAll,
+			Chain:   ChainConfig{Enable: true},
+			Components: map[string]string{
+				ComponentTrigger: ComponentDisabled,
+				ComponentChain:   ComponentDisabled,
+				ComponentResult:  ComponentEnabled,
+			},
+		},
+		enabled: []string{ComponentPipeline, ComponentResult, ComponentDashboard, ComponentAddon},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var enabled []string
+			for _, name := range Components {
+				if test.spec.ComponentEnabled(name) {
+					enabled = append(enabled, name)
+				}
+			}
+			assert.DeepEqual(t, enabled, test.enabled)
+		})
+	}
+}
@@ -19,7 +19,10 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"net/url"
 
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
 )
 
@@ -43,9 +46,17 @@ func (tc *TektonConfig) Validate(ctx context.Context) (errs *apis.FieldError) {
 			errs = errs.Also(apis.ErrInvalidValue(tc.Spec.Profile, "spec.profile"))
 		}
 	}
+	errs = errs.Also(tc.Spec.validateComponents("spec.components"))
 
 	if !tc.Spec.Pruner.IsEmpty() {
-		errs = errs.Also(tc.Spec.Pruner.validate())
+		errs = errs.Also(tc.Spec.Pruner.validate("spec.pruner"))
+		if tc.Spec.Pruner.Schedule == "" {
+			errs = errs.Also(apis.ErrMissingField("spec.pruner.schedule"))
+		}
+		// the runs would never be recorded and so never deleted
+		if a := tc.Spec.Pruner.Archive; a != nil && a.Sink == PruneArchiveSinkResults && !tc.Spec.ComponentEnabled(ComponentResult) {
+			errs = errs.Also(apis.ErrGeneric("the results sink requires Tekton Results to be enabled", "spec.pruner.archive.sink"))
+		}
 	}
 
 	if !tc.Spec.Addon.IsEmpty() {
@@ -57,38 +68,175 @@ func (tc *TektonConfig) Validate(ctx context.Context) (errs *apis.FieldError) {
 	}
 
 	errs = errs.Also(tc.Spec.Pipeline.PipelineProperties.validate("spec.pipeline"))
+	errs = errs.Also(tc.Spec.Pipeline.Rollback.validate("spec.pipeline.rollback"))
+	errs = errs.Also(tc.Spec.Trigger.Rollback.validate("spec.trigger.rollback"))
+	errs = errs.Also(tc.Spec.Pipeline.InstallerSet.validate("spec.pipeline.installerSet"))
+	errs = errs.Also(tc.Spec.Trigger.InstallerSet.validate("spec.trigger.installerSet"))
+	errs = errs.Also(tc.Spec.Upgrade.validate("spec.upgrade"))
+	// the config of a disabled TektonChain is not applied, it may be left incomplete
+	if tc.Spec.ComponentEnabled(ComponentChain) {
+		errs = errs.Also(tc.Spec.Chain.Chain.validate("spec.chain"))
+	}
+	errs = errs.Also(tc.Spec.Chain.SigningKeys.validate("spec.chain.signingKeys"))
+	errs = errs.Also(tc.Spec.Result.ResultsAPIProperties.validate("spec.result"))
+	if apis.IsInUpdate(ctx) {
+		if old, ok := apis.GetBaseline(ctx).(*TektonConfig); ok && old != nil {
+			errs = errs.Also(tc.Spec.Result.DB.validateUpdate(old.Spec.Result.DB, "spec.result.db"))
+		}
+	}
+	errs = errs.Also(tc.Spec.Options.validate("spec.options"))
+	errs = errs.Also(validateVersion(ctx, KindTektonPipeline, tc.Spec.Pipeline.Version, "spec.pipeline.version"))
+	errs = errs.Also(validateVersion(ctx, KindTektonTrigger, tc.Spec.Trigger.Version, "spec.trigger.version"))
 
 	return errs.Also(tc.Spec.Trigger.TriggersProperties.validate("spec.trigger"))
 }
 
-func (p Prune) validate() *apis.FieldError {
+func (tcs TektonConfigSpec) validateComponents(path string) *apis.FieldError {
+	var errs *apis.FieldError
+
+	for name, state := range tcs.Components {
+		if !isValueInArray(Components, name) {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, path))
+			continue
+		}
+		if !isValueInArray(ComponentStates, state) {
+			errs = errs.Also(apis.ErrInvalidValue(state, path+"."+name))
+		}
+	}
+
+	// every other component runs on top of Pipelines
+	if tcs.Components[ComponentPipeline] == ComponentDisabled {
+		errs = errs.Also(apis.ErrGeneric("pipeline cannot be disabled, the other components depend on it", path+"."+ComponentPipeline))
+	}
+
+	// the dashboard and results are only shipped on Kubernetes, the addon on OpenShift
+	unsupported, platform := []string{ComponentDashboard, ComponentResult}, "OpenShift"
+	if !IsOpenShiftPlatform() {
+		unsupported, platform = []string{ComponentAddon}, "Kubernetes"
+	}
+	for _, name := range unsupported {
+		if tcs.Components[name] == ComponentEnabled {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("%s is not supported on %s", name, platform), path+"."+name))
+		}
+	}
+	if tcs.Result.Enable && IsOpenShiftPlatform() {
+		errs = errs.Also(apis.ErrGeneric("TektonResult is not supported on OpenShift", "spec.result.enable"))
+	}
+	return errs
+}
+
+func (p Prune) validate(path string) *apis.FieldError {
 	var errs *apis.FieldError
 
 	if len(p.Resources) != 0 {
 		for i, r := range p.Resources {
 			if !isValueInArray(PruningResource, r) {
-				errs = errs.Also(apis.ErrInvalidArrayValue(r, "spec.pruner.resources", i))
+				errs = errs.Also(apis.ErrInvalidArrayValue(r, path+".resources", i))
 			}
 		}
 	} else {
-		errs = errs.Also(apis.ErrMissingField("spec.pruner.resources"))
+		errs = errs.Also(apis.ErrMissingField(path + ".resources"))
+	}
+
+	for i, s := range p.Statuses {
+		if !isValueInArray(PruneStatuses, s) {
+			errs = errs.Also(apis.ErrInvalidArrayValue(s, path+".statuses", i))
+		}
+	}
+
+	// keep/keep-since may be left out when every pruned status has its own
+	withoutRetention := false
+	for _, s := range PruneStatuses {
+		retention, own := p.Retention(s)
+		if !own {
+			withoutRetention = withoutRetention || p.Prunes(s)
+			continue
+		}
+		statusPath := path + "." + s
+		if !p.Prunes(s) {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("%s resources are not pruned as per %s.statuses", s, path), statusPath))
+		}
+		errs = errs.Also(retention.validate(statusPath, true))
 	}
+	errs = errs.Also(PruneRetention{Keep: p.Keep, KeepSince: p.KeepSince}.validate(path, withoutRetention))
 
-	if p.Keep != nil && p.KeepSince != nil {
-		errs = errs.Also(apis.ErrMultipleOneOf("spec.pruner.keep", "spec.pruner.keep-since"))
+	// the schedule is parsed the way CronJobs do, descriptors like @daily included
+	if p.Schedule != "" {
+		if _, err := cron.ParseStandard(p.Schedule); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(p.Schedule, path+".schedule", err.Error()))
+		}
+	}
+
+	if p.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(p.Selector); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(err.Error(), path+".selector"))
+		}
+	}
+
+	if p.GroupBy != "" && !isValueInArray(PruneGroupBy, p.GroupBy) {
+		errs = errs.Also(apis.ErrInvalidValue(p.GroupBy, path+".groupBy"))
+	} else if p.GroupBy == PruneGroupByName {
+		// keep-since does not count resources, grouping them makes no difference
+		for _, s := range PruneStatuses {
+			if retention, _ := p.Retention(s); p.Prunes(s) && retention.Keep == nil {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("groupBy %q requires keep, %s resources are kept with keep-since", p.GroupBy, s), path+".groupBy"))
+				break
+			}
+		}
 	}
-	if p.Keep == nil && p.KeepSince == nil {
-		errs = errs.Also(apis.ErrMissingOneOf("spec.pruner.keep", "spec.pruner.keep-since"))
-	} else if p.Keep != nil && *p.Keep == 0 {
-		errs = errs.Also(apis.ErrInvalidValue(*p.Keep, "spec.pruner.keep"))
-	} else if p.KeepSince != nil && *p.KeepSince == 0 {
-		errs = errs.Also(apis.ErrInvalidValue(*p.Keep, "spec.pruner.keep-since"))
+
+	return errs.Also(p.Archive.validate(path + ".archive"))
+}
+
+func (a *PruneArchive) validate(path string) *apis.FieldError {
+	var errs *apis.FieldError
+	if a == nil {
+		return errs
 	}
 
-	if p.Schedule == "" {
-		errs = errs.Also(apis.ErrMissingField("spec.pruner.schedule"))
+	if !isValueInArray(PruneArchiveSinks, a.Sink) {
+		errs = errs.Also(apis.ErrInvalidValue(a.Sink, path+".sink"))
+	}
+	if a.Logs && a.Sink == PruneArchiveSinkResults {
+		errs = errs.Also(apis.ErrGeneric("logs are archived by Tekton Results itself", path+".logs"))
 	}
 
+	if a.Sink != PruneArchiveSinkS3 {
+		if a.S3 != nil {
+			errs = errs.Also(apis.ErrDisallowedFields(path + ".s3"))
+		}
+		return errs
+	}
+	if a.S3 == nil {
+		return errs.Also(apis.ErrMissingField(path + ".s3"))
+	}
+	if u, err := url.Parse(a.S3.Endpoint); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		errs = errs.Also(apis.ErrInvalidValue(a.S3.Endpoint, path+".s3.endpoint"))
+	}
+	if a.S3.Bucket == "" {
+		errs = errs.Also(apis.ErrMissingField(path + ".s3.bucket"))
+	}
+	if a.S3.SecretName == "" {
+		errs = errs.Also(apis.ErrMissingField(path + ".s3.secretName"))
+	}
+	return errs
+}
+
+func (r PruneRetention) validate(path string, required bool) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if r.Keep != nil && r.KeepSince != nil {
+		errs = errs.Also(apis.ErrMultipleOneOf(path+".keep", path+".keep-since"))
+	}
+	if r.Keep == nil && r.KeepSince == nil {
+		if required {
+			errs = errs.Also(apis.ErrMissingOneOf(path+".keep", path+".keep-since"))
+		}
+	} else if r.Keep != nil && *r.Keep == 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*r.Keep, path+".keep"))
+	} else if r.KeepSince != nil && *r.KeepSince == 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*r.KeepSince, path+".keep-since"))
+	}
 	return errs
 }
 
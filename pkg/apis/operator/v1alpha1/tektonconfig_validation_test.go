@@ -124,6 +124,72 @@ func Test_ValidateTektonConfig_MissingKeepKeepsinceSchedule(t *testing.T) {
 	assert.Equal(t, "expected exactly one, got neither: spec.pruner.keep, spec.pruner.keep-since\nmissing field(s): spec.pruner.schedule", err.Error())
 }
 
+func Test_ValidateTektonConfig_PrunerRetentionPerStatus(t *testing.T) {
+	keep := uint(2)
+	tc := &TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config",
+			Namespace: "namespace",
+		},
+		Spec: TektonConfigSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+			Profile: "all",
+			Pruner: Prune{
+				Resources: []string{"pipelinerun"},
+				Schedule:  "0 * * * *",
+				Statuses:  []string{"succeeded", "failed"},
+				Succeeded: &PruneRetention{Keep: &keep},
+				Failed:    &PruneRetention{Keep: &keep},
+				GroupBy:   "name",
+				Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key: "keep", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"true"},
+				}}},
+			},
+		},
+	}
+
+	err := tc.Validate(context.TODO())
+	assert.Assert(t, err == nil, "unexpected error: %v", err)
+}
+
+func Test_ValidateTektonConfig_PrunerConflicts(t *testing.T) {
+	keep := uint(2)
+	keepSince := uint(60)
+	tc := &TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config",
+			Namespace: "namespace",
+		},
+		Spec: TektonConfigSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+			Profile: "all",
+			Pruner: Prune{
+				Resources: []string{"pipelinerun"},
+				Schedule:  "0 * * * *",
+				KeepSince: &keepSince,
+				Statuses:  []string{"failed", "skipped"},
+				Succeeded: &PruneRetention{Keep: &keep, KeepSince: &keepSince},
+				Failed:    &PruneRetention{KeepSince: &keepSince},
+				GroupBy:   "name",
+				Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key: "keep", Operator: "Unknown",
+				}}},
+			},
+		},
+	}
+
+	err := tc.Validate(context.TODO())
+	assert.Equal(t, "expected exactly one, got both: spec.pruner.succeeded.keep, spec.pruner.succeeded.keep-since\n"+
+		"groupBy \"name\" requires keep, failed resources are kept with keep-since: spec.pruner.groupBy\n"+
+		"invalid value: \"Unknown\" is not a valid pod selector operator: spec.pruner.selector\n"+
+		"invalid value: skipped: spec.pruner.statuses[1]\n"+
+		"succeeded resources are not pruned as per spec.pruner.statuses: spec.pruner.succeeded", err.Error())
+}
+
 func Test_ValidateTektonConfig_MissingSchedule(t *testing.T) {
 	keep := uint(2)
 	tc := &TektonConfig{
@@ -147,6 +213,101 @@ func Test_ValidateTektonConfig_MissingSchedule(t *testing.T) {
 	assert.Equal(t, "missing field(s): spec.pruner.schedule", err.Error())
 }
 
+func Test_ValidateTektonConfig_PrunerSchedule(t *testing.T) {
+	keep := uint(2)
+	tc := &TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "config",
+		},
+		Spec: TektonConfigSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+			Profile: "all",
+			Pruner: Prune{
+				Keep:      &keep,
+				Resources: []string{"taskrun"},
+			},
+		},
+	}
+
+	for _, schedule := range []string{"*/15 * * * *", "0 2 * * SUN", "@daily", "@every 6h"} {
+		tc.Spec.Pruner.Schedule = schedule
+		assert.Assert(t, tc.Validate(context.TODO()) == nil, schedule)
+	}
+
+	tc.Spec.Pruner.Schedule = "0 25 * * *"
+	err := tc.Validate(context.TODO())
+	assert.Equal(t, `invalid value: 0 25 * * *: spec.pruner.schedule
+end of range (25) above maximum (23): 25`, err.Error())
+}
+
+func Test_ValidateTektonConfig_PrunerArchive(t *testing.T) {
+	keep := uint(2)
+	tc := &TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config",
+			Namespace: "namespace",
+		},
+		Spec: TektonConfigSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+			Profile: "all",
+			Pruner: Prune{
+				Keep:      &keep,
+				Resources: []string{"taskrun"},
+				Schedule:  "0 * * * *",
+				Archive: &PruneArchive{
+					Sink: "s3",
+					Logs: true,
+					S3:   &PruneArchiveS3{Endpoint: "minio:9000", Bucket: "runs"},
+				},
+			},
+		},
+	}
+
+	err := tc.Validate(context.TODO())
+	assert.Equal(t, "invalid value: minio:9000: spec.pruner.archive.s3.endpoint\n"+
+		"missing field(s): spec.pruner.archive.s3.secretName", err.Error())
+
+	tc.Spec.Pruner.Archive = &PruneArchive{Sink: "results", Logs: true, S3: &PruneArchiveS3{}}
+	err = tc.Validate(context.TODO())
+	assert.Equal(t, "logs are archived by Tekton Results itself: spec.pruner.archive.logs\n"+
+		"must not set the field(s): spec.pruner.archive.s3\n"+
+		"the results sink requires Tekton Results to be enabled: spec.pruner.archive.sink", err.Error())
+
+	tc.Spec.Result.Enable = true
+	tc.Spec.Pruner.Archive = &PruneArchive{Sink: "results"}
+	err = tc.Validate(context.TODO())
+	assert.Equal(t, "", err.Error())
+
+	tc.Spec.Pruner.Archive = &PruneArchive{Sink: "pvc", Logs: true}
+	err = tc.Validate(context.TODO())
+	assert.Equal(t, "", err.Error())
+}
+
+func Test_ValidateTektonConfig_ResultOnOpenShift(t *testing.T) {
+	t.Setenv("PLATFORM", "openshift")
+	tc := &TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config",
+			Namespace: "namespace",
+		},
+		Spec: TektonConfigSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+			Profile: "all",
+			Chain:   ChainConfig{Enable: true},
+			Result:  ResultConfig{Enable: true},
+		},
+	}
+
+	err := tc.Validate(context.TODO())
+	assert.Equal(t, "TektonResult is not supported on OpenShift: spec.result.enable", err.Error())
+}
+
 func Test_ValidateTektonConfig_InvalidAddonParam(t *testing.T) {
 
 	tc := &TektonConfig{
@@ -248,3 +409,104 @@ func Test_ValidateTektonConfig_InvalidTriggerProperties(t *testing.T) {
 	err := tc.Validate(context.TODO())
 	assert.Equal(t, "invalid value: test: spec.trigger.enable-api-fields", err.Error())
 }
+
+func Test_ValidateTektonConfig_InvalidUpgradeStrategy(t *testing.T) {
+
+	tc := &TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config",
+			Namespace: "namespace",
+		},
+		Spec: TektonConfigSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+			Profile: "all",
+			Upgrade: &Upgrade{
+				Strategy: "canary",
+			},
+		},
+	}
+
+	err := tc.Validate(context.TODO())
+	assert.Equal(t, "invalid value: canary: spec.upgrade.strategy", err.Error())
+
+	tc.Spec.Upgrade.Strategy = UpgradeStrategyStaged
+	if err := tc.Validate(context.TODO()); err != nil {
+		t.Errorf("ValidateTektonConfig.Validate() with staged upgrade expected no error, but got one, ValidateTektonConfig: %v", err)
+	}
+}
+
+func Test_ValidateTektonConfig_Components(t *testing.T) {
+	tc := &TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config",
+			Namespace: "namespace",
+		},
+		Spec: TektonConfigSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+			Profile: "basic",
+			Components: map[string]string{
+				"trigger":   "disabled",
+				"chain":     "enabled",
+				"dashboard": "enabled",
+			},
+		},
+	}
+	err := tc.Validate(context.TODO())
+	assert.Assert(t, err == nil, err)
+
+	tc.Spec.Components = map[string]string{
+		"pipeline": "disabled",
+		"trigger":  "on",
+		"operator": "enabled",
+		"addon":    "enabled",
+	}
+	err = tc.Validate(context.TODO())
+	assert.Equal(t, `addon is not supported on Kubernetes: spec.components.addon
+invalid key name "operator": spec.components
+invalid value: on: spec.components.trigger
+pipeline cannot be disabled, the other components depend on it: spec.components.pipeline`, err.Error())
+
+	t.Setenv("PLATFORM", "openshift")
+	tc.Spec.Components = map[string]string{"addon": "enabled", "dashboard": "enabled"}
+	err = tc.Validate(context.TODO())
+	assert.Equal(t, "dashboard is not supported on OpenShift: spec.components.dashboard", err.Error())
+}
+
+func Test_ValidateTektonConfig_Chain(t *testing.T) {
+	tc := &TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config",
+			Namespace: "namespace",
+		},
+		Spec: TektonConfigSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+			Profile: "all",
+			Chain: ChainConfig{
+				Enable: true,
+				Chain: Chain{
+					ArtifactsOCIStorage: "gcs",
+					ArtifactsOCISigner:  "pgp",
+				},
+			},
+		},
+	}
+	err := tc.Validate(context.TODO())
+	assert.Equal(t, "invalid value: pgp: spec.chain.artifacts.oci.signer\n"+
+		"required by the gcs storage: spec.chain.storage.gcs.bucket", err.Error())
+
+	// the config of a disabled TektonChain is not validated
+	tc.Spec.Chain.Enable = false
+	err = tc.Validate(context.TODO())
+	assert.Equal(t, "", err.Error())
+
+	tc.Spec.Chain.Enable = true
+	tc.Spec.Components = map[string]string{ComponentChain: ComponentDisabled}
+	err = tc.Validate(context.TODO())
+	assert.Equal(t, "", err.Error())
+}
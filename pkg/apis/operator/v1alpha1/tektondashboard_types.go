@@ -56,6 +56,9 @@ type TektonDashboardSpec struct {
 	// Config holds the configuration for resources created by TektonDashboard
 	// +optional
 	Config Config `json:"config,omitempty"`
+	// Options holds the overrides of resources created by TektonDashboard
+	// +optional
+	Options *AdditionalOptions `json:"options,omitempty"`
 }
 
 // TektonDashboardStatus defines the observed state of TektonDashboard
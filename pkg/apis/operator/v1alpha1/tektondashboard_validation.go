@@ -38,7 +38,7 @@ func (td *TektonDashboard) Validate(ctx context.Context) (errs *apis.FieldError)
 		errs = errs.Also(apis.ErrMissingField("spec.targetNamespace"))
 	}
 
-	return errs
+	return errs.Also(td.Spec.Options.validate("spec.options"))
 }
 
 func (td *TektonDashboard) SetDefaults(ctx context.Context) {
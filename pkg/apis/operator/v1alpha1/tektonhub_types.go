@@ -44,6 +44,9 @@ type TektonHubSpec struct {
 	Hub        `json:",inline"`
 	Db         DbSpec  `json:"db,omitempty"`
 	Api        ApiSpec `json:"api,omitempty"`
+	// Options holds the overrides of resources created by TektonHub
+	// +optional
+	Options *AdditionalOptions `json:"options,omitempty"`
 }
 
 // Hub defines the field to customize Hub component
@@ -48,6 +48,7 @@ func (th *TektonHub) Validate(ctx context.Context) (errs *apis.FieldError) {
 	}
 
 	errs = errs.Also(th.Spec.Db.validate("spec.db"))
+	errs = errs.Also(th.Spec.Options.validate("spec.options"))
 
 	return errs.Also(th.Spec.Api.validate("spec.api"))
 
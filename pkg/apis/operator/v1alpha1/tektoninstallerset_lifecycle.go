@@ -29,6 +29,11 @@ const (
 	WebhookReady         apis.ConditionType = "WebhooksReady"
 	ControllerReady      apis.ConditionType = "ControllersReady"
 	AllDeploymentsReady  apis.ConditionType = "AllDeploymentsReady"
+	WorkloadsReady       apis.ConditionType = "WorkloadsReady"
+	CrdsEstablished      apis.ConditionType = "CrdsEstablished"
+	// ResourcesInSync is not part of the installer set readiness, it reports
+	// the resources drifted from manifests with DriftPolicyReport
+	ResourcesInSync apis.ConditionType = "ResourcesInSync"
 )
 
 var (
@@ -40,6 +45,8 @@ var (
 		WebhookReady,
 		ControllerReady,
 		AllDeploymentsReady,
+		WorkloadsReady,
+		CrdsEstablished,
 	)
 )
 
@@ -91,6 +98,25 @@ func (tis *TektonInstallerSetStatus) MarkAllDeploymentsReady() {
 	installerSetCondSet.Manage(tis).MarkTrue(AllDeploymentsReady)
 }
 
+func (tis *TektonInstallerSetStatus) MarkWorkloadsReady() {
+	installerSetCondSet.Manage(tis).MarkTrue(WorkloadsReady)
+}
+
+func (tis *TektonInstallerSetStatus) MarkCRDsEstablished() {
+	installerSetCondSet.Manage(tis).MarkTrue(CrdsEstablished)
+}
+
+func (tis *TektonInstallerSetStatus) MarkResourcesInSync() {
+	installerSetCondSet.Manage(tis).MarkTrue(ResourcesInSync)
+}
+
+func (tis *TektonInstallerSetStatus) MarkResourcesDrifted(msg string) {
+	installerSetCondSet.Manage(tis).MarkFalse(
+		ResourcesInSync,
+		"Drifted",
+		"Resources drifted from manifests: %s", msg)
+}
+
 func (tis *TektonInstallerSetStatus) MarkNotReady(msg string) {
 	installerSetCondSet.Manage(tis).MarkFalse(
 		apis.ConditionReady,
@@ -153,3 +179,19 @@ func (tis *TektonInstallerSetStatus) MarkAllDeploymentsNotReady(msg string) {
 		"Error",
 		"Deployment: %s", msg)
 }
+
+func (tis *TektonInstallerSetStatus) MarkWorkloadsNotReady(msg string) {
+	tis.MarkNotReady("Workloads not available")
+	installerSetCondSet.Manage(tis).MarkFalse(
+		WorkloadsReady,
+		"Error",
+		"Workload: %s", msg)
+}
+
+func (tis *TektonInstallerSetStatus) MarkCRDsNotEstablished(msg string) {
+	tis.MarkNotReady("CRDs not established")
+	installerSetCondSet.Manage(tis).MarkFalse(
+		CrdsEstablished,
+		"Error",
+		"CRD: %s", msg)
+}
@@ -46,6 +46,8 @@ func TestTektonInstallerSetHappyPath(t *testing.T) {
 	apistest.CheckConditionOngoing(tis, WebhookReady, t)
 	apistest.CheckConditionOngoing(tis, ControllerReady, t)
 	apistest.CheckConditionOngoing(tis, AllDeploymentsReady, t)
+	apistest.CheckConditionOngoing(tis, WorkloadsReady, t)
+	apistest.CheckConditionOngoing(tis, CrdsEstablished, t)
 
 	// Install succeeds.
 	tis.MarkCRDsInstalled()
@@ -73,6 +75,12 @@ func TestTektonInstallerSetHappyPath(t *testing.T) {
 	tis.MarkAllDeploymentsReady()
 	apistest.CheckConditionSucceeded(tis, AllDeploymentsReady, t)
 
+	tis.MarkWorkloadsReady()
+	apistest.CheckConditionSucceeded(tis, WorkloadsReady, t)
+
+	tis.MarkCRDsEstablished()
+	apistest.CheckConditionSucceeded(tis, CrdsEstablished, t)
+
 	if ready := tis.IsReady(); !ready {
 		t.Errorf("tt.IsReady() = %v, want true", ready)
 	}
@@ -89,6 +97,8 @@ func TestTektonInstallerSetErrorPath(t *testing.T) {
 	apistest.CheckConditionOngoing(tis, WebhookReady, t)
 	apistest.CheckConditionOngoing(tis, ControllerReady, t)
 	apistest.CheckConditionOngoing(tis, AllDeploymentsReady, t)
+	apistest.CheckConditionOngoing(tis, WorkloadsReady, t)
+	apistest.CheckConditionOngoing(tis, CrdsEstablished, t)
 
 	// CrdsInstall succeeds
 	tis.MarkCRDsInstalled()
@@ -119,6 +129,12 @@ func TestTektonInstallerSetErrorPath(t *testing.T) {
 	tis.MarkAllDeploymentsReady()
 	apistest.CheckConditionSucceeded(tis, AllDeploymentsReady, t)
 
+	tis.MarkWorkloadsReady()
+	apistest.CheckConditionSucceeded(tis, WorkloadsReady, t)
+
+	tis.MarkCRDsEstablished()
+	apistest.CheckConditionSucceeded(tis, CrdsEstablished, t)
+
 	if ready := tis.IsReady(); !ready {
 		t.Errorf("tt.IsReady() = %v, want true", ready)
 	}
@@ -133,3 +149,53 @@ func TestTektonInstallerSetErrorPath(t *testing.T) {
 		t.Errorf("tt.IsReady() = %v, want false", ready)
 	}
 }
+
+func TestTektonInstallerSetWorkloadsNotReady(t *testing.T) {
+	tis := &TektonInstallerSetStatus{}
+	tis.InitializeConditions()
+
+	tis.MarkCRDsInstalled()
+	tis.MarkClustersScopedResourcesInstalled()
+	tis.MarkNamespaceScopedResourcesInstalled()
+	tis.MarkDeploymentsAvailable()
+	tis.MarkWebhookReady()
+	tis.MarkControllerReady()
+	tis.MarkAllDeploymentsReady()
+
+	tis.MarkWorkloadsNotReady("tekton-results-postgres statefulset not ready")
+	apistest.CheckConditionFailed(tis, WorkloadsReady, t)
+
+	tis.MarkWorkloadsReady()
+	tis.MarkCRDsNotEstablished("tasks.tekton.dev crd not established")
+	apistest.CheckConditionFailed(tis, CrdsEstablished, t)
+
+	if ready := tis.IsReady(); ready {
+		t.Errorf("tt.IsReady() = %v, want false", ready)
+	}
+}
+
+func TestTektonInstallerSetResourcesDrifted(t *testing.T) {
+	tis := &TektonInstallerSetStatus{}
+	tis.InitializeConditions()
+
+	tis.MarkCRDsInstalled()
+	tis.MarkClustersScopedResourcesInstalled()
+	tis.MarkNamespaceScopedResourcesInstalled()
+	tis.MarkDeploymentsAvailable()
+	tis.MarkWebhookReady()
+	tis.MarkControllerReady()
+	tis.MarkAllDeploymentsReady()
+	tis.MarkWorkloadsReady()
+	tis.MarkCRDsEstablished()
+
+	// drifted resources are reported but doesn't affect readiness
+	tis.MarkResourcesDrifted("ConfigMap tekton-pipelines/config-defaults")
+	apistest.CheckConditionFailed(tis, ResourcesInSync, t)
+
+	if ready := tis.IsReady(); !ready {
+		t.Errorf("tt.IsReady() = %v, want true", ready)
+	}
+
+	tis.MarkResourcesInSync()
+	apistest.CheckConditionSucceeded(tis, ResourcesInSync, t)
+}
@@ -38,11 +38,81 @@ type TektonInstallerSet struct {
 // TektonInstallerSetSpec defines the desired state of TektonInstallerSet
 type TektonInstallerSetSpec struct {
 	Manifests mf.Slice `json:"manifests,omitempty"`
+	// ContinueOnError when set continues applying the rest of the resources
+	// if a resource fails to apply, the failed resources are reported in status
+	// +optional
+	ContinueOnError bool `json:"continueOnError,omitempty"`
+	// ServerSideApply when set applies the resources using server-side apply,
+	// fields owned by other field managers are reported as conflict in status
+	// instead of being overwritten
+	// +optional
+	ServerSideApply bool `json:"serverSideApply,omitempty"`
+	// DriftPolicy decides what to do when a resource on cluster is changed
+	// manually, one of enforce, report or ignore (default)
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
 }
 
+// DriftPolicy is the policy for resources which have drifted
+// from the installer set manifests
+type DriftPolicy string
+
+const (
+	// DriftPolicyEnforce reverts the drifted resources
+	DriftPolicyEnforce DriftPolicy = "enforce"
+	// DriftPolicyReport reports the drifted resources through
+	// condition, event and metric and keeps them as it is
+	DriftPolicyReport DriftPolicy = "report"
+	// DriftPolicyIgnore doesn't check resources for drift
+	DriftPolicyIgnore DriftPolicy = "ignore"
+)
+
 // TektonInstallerSetStatus defines the observed state of TektonInstallerSet
 type TektonInstallerSetStatus struct {
 	duckv1.Status `json:",inline"`
+
+	// Inventory is the list of resources applied by the installer set
+	// in the last reconcile, it is used to delete the resources which
+	// are removed from the manifests on update
+	// +optional
+	Inventory []InstallerSetResource `json:"inventory,omitempty"`
+
+	// Resources is the apply status of each resource attempted
+	// in the last reconcile
+	// +optional
+	Resources []InstallerSetResourceStatus `json:"resources,omitempty"`
+}
+
+// InstallerSetResource identifies a resource applied by a TektonInstallerSet
+type InstallerSetResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// ResourceApplyResult is the result of applying a resource
+type ResourceApplyResult string
+
+const (
+	ResourceCreated   ResourceApplyResult = "Created"
+	ResourceUpdated   ResourceApplyResult = "Updated"
+	ResourceUnchanged ResourceApplyResult = "Unchanged"
+	ResourceFailed    ResourceApplyResult = "Failed"
+	ResourceConflict  ResourceApplyResult = "Conflict"
+	ResourceDrifted   ResourceApplyResult = "Drifted"
+)
+
+// InstallerSetResourceStatus is the apply status of a resource in TektonInstallerSet
+type InstallerSetResourceStatus struct {
+	InstallerSetResource `json:",inline"`
+	// Hash is the last-applied hash of the resource
+	// +optional
+	Hash   string              `json:"hash,omitempty"`
+	Result ResourceApplyResult `json:"result"`
+	// Error is the error from the last apply, if it failed
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // TektonInstallerSetList contains a list of TektonInstallerSet
@@ -49,6 +49,17 @@ type TektonPipelineSpec struct {
 	// Config holds the configuration for resources created by TektonPipeline
 	// +optional
 	Config Config `json:"config,omitempty"`
+	// Options holds the overrides of resources created by TektonPipeline
+	// +optional
+	Options *AdditionalOptions `json:"options,omitempty"`
+	// Upgrade configures how Pipelines are upgraded
+	// +optional
+	Upgrade *Upgrade `json:"upgrade,omitempty"`
+}
+
+// GetUpgrade implements UpgradeSpec
+func (s *TektonPipelineSpec) GetUpgrade() *Upgrade {
+	return s.Upgrade
 }
 
 // TektonPipelineStatus defines the observed state of TektonPipeline
@@ -78,9 +89,29 @@ type TektonPipelineList struct {
 // Pipeline defines the field to customize Pipeline component
 type Pipeline struct {
 	PipelineProperties `json:",inline"`
+	// Version of Pipelines to install, one of the versions shipped with the
+	// operator. The latest shipped version is installed if empty
+	// +optional
+	Version string `json:"version,omitempty"`
 	// The params to customize different components of Pipelines
 	// +optional
 	Params []Param `json:"params,omitempty"`
+	// Rollback configures rolling back a failed upgrade of Pipelines
+	// +optional
+	Rollback *UpgradeRollback `json:"rollback,omitempty"`
+	// InstallerSet configures the installer sets of Pipelines
+	// +optional
+	InstallerSet *InstallerSetOptions `json:"installerSet,omitempty"`
+}
+
+// GetUpgradeRollback implements UpgradeRollbackSpec
+func (p *Pipeline) GetUpgradeRollback() *UpgradeRollback {
+	return p.Rollback
+}
+
+// GetInstallerSetOptions implements InstallerSetOptionsSpec
+func (p *Pipeline) GetInstallerSetOptions() *InstallerSetOptions {
+	return p.InstallerSet
 }
 
 // PipelineProperties defines customizable flags for Pipeline Component.
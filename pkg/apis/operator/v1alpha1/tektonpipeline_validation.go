@@ -39,6 +39,12 @@ func (tp *TektonPipeline) Validate(ctx context.Context) (errs *apis.FieldError)
 		errs = errs.Also(apis.ErrMissingField("spec.targetNamespace"))
 	}
 
+	errs = errs.Also(tp.Spec.Rollback.validate("spec.rollback"))
+	errs = errs.Also(tp.Spec.InstallerSet.validate("spec.installerSet"))
+	errs = errs.Also(tp.Spec.Upgrade.validate("spec.upgrade"))
+	errs = errs.Also(tp.Spec.Options.validate("spec.options"))
+	errs = errs.Also(validateVersion(ctx, KindTektonPipeline, tp.Spec.Version, "spec.version"))
+
 	return errs.Also(tp.Spec.PipelineProperties.validate("spec"))
 }
 
@@ -81,3 +81,64 @@ func Test_ValidateTektonPipeline_OnDelete(t *testing.T) {
 		t.Errorf("ValidateTektonPipeline.Validate() on Delete expected no error, but got one, ValidateTektonPipeline: %v", err)
 	}
 }
+
+func Test_ValidateTektonPipeline_OptionsConfigMaps(t *testing.T) {
+
+	tp := &TektonPipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pipeline",
+			Namespace: "namespace",
+		},
+		Spec: TektonPipelineSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+			Options: &AdditionalOptions{
+				ConfigMaps: map[string]ConfigMapOverride{
+					"config-logging": {
+						Data: map[string]string{"loglevel.controller": "debug", "_example": ""},
+					},
+					"feature-flags": {
+						Data: map[string]string{"enable-api-fields": "beta", "enable-new-flag": "true"},
+					},
+					"custom-config": {
+						Data: map[string]string{"foo": "bar"},
+					},
+				},
+			},
+		},
+	}
+
+	err := tp.Validate(context.TODO())
+	assert.Equal(t, true, err.Filter(apis.ErrorLevel) == nil)
+	assert.Equal(t, "ConfigMap is not known to be shipped with the components, the data is merged as is: spec.options.configMaps.custom-config\n"+
+		"key \"enable-new-flag\" is not known for the ConfigMap, the data is merged as is: spec.options.configMaps.feature-flags.data",
+		err.Filter(apis.WarningLevel).Error())
+}
+
+func Test_ValidateTektonPipeline_InstallerSetDriftPolicy(t *testing.T) {
+
+	tp := &TektonPipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pipeline",
+			Namespace: "namespace",
+		},
+		Spec: TektonPipelineSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+			Pipeline: Pipeline{
+				InstallerSet: &InstallerSetOptions{
+					DriftPolicy: "revert",
+				},
+			},
+		},
+	}
+
+	err := tp.Validate(context.TODO())
+	assert.Equal(t, "invalid value: revert: spec.installerSet.driftPolicy", err.Error())
+
+	tp.Spec.InstallerSet.DriftPolicy = DriftPolicyEnforce
+	err = tp.Validate(context.TODO())
+	assert.Assert(t, err == nil)
+}
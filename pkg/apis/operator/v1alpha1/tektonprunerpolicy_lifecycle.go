@@ -0,0 +1,56 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+)
+
+var (
+	prunerPolicyCondSet = apis.NewLivingConditionSet()
+)
+
+// GroupVersionKind returns SchemeGroupVersion of a TektonPrunerPolicy
+func (tp *TektonPrunerPolicy) GroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind(KindTektonPrunerPolicy)
+}
+
+// GetCondition returns the current condition of a given condition type
+func (tps *TektonPrunerPolicyStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return prunerPolicyCondSet.Manage(tps).GetCondition(t)
+}
+
+// InitializeConditions initializes conditions of an TektonPrunerPolicyStatus
+func (tps *TektonPrunerPolicyStatus) InitializeConditions() {
+	prunerPolicyCondSet.Manage(tps).InitializeConditions()
+}
+
+// IsReady looks at the conditions returns true if they are all true.
+func (tps *TektonPrunerPolicyStatus) IsReady() bool {
+	return prunerPolicyCondSet.Manage(tps).IsHappy()
+}
+
+// MarkActive marks the policy as the one pruning its namespace
+func (tps *TektonPrunerPolicyStatus) MarkActive() {
+	prunerPolicyCondSet.Manage(tps).MarkTrue(apis.ConditionReady)
+}
+
+// MarkInactive marks the policy as not pruning its namespace
+func (tps *TektonPrunerPolicyStatus) MarkInactive(reason, msg string) {
+	prunerPolicyCondSet.Manage(tps).MarkFalse(apis.ConditionReady, reason, msg)
+}
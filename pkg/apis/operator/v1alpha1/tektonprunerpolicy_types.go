@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// TektonPrunerPolicy is the Schema for the tektonprunerpolicies API.
+// It sets how the PipelineRuns and TaskRuns of its namespace are pruned,
+// taking precedence over the prune annotations of the namespace and the
+// pruner config of TektonConfig.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TektonPrunerPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TektonPrunerPolicySpec   `json:"spec,omitempty"`
+	Status TektonPrunerPolicyStatus `json:"status,omitempty"`
+}
+
+// TektonPrunerPolicySpec defines the desired state of TektonPrunerPolicy.
+// The schedule may be left out to use the one of TektonConfig.
+type TektonPrunerPolicySpec struct {
+	Prune `json:",inline"`
+}
+
+// TektonPrunerPolicyStatus defines the observed state of TektonPrunerPolicy
+type TektonPrunerPolicyStatus struct {
+	duckv1.Status `json:",inline"`
+
+	// LastPruneTime is the time the latest pruner run finished
+	// +optional
+	LastPruneTime *metav1.Time `json:"lastPruneTime,omitempty"`
+	// NextPruneTime is the time the next pruner run is scheduled
+	// +optional
+	NextPruneTime *metav1.Time `json:"nextPruneTime,omitempty"`
+	// Deleted holds the number of resources deleted by the latest run,
+	// keyed by resource (pipelinerun/taskrun)
+	// +optional
+	Deleted map[string]int32 `json:"deleted,omitempty"`
+	// NotArchived holds the number of expired resources kept by the latest
+	// run because they are not archived yet, keyed by resource
+	// +optional
+	NotArchived map[string]int32 `json:"notArchived,omitempty"`
+	// Errors is the number of failures in the latest run
+	// +optional
+	Errors int32 `json:"errors,omitempty"`
+	// Error holds the last failure of the latest run
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// TektonPrunerPolicyList contains a list of TektonPrunerPolicy
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TektonPrunerPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TektonPrunerPolicy `json:"items"`
+}
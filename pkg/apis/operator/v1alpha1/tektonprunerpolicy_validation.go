@@ -0,0 +1,39 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"knative.dev/pkg/apis"
+)
+
+func (tp *TektonPrunerPolicy) Validate(ctx context.Context) (errs *apis.FieldError) {
+
+	if apis.IsInDelete(ctx) {
+		return nil
+	}
+
+	errs = errs.Also(tp.Spec.Prune.validate("spec"))
+	if a := tp.Spec.Archive; a != nil && isValueInArray(PruneArchiveSinks, a.Sink) && !isValueInArray(PolicyArchiveSinks, a.Sink) {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("the %s sink may only be used by the pruner of TektonConfig, a policy may use %s",
+			a.Sink, strings.Join(PolicyArchiveSinks, ", ")), "spec.archive.sink"))
+	}
+	return errs
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ValidateTektonPrunerPolicy_WithoutSchedule(t *testing.T) {
+	keep := uint(2)
+	tp := &TektonPrunerPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "policy",
+			Namespace: "namespace",
+		},
+		Spec: TektonPrunerPolicySpec{
+			Prune: Prune{
+				Resources: []string{"pipelinerun"},
+				Keep:      &keep,
+			},
+		},
+	}
+
+	err := tp.Validate(context.TODO())
+	assert.Equal(t, err.Error(), "")
+}
+
+func Test_ValidateTektonPrunerPolicy_Invalid(t *testing.T) {
+	keepSince := uint(60)
+	tp := &TektonPrunerPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "policy",
+			Namespace: "namespace",
+		},
+		Spec: TektonPrunerPolicySpec{
+			Prune: Prune{
+				KeepSince: &keepSince,
+				GroupBy:   "name",
+			},
+		},
+	}
+
+	err := tp.Validate(context.TODO())
+	assert.Equal(t, "groupBy \"name\" requires keep, succeeded resources are kept with keep-since: spec.groupBy\n"+
+		"missing field(s): spec.resources", err.Error())
+}
+
+func Test_ValidateTektonPrunerPolicy_ArchiveSink(t *testing.T) {
+	keep := uint(2)
+	tp := &TektonPrunerPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "policy",
+			Namespace: "namespace",
+		},
+		Spec: TektonPrunerPolicySpec{
+			Prune: Prune{
+				Resources: []string{"pipelinerun"},
+				Keep:      &keep,
+				Archive: &PruneArchive{
+					Sink: "s3",
+					S3:   &PruneArchiveS3{Endpoint: "http://169.254.169.254", Bucket: "runs", SecretName: "s3"},
+				},
+			},
+		},
+	}
+
+	err := tp.Validate(context.TODO())
+	assert.Equal(t, err.Error(), "the s3 sink may only be used by the pruner of TektonConfig, a policy may use results: spec.archive.sink")
+
+	tp.Spec.Archive = &PruneArchive{Sink: "pvc"}
+	err = tp.Validate(context.TODO())
+	assert.Equal(t, err.Error(), "the pvc sink may only be used by the pruner of TektonConfig, a policy may use results: spec.archive.sink")
+
+	tp.Spec.Archive = &PruneArchive{Sink: "results"}
+	err = tp.Validate(context.TODO())
+	assert.Equal(t, err.Error(), "")
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
@@ -51,7 +52,152 @@ func (tp *TektonResult) GetStatus() TektonComponentStatus {
 
 // TektonResultSpec defines the desired state of TektonResult
 type TektonResultSpec struct {
-	CommonSpec `json:",inline"`
+	CommonSpec           `json:",inline"`
+	ResultsAPIProperties `json:",inline"`
+	// Config holds the configuration for resources created by TektonResult
+	// +optional
+	Config Config `json:"config,omitempty"`
+	// Options holds the overrides of resources created by TektonResult
+	// +optional
+	Options *AdditionalOptions `json:"options,omitempty"`
+}
+
+// ResultConfig defines the results section of TektonConfig
+type ResultConfig struct {
+	// Enable installs Results with the all and basic profiles,
+	// on Kubernetes only
+	// +optional
+	Enable               bool `json:"enable,omitempty"`
+	ResultsAPIProperties `json:",inline"`
+}
+
+// ResultsAPIProperties defines the configuration of the Results API server,
+// of its database and of the storage of the logs
+type ResultsAPIProperties struct {
+	// DB configures the database the results are stored in
+	// +optional
+	DB *ResultsDB `json:"db,omitempty"`
+	// TLS configures the certificate served by the API server
+	// +optional
+	TLS *ResultsTLS `json:"tls,omitempty"`
+	// Logs configures the storage of the logs of the runs
+	// +optional
+	Logs *ResultsLogs `json:"logs,omitempty"`
+	// Retention is how long the results are kept after their last update,
+	// they are kept forever when unset
+	// +optional
+	Retention *metav1.Duration `json:"retention,omitempty"`
+	// LogLevel of the API server, one of debug, info, warn and error
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+	// AuthDisable turns off the authentication of the requests to the API
+	// server
+	// +optional
+	AuthDisable bool `json:"authDisable,omitempty"`
+	// Workloads overrides the scheduling and resources of each workload
+	// +optional
+	Workloads ResultsWorkloads `json:"workloads,omitempty"`
+}
+
+// ResultsWorkloads defines the overrides of the workloads of Results
+type ResultsWorkloads struct {
+	// API is the API server Deployment
+	// +optional
+	API *ResultsWorkload `json:"api,omitempty"`
+	// Watcher is the watcher Deployment
+	// +optional
+	Watcher *ResultsWorkload `json:"watcher,omitempty"`
+	// Postgres is the StatefulSet of the provisioned database
+	// +optional
+	Postgres *ResultsWorkload `json:"postgres,omitempty"`
+}
+
+// ResultsWorkload defines the overrides of a workload of Results
+type ResultsWorkload struct {
+	// Config replaces the fields of spec.config it sets for the workload
+	Config `json:",inline"`
+	// Resources of the containers of the workload
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// ResultsDB defines the database of Results
+type ResultsDB struct {
+	// Provision runs Postgres in the target namespace with a password
+	// generated in the database Secret, it defaults to true unless host is set
+	// +optional
+	Provision *bool `json:"provision,omitempty"`
+	// Host of an external database
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Port of the database, 5432 by default
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+	// Name of the database, tekton-results by default
+	// +optional
+	Name string `json:"name,omitempty"`
+	// SSLMode of the connections to the database, as in libpq
+	// +optional
+	SSLMode string `json:"sslMode,omitempty"`
+	// SecretName is the Secret holding the POSTGRES_USER and
+	// POSTGRES_PASSWORD of the database, tekton-results-postgres by default
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// IsProvisioned tells whether the operator runs the database
+func (db *ResultsDB) IsProvisioned() bool {
+	if db == nil {
+		return true
+	}
+	if db.Provision != nil {
+		return *db.Provision
+	}
+	return db.Host == ""
+}
+
+// DatabaseName returns the name of the database, the default one if not set
+func (db *ResultsDB) DatabaseName() string {
+	if db == nil || db.Name == "" {
+		return ResultsDefaultDBName
+	}
+	return db.Name
+}
+
+// ResultsTLS defines the certificate of the API server
+type ResultsTLS struct {
+	// SecretName is the kubernetes.io/tls Secret holding the certificate,
+	// tekton-results-tls by default
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// Generate lets the operator create a self-signed certificate in the
+	// Secret when it does not exist, and renew it before it expires
+	// +optional
+	Generate bool `json:"generate,omitempty"`
+}
+
+// ResultsLogs defines the storage of the logs of the runs
+type ResultsLogs struct {
+	// Enabled turns on the logs API
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Type of the storage, File or S3, File by default
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Path the logs are written to with the File storage, /logs by default
+	// +optional
+	Path string `json:"path,omitempty"`
+	// BufferSize in bytes of the logs streamed to the storage
+	// +optional
+	BufferSize *int64 `json:"bufferSize,omitempty"`
+	// PVCName is the PersistentVolumeClaim mounted at path with the File
+	// storage
+	// +optional
+	PVCName string `json:"pvcName,omitempty"`
+	// S3SecretName is the Secret holding the S3_* settings of the S3
+	// storage, passed as env vars to the API server
+	// +optional
+	S3SecretName string `json:"s3SecretName,omitempty"`
 }
 
 // TektonResultStatus defines the observed state of TektonResult
@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"knative.dev/pkg/apis"
+)
+
+func (tr *TektonResult) Validate(ctx context.Context) (errs *apis.FieldError) {
+
+	if apis.IsInDelete(ctx) {
+		return nil
+	}
+
+	if tr.GetName() != ResultResourceName {
+		errMsg := fmt.Sprintf("metadata.name, Only one instance of TektonResult is allowed by name, %s", ResultResourceName)
+		errs = errs.Also(apis.ErrInvalidValue(tr.GetName(), errMsg))
+	}
+
+	if tr.Spec.TargetNamespace == "" {
+		errs = errs.Also(apis.ErrMissingField("spec.targetNamespace"))
+	}
+
+	errs = errs.Also(tr.Spec.Options.validate("spec.options"))
+
+	if apis.IsInUpdate(ctx) {
+		if old, ok := apis.GetBaseline(ctx).(*TektonResult); ok && old != nil {
+			errs = errs.Also(tr.Spec.DB.validateUpdate(old.Spec.DB, "spec.db"))
+		}
+	}
+
+	return errs.Also(tr.Spec.ResultsAPIProperties.validate("spec"))
+}
+
+func (tr *TektonResult) SetDefaults(ctx context.Context) {
+	// the defaults are applied by the reconciler, the spec is copied from
+	// TektonConfig as it is
+}
+
+func (p ResultsAPIProperties) validate(path string) *apis.FieldError {
+	var errs *apis.FieldError
+
+	errs = errs.Also(p.DB.validate(path + ".db"))
+	errs = errs.Also(p.Logs.validate(path + ".logs"))
+
+	if p.Retention != nil && p.Retention.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(p.Retention.Duration.String(), path+".retention"))
+	}
+	if p.LogLevel != "" && !isValueInArray(ResultsLogLevels, p.LogLevel) {
+		errs = errs.Also(apis.ErrInvalidValue(p.LogLevel, path+".logLevel"))
+	}
+	return errs
+}
+
+func (db *ResultsDB) validate(path string) *apis.FieldError {
+	if db == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+
+	if db.Provision != nil {
+		if *db.Provision && db.Host != "" {
+			errs = errs.Also(apis.ErrGeneric("the host of a provisioned database is set by the operator", path+".host"))
+		}
+		if !*db.Provision && db.Host == "" {
+			errs = errs.Also(apis.ErrMissingField(path + ".host"))
+		}
+	}
+	if db.Port != nil && (*db.Port < 1 || *db.Port > 65535) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*db.Port, 1, 65535, path+".port"))
+	}
+	if db.SSLMode != "" && !isValueInArray(ResultsDBSSLModes, db.SSLMode) {
+		errs = errs.Also(apis.ErrInvalidValue(db.SSLMode, path+".sslMode"))
+	}
+	return errs
+}
+
+// validateUpdate rejects renaming a provisioned database, the database is
+// created by Postgres on its first start only and would not be found
+func (db *ResultsDB) validateUpdate(old *ResultsDB, path string) *apis.FieldError {
+	if !old.IsProvisioned() || !db.IsProvisioned() || old.DatabaseName() == db.DatabaseName() {
+		return nil
+	}
+	return apis.ErrGeneric(fmt.Sprintf("the provisioned database %q cannot be renamed to %q", old.DatabaseName(), db.DatabaseName()), path+".name")
+}
+
+func (l *ResultsLogs) validate(path string) *apis.FieldError {
+	if l == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+
+	if l.Type != "" && !isValueInArray(ResultsLogsTypes, l.Type) {
+		return errs.Also(apis.ErrInvalidValue(l.Type, path+".type"))
+	}
+	if l.BufferSize != nil && *l.BufferSize <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*l.BufferSize, path+".bufferSize"))
+	}
+	if !l.Enabled {
+		return errs
+	}
+
+	switch l.Type {
+	case ResultsLogsTypeS3:
+		if l.S3SecretName == "" {
+			errs = errs.Also(apis.ErrMissingField(path + ".s3SecretName"))
+		}
+	default:
+		if l.PVCName == "" {
+			errs = errs.Also(apis.ErrMissingField(path + ".pvcName"))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
+)
+
+func Test_ValidateTektonResult_MissingTargetNamespace(t *testing.T) {
+
+	tr := &TektonResult{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "result",
+		},
+		Spec: TektonResultSpec{},
+	}
+
+	err := tr.Validate(context.TODO())
+	assert.Equal(t, "missing field(s): spec.targetNamespace", err.Error())
+}
+
+func Test_ValidateTektonResult_OnDelete(t *testing.T) {
+
+	tr := &TektonResult{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "name",
+		},
+	}
+
+	err := tr.Validate(apis.WithinDelete(context.Background()))
+	assert.Assert(t, err == nil)
+}
+
+func Test_ValidateTektonResult_Properties(t *testing.T) {
+	tr := &TektonResult{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "result",
+		},
+		Spec: TektonResultSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+			ResultsAPIProperties: ResultsAPIProperties{
+				DB: &ResultsDB{
+					Host:    "postgres.example.com",
+					Port:    ptr.Int32(5433),
+					SSLMode: "verify-full",
+				},
+				TLS: &ResultsTLS{Generate: true},
+				Logs: &ResultsLogs{
+					Enabled:      true,
+					Type:         ResultsLogsTypeS3,
+					S3SecretName: "s3",
+				},
+				Retention: &metav1.Duration{Duration: 30 * 24 * time.Hour},
+				LogLevel:  "info",
+			},
+		},
+	}
+	assert.Assert(t, tr.Validate(context.TODO()) == nil)
+	assert.Assert(t, !tr.Spec.DB.IsProvisioned())
+
+	tr.Spec.DB = &ResultsDB{Provision: ptr.Bool(false)}
+	err := tr.Validate(context.TODO())
+	assert.Equal(t, "missing field(s): spec.db.host", err.Error())
+
+	tr.Spec.DB = &ResultsDB{Provision: ptr.Bool(true), Host: "postgres", Port: ptr.Int32(0)}
+	err = tr.Validate(context.TODO())
+	assert.Equal(t, "expected 1 <= 0 <= 65535: spec.db.port\nthe host of a provisioned database is set by the operator: spec.db.host", err.Error())
+
+	tr.Spec.DB = nil
+	tr.Spec.Logs = &ResultsLogs{Enabled: true}
+	tr.Spec.LogLevel = "verbose"
+	err = tr.Validate(context.TODO())
+	assert.Equal(t, "invalid value: verbose: spec.logLevel\nmissing field(s): spec.logs.pvcName", err.Error())
+
+	tr.Spec.LogLevel = ""
+	tr.Spec.Logs = &ResultsLogs{Enabled: true, Type: "GCS"}
+	tr.Spec.Retention.Duration = 0
+	err = tr.Validate(context.TODO())
+	assert.Equal(t, "invalid value: 0s: spec.retention\ninvalid value: GCS: spec.logs.type", err.Error())
+}
+
+func Test_ValidateTektonConfig_Result(t *testing.T) {
+	tc := &TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "config",
+		},
+		Spec: TektonConfigSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+			Result: ResultConfig{
+				Enable: true,
+				ResultsAPIProperties: ResultsAPIProperties{
+					DB: &ResultsDB{SSLMode: "always"},
+				},
+			},
+		},
+	}
+
+	err := tc.Validate(context.TODO())
+	assert.Equal(t, "invalid value: always: spec.result.db.sslMode", err.Error())
+}
+
+func Test_ValidateTektonResult_RenameProvisionedDB(t *testing.T) {
+	old := &TektonResult{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "result",
+		},
+		Spec: TektonResultSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+		},
+	}
+	tr := old.DeepCopy()
+	tr.Spec.DB = &ResultsDB{Name: "results"}
+	ctx := apis.WithinUpdate(context.Background(), old)
+
+	err := tr.Validate(ctx)
+	assert.Equal(t, `the provisioned database "tekton-results" cannot be renamed to "results": spec.db.name`, err.Error())
+
+	// the default name may be set explicitly
+	tr.Spec.DB.Name = ResultsDefaultDBName
+	assert.Assert(t, tr.Validate(ctx) == nil)
+
+	// an external database may have any name
+	tr.Spec.DB = &ResultsDB{Host: "postgres.example.com", Name: "results"}
+	assert.Assert(t, tr.Validate(ctx) == nil)
+	old.Spec.DB = tr.Spec.DB.DeepCopy()
+	tr.Spec.DB.Name = "other"
+	assert.Assert(t, tr.Validate(apis.WithinUpdate(context.Background(), old)) == nil)
+
+	tc := &TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "config",
+		},
+		Spec: TektonConfigSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+		},
+	}
+	oldConfig := tc.DeepCopy()
+	tc.Spec.Result.DB = &ResultsDB{Name: "results"}
+	err = tc.Validate(apis.WithinUpdate(context.Background(), oldConfig))
+	assert.Equal(t, `the provisioned database "tekton-results" cannot be renamed to "results": spec.result.db.name`, err.Error())
+}
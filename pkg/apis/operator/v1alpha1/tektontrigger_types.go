@@ -51,6 +51,17 @@ type TektonTriggerSpec struct {
 	// Config holds the configuration for resources created by TektonTrigger
 	// +optional
 	Config Config `json:"config,omitempty"`
+	// Options holds the overrides of resources created by TektonTrigger
+	// +optional
+	Options *AdditionalOptions `json:"options,omitempty"`
+	// Upgrade configures how Triggers are upgraded
+	// +optional
+	Upgrade *Upgrade `json:"upgrade,omitempty"`
+}
+
+// GetUpgrade implements UpgradeSpec
+func (s *TektonTriggerSpec) GetUpgrade() *Upgrade {
+	return s.Upgrade
 }
 
 // TektonTriggerStatus defines the observed state of TektonTrigger
@@ -77,6 +88,26 @@ type TektonTriggerList struct {
 // Trigger defines the field to customize Trigger component
 type Trigger struct {
 	TriggersProperties `json:",inline"`
+	// Version of Triggers to install, one of the versions shipped with the
+	// operator. The latest shipped version is installed if empty
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Rollback configures rolling back a failed upgrade of Triggers
+	// +optional
+	Rollback *UpgradeRollback `json:"rollback,omitempty"`
+	// InstallerSet configures the installer sets of Triggers
+	// +optional
+	InstallerSet *InstallerSetOptions `json:"installerSet,omitempty"`
+}
+
+// GetUpgradeRollback implements UpgradeRollbackSpec
+func (t *Trigger) GetUpgradeRollback() *UpgradeRollback {
+	return t.Rollback
+}
+
+// GetInstallerSetOptions implements InstallerSetOptionsSpec
+func (t *Trigger) GetInstallerSetOptions() *InstallerSetOptions {
+	return t.InstallerSet
 }
 
 // TriggersProperties defines the fields which are to be
@@ -39,6 +39,12 @@ func (tr *TektonTrigger) Validate(ctx context.Context) (errs *apis.FieldError) {
 		errs = errs.Also(apis.ErrMissingField("spec.targetNamespace"))
 	}
 
+	errs = errs.Also(tr.Spec.Rollback.validate("spec.rollback"))
+	errs = errs.Also(tr.Spec.InstallerSet.validate("spec.installerSet"))
+	errs = errs.Also(tr.Spec.Upgrade.validate("spec.upgrade"))
+	errs = errs.Also(tr.Spec.Options.validate("spec.options"))
+	errs = errs.Also(validateVersion(ctx, KindTektonTrigger, tr.Spec.Version, "spec.version"))
+
 	return errs.Also(tr.Spec.TriggersProperties.validate("spec"))
 }
 
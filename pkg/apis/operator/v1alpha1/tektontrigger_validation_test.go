@@ -81,3 +81,35 @@ func Test_ValidateTektonTrigger_OnDelete(t *testing.T) {
 		t.Errorf("ValidateTektonTrigger.Validate() on Delete expected no error, but got one, ValidateTektonTrigger: %v", err)
 	}
 }
+
+func Test_ValidateTektonTrigger_Version(t *testing.T) {
+
+	tr := &TektonTrigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "trigger",
+			Namespace: "namespace",
+		},
+		Spec: TektonTriggerSpec{
+			CommonSpec: CommonSpec{
+				TargetNamespace: "namespace",
+			},
+			Trigger: Trigger{
+				Version: "0.14.3",
+			},
+		},
+	}
+
+	// versions are not validated if the shipped versions are not known
+	err := tr.Validate(context.TODO())
+	assert.Assert(t, err == nil)
+
+	ctx := WithShippedVersions(context.TODO(), map[string][]string{
+		KindTektonTrigger: {"0.15.2", "0.14.3"},
+	})
+	err = tr.Validate(ctx)
+	assert.Assert(t, err == nil)
+
+	tr.Spec.Version = "0.13.2"
+	err = tr.Validate(ctx)
+	assert.Equal(t, "invalid value: 0.13.2: spec.version\nversion is not shipped with the operator, supported versions: 0.15.2, 0.14.3", err.Error())
+}
@@ -24,9 +24,40 @@ package v1alpha1
 import (
 	manifestival "github.com/manifestival/manifestival"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalOptions) DeepCopyInto(out *AdditionalOptions) {
+	*out = *in
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make(map[string]ConfigMapOverride, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = make(map[string]DeploymentOverride, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalOptions.
+func (in *AdditionalOptions) DeepCopy() *AdditionalOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Addon) DeepCopyInto(out *Addon) {
 	*out = *in
@@ -87,6 +118,11 @@ func (in *Chain) DeepCopyInto(out *Chain) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = new(ChainSecrets)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -100,6 +136,64 @@ func (in *Chain) DeepCopy() *Chain {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChainConfig) DeepCopyInto(out *ChainConfig) {
+	*out = *in
+	in.Chain.DeepCopyInto(&out.Chain)
+	if in.SigningKeys != nil {
+		in, out := &in.SigningKeys, &out.SigningKeys
+		*out = new(SigningKeys)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChainConfig.
+func (in *ChainConfig) DeepCopy() *ChainConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ChainConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChainSecrets) DeepCopyInto(out *ChainSecrets) {
+	*out = *in
+	if in.KMSAuthToken != nil {
+		in, out := &in.KMSAuthToken, &out.KMSAuthToken
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DocDBURL != nil {
+		in, out := &in.DocDBURL, &out.DocDBURL
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafeasCredentials != nil {
+		in, out := &in.GrafeasCredentials, &out.GrafeasCredentials
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RegistryCredentials != nil {
+		in, out := &in.RegistryCredentials, &out.RegistryCredentials
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChainSecrets.
+func (in *ChainSecrets) DeepCopy() *ChainSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(ChainSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonSpec) DeepCopyInto(out *CommonSpec) {
 	*out = *in
@@ -116,6 +210,26 @@ func (in *CommonSpec) DeepCopy() *CommonSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Config) DeepCopyInto(out *Config) {
 	*out = *in
@@ -146,6 +260,62 @@ func (in *Config) DeepCopy() *Config {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapOverride) DeepCopyInto(out *ConfigMapOverride) {
+	*out = *in
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapOverride.
+func (in *ConfigMapOverride) DeepCopy() *ConfigMapOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerOverride) DeepCopyInto(out *ContainerOverride) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerOverride.
+func (in *ContainerOverride) DeepCopy() *ContainerOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Dashboard) DeepCopyInto(out *Dashboard) {
 	*out = *in
@@ -195,6 +365,60 @@ func (in *DbSpec) DeepCopy() *DbSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentOverride) DeepCopyInto(out *DeploymentOverride) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make(map[string]ContainerOverride, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentOverride.
+func (in *DeploymentOverride) DeepCopy() *DeploymentOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Hub) DeepCopyInto(out *Hub) {
 	*out = *in
@@ -216,6 +440,89 @@ func (in *Hub) DeepCopy() *Hub {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallerSetOptions) DeepCopyInto(out *InstallerSetOptions) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallerSetOptions.
+func (in *InstallerSetOptions) DeepCopy() *InstallerSetOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallerSetOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallerSetResource) DeepCopyInto(out *InstallerSetResource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallerSetResource.
+func (in *InstallerSetResource) DeepCopy() *InstallerSetResource {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallerSetResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallerSetResourceStatus) DeepCopyInto(out *InstallerSetResourceStatus) {
+	*out = *in
+	out.InstallerSetResource = in.InstallerSetResource
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallerSetResourceStatus.
+func (in *InstallerSetResourceStatus) DeepCopy() *InstallerSetResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallerSetResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacePruneStatus) DeepCopyInto(out *NamespacePruneStatus) {
+	*out = *in
+	if in.LastPruneTime != nil {
+		in, out := &in.LastPruneTime, &out.LastPruneTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Deleted != nil {
+		in, out := &in.Deleted, &out.Deleted
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NotArchived != nil {
+		in, out := &in.NotArchived, &out.NotArchived
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacePruneStatus.
+func (in *NamespacePruneStatus) DeepCopy() *NamespacePruneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacePruneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OptionalPipelineProperties) DeepCopyInto(out *OptionalPipelineProperties) {
 	*out = *in
@@ -227,197 +534,543 @@ func (in *OptionalPipelineProperties) DeepCopyInto(out *OptionalPipelineProperti
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptionalPipelineProperties.
-func (in *OptionalPipelineProperties) DeepCopy() *OptionalPipelineProperties {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptionalPipelineProperties.
+func (in *OptionalPipelineProperties) DeepCopy() *OptionalPipelineProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(OptionalPipelineProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptionalTriggersProperties) DeepCopyInto(out *OptionalTriggersProperties) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptionalTriggersProperties.
+func (in *OptionalTriggersProperties) DeepCopy() *OptionalTriggersProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(OptionalTriggersProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Param) DeepCopyInto(out *Param) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Param.
+func (in *Param) DeepCopy() *Param {
+	if in == nil {
+		return nil
+	}
+	out := new(Param)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParamValue) DeepCopyInto(out *ParamValue) {
+	*out = *in
+	if in.Possible != nil {
+		in, out := &in.Possible, &out.Possible
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParamValue.
+func (in *ParamValue) DeepCopy() *ParamValue {
+	if in == nil {
+		return nil
+	}
+	out := new(ParamValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Pipeline) DeepCopyInto(out *Pipeline) {
+	*out = *in
+	in.PipelineProperties.DeepCopyInto(&out.PipelineProperties)
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make([]Param, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(UpgradeRollback)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstallerSet != nil {
+		in, out := &in.InstallerSet, &out.InstallerSet
+		*out = new(InstallerSetOptions)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pipeline.
+func (in *Pipeline) DeepCopy() *Pipeline {
+	if in == nil {
+		return nil
+	}
+	out := new(Pipeline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineMetricsProperties) DeepCopyInto(out *PipelineMetricsProperties) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineMetricsProperties.
+func (in *PipelineMetricsProperties) DeepCopy() *PipelineMetricsProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineMetricsProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineProperties) DeepCopyInto(out *PipelineProperties) {
+	*out = *in
+	if in.DisableAffinityAssistant != nil {
+		in, out := &in.DisableAffinityAssistant, &out.DisableAffinityAssistant
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableCredsInit != nil {
+		in, out := &in.DisableCredsInit, &out.DisableCredsInit
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AwaitSidecarReadiness != nil {
+		in, out := &in.AwaitSidecarReadiness, &out.AwaitSidecarReadiness
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RunningInEnvironmentWithInjectedSidecars != nil {
+		in, out := &in.RunningInEnvironmentWithInjectedSidecars, &out.RunningInEnvironmentWithInjectedSidecars
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RequireGitSshSecretKnownHosts != nil {
+		in, out := &in.RequireGitSshSecretKnownHosts, &out.RequireGitSshSecretKnownHosts
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableTektonOciBundles != nil {
+		in, out := &in.EnableTektonOciBundles, &out.EnableTektonOciBundles
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableCustomTasks != nil {
+		in, out := &in.EnableCustomTasks, &out.EnableCustomTasks
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SendCloudEventsForRuns != nil {
+		in, out := &in.SendCloudEventsForRuns, &out.SendCloudEventsForRuns
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ScopeWhenExpressionsToTask != nil {
+		in, out := &in.ScopeWhenExpressionsToTask, &out.ScopeWhenExpressionsToTask
+		*out = new(bool)
+		**out = **in
+	}
+	out.PipelineMetricsProperties = in.PipelineMetricsProperties
+	in.OptionalPipelineProperties.DeepCopyInto(&out.OptionalPipelineProperties)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineProperties.
+func (in *PipelineProperties) DeepCopy() *PipelineProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Prune) DeepCopyInto(out *Prune) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Keep != nil {
+		in, out := &in.Keep, &out.Keep
+		*out = new(uint)
+		**out = **in
+	}
+	if in.KeepSince != nil {
+		in, out := &in.KeepSince, &out.KeepSince
+		*out = new(uint)
+		**out = **in
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Statuses != nil {
+		in, out := &in.Statuses, &out.Statuses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Succeeded != nil {
+		in, out := &in.Succeeded, &out.Succeeded
+		*out = new(PruneRetention)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = new(PruneRetention)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cancelled != nil {
+		in, out := &in.Cancelled, &out.Cancelled
+		*out = new(PruneRetention)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Archive != nil {
+		in, out := &in.Archive, &out.Archive
+		*out = new(PruneArchive)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Prune.
+func (in *Prune) DeepCopy() *Prune {
+	if in == nil {
+		return nil
+	}
+	out := new(Prune)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PruneArchive) DeepCopyInto(out *PruneArchive) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(PruneArchiveS3)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PruneArchive.
+func (in *PruneArchive) DeepCopy() *PruneArchive {
+	if in == nil {
+		return nil
+	}
+	out := new(PruneArchive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PruneArchiveS3) DeepCopyInto(out *PruneArchiveS3) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PruneArchiveS3.
+func (in *PruneArchiveS3) DeepCopy() *PruneArchiveS3 {
+	if in == nil {
+		return nil
+	}
+	out := new(PruneArchiveS3)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PruneRetention) DeepCopyInto(out *PruneRetention) {
+	*out = *in
+	if in.Keep != nil {
+		in, out := &in.Keep, &out.Keep
+		*out = new(uint)
+		**out = **in
+	}
+	if in.KeepSince != nil {
+		in, out := &in.KeepSince, &out.KeepSince
+		*out = new(uint)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PruneRetention.
+func (in *PruneRetention) DeepCopy() *PruneRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(PruneRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrunerStatus) DeepCopyInto(out *PrunerStatus) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]NamespacePruneStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrunerStatus.
+func (in *PrunerStatus) DeepCopy() *PrunerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PrunerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResultConfig) DeepCopyInto(out *ResultConfig) {
+	*out = *in
+	in.ResultsAPIProperties.DeepCopyInto(&out.ResultsAPIProperties)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResultConfig.
+func (in *ResultConfig) DeepCopy() *ResultConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ResultConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResultsAPIProperties) DeepCopyInto(out *ResultsAPIProperties) {
+	*out = *in
+	if in.DB != nil {
+		in, out := &in.DB, &out.DB
+		*out = new(ResultsDB)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ResultsTLS)
+		**out = **in
+	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = new(ResultsLogs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	in.Workloads.DeepCopyInto(&out.Workloads)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResultsAPIProperties.
+func (in *ResultsAPIProperties) DeepCopy() *ResultsAPIProperties {
 	if in == nil {
 		return nil
 	}
-	out := new(OptionalPipelineProperties)
+	out := new(ResultsAPIProperties)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OptionalTriggersProperties) DeepCopyInto(out *OptionalTriggersProperties) {
+func (in *ResultsDB) DeepCopyInto(out *ResultsDB) {
 	*out = *in
+	if in.Provision != nil {
+		in, out := &in.Provision, &out.Provision
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptionalTriggersProperties.
-func (in *OptionalTriggersProperties) DeepCopy() *OptionalTriggersProperties {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResultsDB.
+func (in *ResultsDB) DeepCopy() *ResultsDB {
 	if in == nil {
 		return nil
 	}
-	out := new(OptionalTriggersProperties)
+	out := new(ResultsDB)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Param) DeepCopyInto(out *Param) {
+func (in *ResultsLogs) DeepCopyInto(out *ResultsLogs) {
 	*out = *in
+	if in.BufferSize != nil {
+		in, out := &in.BufferSize, &out.BufferSize
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Param.
-func (in *Param) DeepCopy() *Param {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResultsLogs.
+func (in *ResultsLogs) DeepCopy() *ResultsLogs {
 	if in == nil {
 		return nil
 	}
-	out := new(Param)
+	out := new(ResultsLogs)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ParamValue) DeepCopyInto(out *ParamValue) {
+func (in *ResultsTLS) DeepCopyInto(out *ResultsTLS) {
 	*out = *in
-	if in.Possible != nil {
-		in, out := &in.Possible, &out.Possible
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParamValue.
-func (in *ParamValue) DeepCopy() *ParamValue {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResultsTLS.
+func (in *ResultsTLS) DeepCopy() *ResultsTLS {
 	if in == nil {
 		return nil
 	}
-	out := new(ParamValue)
+	out := new(ResultsTLS)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Pipeline) DeepCopyInto(out *Pipeline) {
+func (in *ResultsWorkload) DeepCopyInto(out *ResultsWorkload) {
 	*out = *in
-	in.PipelineProperties.DeepCopyInto(&out.PipelineProperties)
-	if in.Params != nil {
-		in, out := &in.Params, &out.Params
-		*out = make([]Param, len(*in))
-		copy(*out, *in)
+	in.Config.DeepCopyInto(&out.Config)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pipeline.
-func (in *Pipeline) DeepCopy() *Pipeline {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResultsWorkload.
+func (in *ResultsWorkload) DeepCopy() *ResultsWorkload {
 	if in == nil {
 		return nil
 	}
-	out := new(Pipeline)
+	out := new(ResultsWorkload)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PipelineMetricsProperties) DeepCopyInto(out *PipelineMetricsProperties) {
+func (in *ResultsWorkloads) DeepCopyInto(out *ResultsWorkloads) {
 	*out = *in
+	if in.API != nil {
+		in, out := &in.API, &out.API
+		*out = new(ResultsWorkload)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Watcher != nil {
+		in, out := &in.Watcher, &out.Watcher
+		*out = new(ResultsWorkload)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Postgres != nil {
+		in, out := &in.Postgres, &out.Postgres
+		*out = new(ResultsWorkload)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineMetricsProperties.
-func (in *PipelineMetricsProperties) DeepCopy() *PipelineMetricsProperties {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResultsWorkloads.
+func (in *ResultsWorkloads) DeepCopy() *ResultsWorkloads {
 	if in == nil {
 		return nil
 	}
-	out := new(PipelineMetricsProperties)
+	out := new(ResultsWorkloads)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PipelineProperties) DeepCopyInto(out *PipelineProperties) {
+func (in *SigningKeys) DeepCopyInto(out *SigningKeys) {
 	*out = *in
-	if in.DisableAffinityAssistant != nil {
-		in, out := &in.DisableAffinityAssistant, &out.DisableAffinityAssistant
-		*out = new(bool)
-		**out = **in
-	}
-	if in.DisableCredsInit != nil {
-		in, out := &in.DisableCredsInit, &out.DisableCredsInit
-		*out = new(bool)
-		**out = **in
-	}
-	if in.AwaitSidecarReadiness != nil {
-		in, out := &in.AwaitSidecarReadiness, &out.AwaitSidecarReadiness
-		*out = new(bool)
-		**out = **in
-	}
-	if in.RunningInEnvironmentWithInjectedSidecars != nil {
-		in, out := &in.RunningInEnvironmentWithInjectedSidecars, &out.RunningInEnvironmentWithInjectedSidecars
-		*out = new(bool)
-		**out = **in
-	}
-	if in.RequireGitSshSecretKnownHosts != nil {
-		in, out := &in.RequireGitSshSecretKnownHosts, &out.RequireGitSshSecretKnownHosts
-		*out = new(bool)
-		**out = **in
-	}
-	if in.EnableTektonOciBundles != nil {
-		in, out := &in.EnableTektonOciBundles, &out.EnableTektonOciBundles
-		*out = new(bool)
-		**out = **in
-	}
-	if in.EnableCustomTasks != nil {
-		in, out := &in.EnableCustomTasks, &out.EnableCustomTasks
-		*out = new(bool)
+	if in.RotationPeriod != nil {
+		in, out := &in.RotationPeriod, &out.RotationPeriod
+		*out = new(metav1.Duration)
 		**out = **in
 	}
-	if in.SendCloudEventsForRuns != nil {
-		in, out := &in.SendCloudEventsForRuns, &out.SendCloudEventsForRuns
-		*out = new(bool)
-		**out = **in
-	}
-	if in.ScopeWhenExpressionsToTask != nil {
-		in, out := &in.ScopeWhenExpressionsToTask, &out.ScopeWhenExpressionsToTask
-		*out = new(bool)
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
 		**out = **in
 	}
-	out.PipelineMetricsProperties = in.PipelineMetricsProperties
-	in.OptionalPipelineProperties.DeepCopyInto(&out.OptionalPipelineProperties)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineProperties.
-func (in *PipelineProperties) DeepCopy() *PipelineProperties {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningKeys.
+func (in *SigningKeys) DeepCopy() *SigningKeys {
 	if in == nil {
 		return nil
 	}
-	out := new(PipelineProperties)
+	out := new(SigningKeys)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Prune) DeepCopyInto(out *Prune) {
+func (in *SigningKeysStatus) DeepCopyInto(out *SigningKeysStatus) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Keep != nil {
-		in, out := &in.Keep, &out.Keep
-		*out = new(uint)
-		**out = **in
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
 	}
-	if in.KeepSince != nil {
-		in, out := &in.KeepSince, &out.KeepSince
-		*out = new(uint)
-		**out = **in
+	if in.NextRotationTime != nil {
+		in, out := &in.NextRotationTime, &out.NextRotationTime
+		*out = (*in).DeepCopy()
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Prune.
-func (in *Prune) DeepCopy() *Prune {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningKeysStatus.
+func (in *SigningKeysStatus) DeepCopy() *SigningKeysStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Prune)
+	out := new(SigningKeysStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -489,6 +1142,11 @@ func (in *TektonAddonSpec) DeepCopyInto(out *TektonAddonSpec) {
 	out.CommonSpec = in.CommonSpec
 	in.Addon.DeepCopyInto(&out.Addon)
 	in.Config.DeepCopyInto(&out.Config)
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(AdditionalOptions)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -593,6 +1251,16 @@ func (in *TektonChainSpec) DeepCopyInto(out *TektonChainSpec) {
 	out.CommonSpec = in.CommonSpec
 	in.Chain.DeepCopyInto(&out.Chain)
 	in.Config.DeepCopyInto(&out.Config)
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(AdditionalOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SigningKeys != nil {
+		in, out := &in.SigningKeys, &out.SigningKeys
+		*out = new(SigningKeys)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -610,6 +1278,11 @@ func (in *TektonChainSpec) DeepCopy() *TektonChainSpec {
 func (in *TektonChainStatus) DeepCopyInto(out *TektonChainStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
+	if in.SigningKeys != nil {
+		in, out := &in.SigningKeys, &out.SigningKeys
+		*out = new(SigningKeysStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -687,19 +1360,38 @@ func (in *TektonConfigList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TektonConfigSpec) DeepCopyInto(out *TektonConfigSpec) {
 	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	in.Config.DeepCopyInto(&out.Config)
 	in.Pruner.DeepCopyInto(&out.Pruner)
 	out.CommonSpec = in.CommonSpec
 	in.Addon.DeepCopyInto(&out.Addon)
 	in.Hub.DeepCopyInto(&out.Hub)
 	in.Pipeline.DeepCopyInto(&out.Pipeline)
-	out.Trigger = in.Trigger
+	in.Trigger.DeepCopyInto(&out.Trigger)
 	out.Dashboard = in.Dashboard
+	in.Chain.DeepCopyInto(&out.Chain)
+	in.Result.DeepCopyInto(&out.Result)
 	if in.Params != nil {
 		in, out := &in.Params, &out.Params
 		*out = make([]Param, len(*in))
 		copy(*out, *in)
 	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(AdditionalOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(Upgrade)
+		**out = **in
+	}
 	return
 }
 
@@ -724,6 +1416,18 @@ func (in *TektonConfigStatus) DeepCopyInto(out *TektonConfigStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Pruner != nil {
+		in, out := &in.Pruner, &out.Pruner
+		*out = new(PrunerStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -804,6 +1508,11 @@ func (in *TektonDashboardSpec) DeepCopyInto(out *TektonDashboardSpec) {
 	out.CommonSpec = in.CommonSpec
 	out.DashboardProperties = in.DashboardProperties
 	in.Config.DeepCopyInto(&out.Config)
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(AdditionalOptions)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -902,6 +1611,11 @@ func (in *TektonHubSpec) DeepCopyInto(out *TektonHubSpec) {
 	in.Hub.DeepCopyInto(&out.Hub)
 	out.Db = in.Db
 	out.Api = in.Api
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(AdditionalOptions)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1032,6 +1746,16 @@ func (in *TektonInstallerSetSpec) DeepCopy() *TektonInstallerSetSpec {
 func (in *TektonInstallerSetStatus) DeepCopyInto(out *TektonInstallerSetStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = make([]InstallerSetResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]InstallerSetResourceStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1112,6 +1836,16 @@ func (in *TektonPipelineSpec) DeepCopyInto(out *TektonPipelineSpec) {
 	out.CommonSpec = in.CommonSpec
 	in.Pipeline.DeepCopyInto(&out.Pipeline)
 	in.Config.DeepCopyInto(&out.Config)
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(AdditionalOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(Upgrade)
+		**out = **in
+	}
 	return
 }
 
@@ -1149,12 +1883,129 @@ func (in *TektonPipelineStatus) DeepCopy() *TektonPipelineStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TektonPrunerPolicy) DeepCopyInto(out *TektonPrunerPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TektonPrunerPolicy.
+func (in *TektonPrunerPolicy) DeepCopy() *TektonPrunerPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TektonPrunerPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TektonPrunerPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TektonPrunerPolicyList) DeepCopyInto(out *TektonPrunerPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TektonPrunerPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TektonPrunerPolicyList.
+func (in *TektonPrunerPolicyList) DeepCopy() *TektonPrunerPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(TektonPrunerPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TektonPrunerPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TektonPrunerPolicySpec) DeepCopyInto(out *TektonPrunerPolicySpec) {
+	*out = *in
+	in.Prune.DeepCopyInto(&out.Prune)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TektonPrunerPolicySpec.
+func (in *TektonPrunerPolicySpec) DeepCopy() *TektonPrunerPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TektonPrunerPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TektonPrunerPolicyStatus) DeepCopyInto(out *TektonPrunerPolicyStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.LastPruneTime != nil {
+		in, out := &in.LastPruneTime, &out.LastPruneTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextPruneTime != nil {
+		in, out := &in.NextPruneTime, &out.NextPruneTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Deleted != nil {
+		in, out := &in.Deleted, &out.Deleted
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NotArchived != nil {
+		in, out := &in.NotArchived, &out.NotArchived
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TektonPrunerPolicyStatus.
+func (in *TektonPrunerPolicyStatus) DeepCopy() *TektonPrunerPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TektonPrunerPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TektonResult) DeepCopyInto(out *TektonResult) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -1214,6 +2065,13 @@ func (in *TektonResultList) DeepCopyObject() runtime.Object {
 func (in *TektonResultSpec) DeepCopyInto(out *TektonResultSpec) {
 	*out = *in
 	out.CommonSpec = in.CommonSpec
+	in.ResultsAPIProperties.DeepCopyInto(&out.ResultsAPIProperties)
+	in.Config.DeepCopyInto(&out.Config)
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(AdditionalOptions)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1309,8 +2167,18 @@ func (in *TektonTriggerList) DeepCopyObject() runtime.Object {
 func (in *TektonTriggerSpec) DeepCopyInto(out *TektonTriggerSpec) {
 	*out = *in
 	out.CommonSpec = in.CommonSpec
-	out.Trigger = in.Trigger
+	in.Trigger.DeepCopyInto(&out.Trigger)
 	in.Config.DeepCopyInto(&out.Config)
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(AdditionalOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(Upgrade)
+		**out = **in
+	}
 	return
 }
 
@@ -1345,6 +2213,16 @@ func (in *TektonTriggerStatus) DeepCopy() *TektonTriggerStatus {
 func (in *Trigger) DeepCopyInto(out *Trigger) {
 	*out = *in
 	out.TriggersProperties = in.TriggersProperties
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(UpgradeRollback)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstallerSet != nil {
+		in, out := &in.InstallerSet, &out.InstallerSet
+		*out = new(InstallerSetOptions)
+		**out = **in
+	}
 	return
 }
 
@@ -1374,3 +2252,40 @@ func (in *TriggersProperties) DeepCopy() *TriggersProperties {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Upgrade) DeepCopyInto(out *Upgrade) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Upgrade.
+func (in *Upgrade) DeepCopy() *Upgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(Upgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeRollback) DeepCopyInto(out *UpgradeRollback) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeRollback.
+func (in *UpgradeRollback) DeepCopy() *UpgradeRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeRollback)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -56,6 +56,10 @@ func (c *FakeOperatorV1alpha1) TektonPipelines() v1alpha1.TektonPipelineInterfac
 	return &FakeTektonPipelines{c}
 }
 
+func (c *FakeOperatorV1alpha1) TektonPrunerPolicies(namespace string) v1alpha1.TektonPrunerPolicyInterface {
+	return &FakeTektonPrunerPolicies{c, namespace}
+}
+
 func (c *FakeOperatorV1alpha1) TektonResults() v1alpha1.TektonResultInterface {
 	return &FakeTektonResults{c}
 }
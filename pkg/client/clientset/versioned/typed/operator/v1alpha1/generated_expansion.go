@@ -32,6 +32,8 @@ type TektonInstallerSetExpansion interface{}
 
 type TektonPipelineExpansion interface{}
 
+type TektonPrunerPolicyExpansion interface{}
+
 type TektonResultExpansion interface{}
 
 type TektonTriggerExpansion interface{}
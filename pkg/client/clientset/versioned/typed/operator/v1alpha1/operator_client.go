@@ -35,6 +35,7 @@ type OperatorV1alpha1Interface interface {
 	TektonHubsGetter
 	TektonInstallerSetsGetter
 	TektonPipelinesGetter
+	TektonPrunerPoliciesGetter
 	TektonResultsGetter
 	TektonTriggersGetter
 }
@@ -72,6 +73,10 @@ func (c *OperatorV1alpha1Client) TektonPipelines() TektonPipelineInterface {
 	return newTektonPipelines(c)
 }
 
+func (c *OperatorV1alpha1Client) TektonPrunerPolicies(namespace string) TektonPrunerPolicyInterface {
+	return newTektonPrunerPolicies(c, namespace)
+}
+
 func (c *OperatorV1alpha1Client) TektonResults() TektonResultInterface {
 	return newTektonResults(c)
 }
@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	scheme "github.com/tektoncd/operator/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// TektonPrunerPoliciesGetter has a method to return a TektonPrunerPolicyInterface.
+// A group's client should implement this interface.
+type TektonPrunerPoliciesGetter interface {
+	TektonPrunerPolicies(namespace string) TektonPrunerPolicyInterface
+}
+
+// TektonPrunerPolicyInterface has methods to work with TektonPrunerPolicy resources.
+type TektonPrunerPolicyInterface interface {
+	Create(ctx context.Context, tektonPrunerPolicy *v1alpha1.TektonPrunerPolicy, opts v1.CreateOptions) (*v1alpha1.TektonPrunerPolicy, error)
+	Update(ctx context.Context, tektonPrunerPolicy *v1alpha1.TektonPrunerPolicy, opts v1.UpdateOptions) (*v1alpha1.TektonPrunerPolicy, error)
+	UpdateStatus(ctx context.Context, tektonPrunerPolicy *v1alpha1.TektonPrunerPolicy, opts v1.UpdateOptions) (*v1alpha1.TektonPrunerPolicy, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.TektonPrunerPolicy, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.TektonPrunerPolicyList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.TektonPrunerPolicy, err error)
+	TektonPrunerPolicyExpansion
+}
+
+// tektonPrunerPolicies implements TektonPrunerPolicyInterface
+type tektonPrunerPolicies struct {
+	client rest.Interface
+	ns     string
+}
+
+// newTektonPrunerPolicies returns a TektonPrunerPolicies
+func newTektonPrunerPolicies(c *OperatorV1alpha1Client, namespace string) *tektonPrunerPolicies {
+	return &tektonPrunerPolicies{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the tektonPrunerPolicy, and returns the corresponding tektonPrunerPolicy object, and an error if there is any.
+func (c *tektonPrunerPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.TektonPrunerPolicy, err error) {
+	result = &v1alpha1.TektonPrunerPolicy{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("tektonprunerpolicies").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of TektonPrunerPolicies that match those selectors.
+func (c *tektonPrunerPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.TektonPrunerPolicyList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.TektonPrunerPolicyList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("tektonprunerpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested tektonPrunerPolicies.
+func (c *tektonPrunerPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("tektonprunerpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a tektonPrunerPolicy and creates it.  Returns the server's representation of the tektonPrunerPolicy, and an error, if there is any.
+func (c *tektonPrunerPolicies) Create(ctx context.Context, tektonPrunerPolicy *v1alpha1.TektonPrunerPolicy, opts v1.CreateOptions) (result *v1alpha1.TektonPrunerPolicy, err error) {
+	result = &v1alpha1.TektonPrunerPolicy{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("tektonprunerpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(tektonPrunerPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a tektonPrunerPolicy and updates it. Returns the server's representation of the tektonPrunerPolicy, and an error, if there is any.
+func (c *tektonPrunerPolicies) Update(ctx context.Context, tektonPrunerPolicy *v1alpha1.TektonPrunerPolicy, opts v1.UpdateOptions) (result *v1alpha1.TektonPrunerPolicy, err error) {
+	result = &v1alpha1.TektonPrunerPolicy{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("tektonprunerpolicies").
+		Name(tektonPrunerPolicy.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(tektonPrunerPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *tektonPrunerPolicies) UpdateStatus(ctx context.Context, tektonPrunerPolicy *v1alpha1.TektonPrunerPolicy, opts v1.UpdateOptions) (result *v1alpha1.TektonPrunerPolicy, err error) {
+	result = &v1alpha1.TektonPrunerPolicy{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("tektonprunerpolicies").
+		Name(tektonPrunerPolicy.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(tektonPrunerPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the tektonPrunerPolicy and deletes it. Returns an error if one occurs.
+func (c *tektonPrunerPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("tektonprunerpolicies").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *tektonPrunerPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("tektonprunerpolicies").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched tektonPrunerPolicy.
+func (c *tektonPrunerPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.TektonPrunerPolicy, err error) {
+	result = &v1alpha1.TektonPrunerPolicy{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("tektonprunerpolicies").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
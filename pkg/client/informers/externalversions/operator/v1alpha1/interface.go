@@ -38,6 +38,8 @@ type Interface interface {
 	TektonInstallerSets() TektonInstallerSetInformer
 	// TektonPipelines returns a TektonPipelineInformer.
 	TektonPipelines() TektonPipelineInformer
+	// TektonPrunerPolicies returns a TektonPrunerPolicyInformer.
+	TektonPrunerPolicies() TektonPrunerPolicyInformer
 	// TektonResults returns a TektonResultInformer.
 	TektonResults() TektonResultInformer
 	// TektonTriggers returns a TektonTriggerInformer.
@@ -90,6 +92,11 @@ func (v *version) TektonPipelines() TektonPipelineInformer {
 	return &tektonPipelineInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// TektonPrunerPolicies returns a TektonPrunerPolicyInformer.
+func (v *version) TektonPrunerPolicies() TektonPrunerPolicyInformer {
+	return &tektonPrunerPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TektonResults returns a TektonResultInformer.
 func (v *version) TektonResults() TektonResultInformer {
 	return &tektonResultInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -1000,6 +1000,137 @@ func (w *wrapOperatorV1alpha1TektonPipelineImpl) Watch(ctx context.Context, opts
 	return nil, errors.New("NYI: Watch")
 }
 
+func (w *wrapOperatorV1alpha1) TektonPrunerPolicies(namespace string) typedoperatorv1alpha1.TektonPrunerPolicyInterface {
+	return &wrapOperatorV1alpha1TektonPrunerPolicyImpl{
+		dyn: w.dyn.Resource(schema.GroupVersionResource{
+			Group:    "operator.tekton.dev",
+			Version:  "v1alpha1",
+			Resource: "tektonprunerpolicies",
+		}),
+
+		namespace: namespace,
+	}
+}
+
+type wrapOperatorV1alpha1TektonPrunerPolicyImpl struct {
+	dyn dynamic.NamespaceableResourceInterface
+
+	namespace string
+}
+
+var _ typedoperatorv1alpha1.TektonPrunerPolicyInterface = (*wrapOperatorV1alpha1TektonPrunerPolicyImpl)(nil)
+
+func (w *wrapOperatorV1alpha1TektonPrunerPolicyImpl) Create(ctx context.Context, in *v1alpha1.TektonPrunerPolicy, opts v1.CreateOptions) (*v1alpha1.TektonPrunerPolicy, error) {
+	in.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "operator.tekton.dev",
+		Version: "v1alpha1",
+		Kind:    "TektonPrunerPolicy",
+	})
+	uo := &unstructured.Unstructured{}
+	if err := convert(in, uo); err != nil {
+		return nil, err
+	}
+	uo, err := w.dyn.Namespace(w.namespace).Create(ctx, uo, opts)
+	if err != nil {
+		return nil, err
+	}
+	out := &v1alpha1.TektonPrunerPolicy{}
+	if err := convert(uo, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (w *wrapOperatorV1alpha1TektonPrunerPolicyImpl) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return w.dyn.Namespace(w.namespace).Delete(ctx, name, opts)
+}
+
+func (w *wrapOperatorV1alpha1TektonPrunerPolicyImpl) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	return w.dyn.Namespace(w.namespace).DeleteCollection(ctx, opts, listOpts)
+}
+
+func (w *wrapOperatorV1alpha1TektonPrunerPolicyImpl) Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.TektonPrunerPolicy, error) {
+	uo, err := w.dyn.Namespace(w.namespace).Get(ctx, name, opts)
+	if err != nil {
+		return nil, err
+	}
+	out := &v1alpha1.TektonPrunerPolicy{}
+	if err := convert(uo, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (w *wrapOperatorV1alpha1TektonPrunerPolicyImpl) List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.TektonPrunerPolicyList, error) {
+	uo, err := w.dyn.Namespace(w.namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	out := &v1alpha1.TektonPrunerPolicyList{}
+	if err := convert(uo, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (w *wrapOperatorV1alpha1TektonPrunerPolicyImpl) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.TektonPrunerPolicy, err error) {
+	uo, err := w.dyn.Namespace(w.namespace).Patch(ctx, name, pt, data, opts)
+	if err != nil {
+		return nil, err
+	}
+	out := &v1alpha1.TektonPrunerPolicy{}
+	if err := convert(uo, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (w *wrapOperatorV1alpha1TektonPrunerPolicyImpl) Update(ctx context.Context, in *v1alpha1.TektonPrunerPolicy, opts v1.UpdateOptions) (*v1alpha1.TektonPrunerPolicy, error) {
+	in.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "operator.tekton.dev",
+		Version: "v1alpha1",
+		Kind:    "TektonPrunerPolicy",
+	})
+	uo := &unstructured.Unstructured{}
+	if err := convert(in, uo); err != nil {
+		return nil, err
+	}
+	uo, err := w.dyn.Namespace(w.namespace).Update(ctx, uo, opts)
+	if err != nil {
+		return nil, err
+	}
+	out := &v1alpha1.TektonPrunerPolicy{}
+	if err := convert(uo, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (w *wrapOperatorV1alpha1TektonPrunerPolicyImpl) UpdateStatus(ctx context.Context, in *v1alpha1.TektonPrunerPolicy, opts v1.UpdateOptions) (*v1alpha1.TektonPrunerPolicy, error) {
+	in.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "operator.tekton.dev",
+		Version: "v1alpha1",
+		Kind:    "TektonPrunerPolicy",
+	})
+	uo := &unstructured.Unstructured{}
+	if err := convert(in, uo); err != nil {
+		return nil, err
+	}
+	uo, err := w.dyn.Namespace(w.namespace).UpdateStatus(ctx, uo, opts)
+	if err != nil {
+		return nil, err
+	}
+	out := &v1alpha1.TektonPrunerPolicy{}
+	if err := convert(uo, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (w *wrapOperatorV1alpha1TektonPrunerPolicyImpl) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return nil, errors.New("NYI: Watch")
+}
+
 func (w *wrapOperatorV1alpha1) TektonResults() typedoperatorv1alpha1.TektonResultInterface {
 	return &wrapOperatorV1alpha1TektonResultImpl{
 		dyn: w.dyn.Resource(schema.GroupVersionResource{
@@ -46,6 +46,14 @@ type TektonInstallerSetListerExpansion interface{}
 // TektonPipelineLister.
 type TektonPipelineListerExpansion interface{}
 
+// TektonPrunerPolicyListerExpansion allows custom methods to be added to
+// TektonPrunerPolicyLister.
+type TektonPrunerPolicyListerExpansion interface{}
+
+// TektonPrunerPolicyNamespaceListerExpansion allows custom methods to be added to
+// TektonPrunerPolicyNamespaceLister.
+type TektonPrunerPolicyNamespaceListerExpansion interface{}
+
 // TektonResultListerExpansion allows custom methods to be added to
 // TektonResultLister.
 type TektonResultListerExpansion interface{}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// TektonPrunerPolicyLister helps list TektonPrunerPolicies.
+// All objects returned here must be treated as read-only.
+type TektonPrunerPolicyLister interface {
+	// List lists all TektonPrunerPolicies in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.TektonPrunerPolicy, err error)
+	// TektonPrunerPolicies returns an object that can list and get TektonPrunerPolicies.
+	TektonPrunerPolicies(namespace string) TektonPrunerPolicyNamespaceLister
+	TektonPrunerPolicyListerExpansion
+}
+
+// tektonPrunerPolicyLister implements the TektonPrunerPolicyLister interface.
+type tektonPrunerPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewTektonPrunerPolicyLister returns a new TektonPrunerPolicyLister.
+func NewTektonPrunerPolicyLister(indexer cache.Indexer) TektonPrunerPolicyLister {
+	return &tektonPrunerPolicyLister{indexer: indexer}
+}
+
+// List lists all TektonPrunerPolicies in the indexer.
+func (s *tektonPrunerPolicyLister) List(selector labels.Selector) (ret []*v1alpha1.TektonPrunerPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.TektonPrunerPolicy))
+	})
+	return ret, err
+}
+
+// TektonPrunerPolicies returns an object that can list and get TektonPrunerPolicies.
+func (s *tektonPrunerPolicyLister) TektonPrunerPolicies(namespace string) TektonPrunerPolicyNamespaceLister {
+	return tektonPrunerPolicyNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// TektonPrunerPolicyNamespaceLister helps list and get TektonPrunerPolicies.
+// All objects returned here must be treated as read-only.
+type TektonPrunerPolicyNamespaceLister interface {
+	// List lists all TektonPrunerPolicies in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.TektonPrunerPolicy, err error)
+	// Get retrieves the TektonPrunerPolicy from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.TektonPrunerPolicy, error)
+	TektonPrunerPolicyNamespaceListerExpansion
+}
+
+// tektonPrunerPolicyNamespaceLister implements the TektonPrunerPolicyNamespaceLister
+// interface.
+type tektonPrunerPolicyNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all TektonPrunerPolicies in the indexer for a given namespace.
+func (s tektonPrunerPolicyNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.TektonPrunerPolicy, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.TektonPrunerPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the TektonPrunerPolicy from the indexer for a given namespace and name.
+func (s tektonPrunerPolicyNamespaceLister) Get(name string) (*v1alpha1.TektonPrunerPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("tektonprunerpolicy"), name)
+	}
+	return obj.(*v1alpha1.TektonPrunerPolicy), nil
+}
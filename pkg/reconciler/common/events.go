@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/controller"
+)
+
+// Reasons of the events emitted on the lifecycle of the components
+const (
+	EventInstallStarted        = "InstallStarted"
+	EventUpgradeStarted        = "UpgradeStarted"
+	EventUpgradeSucceeded      = "UpgradeSucceeded"
+	EventUpgradeFailed         = "UpgradeFailed"
+	EventDriftCorrected        = "DriftCorrected"
+	EventResourcesDrifted      = "ResourcesDrifted"
+	EventInstallerSetRecreated = "InstallerSetRecreated"
+	EventPruneFailed           = "PruneFailed"
+	EventPruneNotArchived      = "PruneNotArchived"
+	EventSigningKeysRotated    = "SigningKeysRotated"
+	EventCertificateGenerated  = "CertificateGenerated"
+)
+
+// RecordEvent emits an event on obj through the recorder of the reconciler
+// in ctx, it does nothing outside a reconcile
+func RecordEvent(ctx context.Context, obj interface{}, eventType, reason, messageFmt string, args ...interface{}) {
+	recorder := controller.GetEventRecorder(ctx)
+	object, ok := obj.(runtime.Object)
+	if recorder == nil || !ok {
+		return
+	}
+	recorder.Eventf(object, eventType, reason, messageFmt, args...)
+}
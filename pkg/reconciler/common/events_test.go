@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/controller"
+)
+
+func TestRecordEvent(t *testing.T) {
+	recorder := record.NewFakeRecorder(2)
+	ctx := controller.WithEventRecorder(context.Background(), recorder)
+
+	RecordEvent(ctx, &v1alpha1.TektonPipeline{}, corev1.EventTypeNormal, EventUpgradeStarted,
+		"Upgrading from release %s to %s", "v0.1.0", "v0.2.0")
+	assert.Equal(t, <-recorder.Events, "Normal UpgradeStarted Upgrading from release v0.1.0 to v0.2.0")
+
+	// objects which are not runtime objects and contexts without a recorder are ignored
+	RecordEvent(ctx, "not-an-object", corev1.EventTypeNormal, EventInstallStarted, "ignored")
+	RecordEvent(context.Background(), &v1alpha1.TektonPipeline{}, corev1.EventTypeNormal, EventInstallStarted, "ignored")
+	assert.Equal(t, len(recorder.Events), 0)
+}
@@ -18,9 +18,11 @@ package common
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	mfc "github.com/manifestival/client-go-client"
 	mf "github.com/manifestival/manifestival"
@@ -32,6 +34,16 @@ import (
 
 const ReleaseVersionUnknown = "Unknown"
 
+var (
+	payloadsMu sync.Mutex
+	payloads   = map[string]payload{}
+)
+
+type payload struct {
+	manifest mf.Manifest
+	version  string
+}
+
 type Controller struct {
 	Manifest         *mf.Manifest
 	Logger           *zap.SugaredLogger
@@ -84,6 +96,48 @@ func (ctrl Controller) InitController(ctx context.Context, opts PayloadOptions)
 	return manifest, releaseVersion
 }
 
+// TargetPayload returns the manifest and the release version of component to
+// install for the version pinned in spec.version of instance. The manifest
+// and version loaded by InitController are returned if no version is pinned
+func (ctrl Controller) TargetPayload(instance v1alpha1.TektonComponent, manifest mf.Manifest, version string) (mf.Manifest, string, error) {
+	pinned := pinnedVersion(instance)
+	if pinned == "" || pinned == latestRelease(instance) {
+		return manifest, version, nil
+	}
+
+	key := filepath.Join(ComponentDir(instance), pinned)
+	payloadsMu.Lock()
+	defer payloadsMu.Unlock()
+	if p, ok := payloads[key]; ok {
+		return p.manifest, p.version, nil
+	}
+
+	path := manifestPath(pinned, instance)
+	if path == "" {
+		return manifest, version, fmt.Errorf("version %s is not shipped with the operator", pinned)
+	}
+	pinnedManifest, err := mf.NewManifest(path, mf.UseClient(manifest.Client))
+	if err != nil {
+		return manifest, version, err
+	}
+	if strings.Contains(ctrl.VersionConfigMap, "pipeline") {
+		if err := addProxy(&pinnedManifest); err != nil {
+			return manifest, version, err
+		}
+	}
+
+	releaseVersion, err := FetchVersionFromConfigMap(pinnedManifest, ctrl.VersionConfigMap)
+	if err != nil {
+		if !IsFetchVersionError(err) {
+			return manifest, version, err
+		}
+		releaseVersion = ReleaseVersionUnknown
+	}
+
+	payloads[key] = payload{manifest: pinnedManifest, version: releaseVersion}
+	return pinnedManifest, releaseVersion, nil
+}
+
 // fetchSourceManifests mutates the passed manifest by appending one
 // appropriate for the passed TektonComponent
 func (ctrl Controller) fetchSourceManifests(ctx context.Context, opts PayloadOptions) error {
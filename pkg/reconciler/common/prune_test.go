@@ -18,25 +18,22 @@ package common
 
 import (
 	"context"
-	"errors"
-	"os"
-	"strings"
 	"testing"
 
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	"gotest.tools/v3/assert"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
 const (
-	scheduleCommon  = "*/2 * * * *"
-	scheduleUnique  = "*/4 * * * *"
-	scheduleUnique2 = "*/6 * * * *"
+	scheduleCommon = "*/2 * * * *"
+	scheduleUnique = "*/4 * * * *"
 )
 
-func TestGetPrunableNamespaces(t *testing.T) {
+func TestPruneConfigs(t *testing.T) {
 	keep := uint(3)
 	anno1 := map[string]string{pruneSchedule: scheduleCommon}
 	anno2 := map[string]string{pruneSchedule: scheduleUnique}
@@ -45,8 +42,12 @@ func TestGetPrunableNamespaces(t *testing.T) {
 		Keep:      &keep,
 		Schedule:  scheduleCommon,
 	}
-	expected1 := map[string]struct{}{"ns-one": {}, "ns-two": {}, "ns-three": {}}
-	expected2 := map[string]struct{}{"ns-four": {}}
+	expected := map[string]string{
+		"ns-one":   scheduleCommon,
+		"ns-two":   scheduleCommon,
+		"ns-three": scheduleCommon,
+		"ns-four":  scheduleUnique,
+	}
 
 	client := fake.NewSimpleClientset(
 		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-api"}},
@@ -58,380 +59,65 @@ func TestGetPrunableNamespaces(t *testing.T) {
 		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-three", Annotations: anno1}},
 		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-four", Annotations: anno2}},
 	)
-	pruningNamespaces, err := prunableNamespaces(context.TODO(), client, defaultPrune)
-	if err != nil {
-		assert.Error(t, err, "unable to get ns list")
-	}
-	assert.Equal(t, len(expected1), len(pruningNamespaces.commonScheduleNs))
-	for ns := range pruningNamespaces.commonScheduleNs {
-		if _, ok := expected1[ns]; !ok {
-			assert.Error(t, errors.New("namespace not found"), ns)
-		}
-	}
-	assert.Equal(t, len(expected2), len(pruningNamespaces.uniqueScheduleNS))
-	for ns := range pruningNamespaces.uniqueScheduleNS {
-		if _, ok := expected2[ns]; !ok {
-			assert.Error(t, errors.New("namespace not found"), ns)
-		}
+	configs, err := PruneConfigs(context.TODO(), client, defaultPrune)
+	assert.NilError(t, err)
+	assert.Equal(t, len(expected), len(configs))
+	for ns, schedule := range expected {
+		config, ok := configs[ns]
+		assert.Assert(t, ok, "namespace %s not found", ns)
+		assert.Equal(t, config.Schedule, schedule)
 	}
 }
 
-func TestCompleteFlowPrune(t *testing.T) {
-
+func TestPruneConfigsAnnotations(t *testing.T) {
 	keep := uint(3)
-	anno1 := map[string]string{pruneSchedule: scheduleCommon}
-	anno2 := map[string]string{pruneSchedule: scheduleUnique}
-	anno4 := map[string]string{pruneSchedule: scheduleCommon}
-
-	defaultPrune := &v1alpha1.Prune{
-		Resources: []string{"pipelinerun"},
-		Keep:      &keep,
-		Schedule:  scheduleCommon,
-	}
-	config := &v1alpha1.TektonConfig{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "config",
-		},
-		Spec: v1alpha1.TektonConfigSpec{
-			Profile:    "all",
-			Pruner:     *defaultPrune,
-			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "openshift-pipelines"},
-		},
-	}
-	client := fake.NewSimpleClientset(
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-pipelines"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-api"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-api-url"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-api"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-one"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-two", Annotations: anno2}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-three", Annotations: anno1}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-four", Annotations: anno4}},
-	)
-	os.Setenv(JobsTKNImageName, "some")
-	defer os.Unsetenv(JobsTKNImageName)
-
-	err := Prune(context.TODO(), client, config)
-	if err != nil {
-		assert.Error(t, err, "unable to initiate prune")
-	}
-	cronjobs, err := client.BatchV1().CronJobs("").List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		assert.Error(t, err, "unable to get cronjobs ")
-	}
-	// Only one ns with unique schedule than default
-	if len(cronjobs.Items) != 2 {
-		assert.Error(t, err, "number of cronjobs not correct")
-	}
-	if _, err := client.CoreV1().Namespaces().Update(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-two"}}, metav1.UpdateOptions{}); err != nil {
-		assert.Error(t, err, "unexpected error")
-	}
-
-	err = Prune(context.TODO(), client, config)
-	if err != nil {
-		assert.Error(t, err, "unable to initiate prune")
-	}
-	cronjobs1, err := client.BatchV1().CronJobs("openshift-pipelines").List(context.TODO(), metav1.ListOptions{})
-	if len(cronjobs1.Items) != 1 {
-		assert.Error(t, err, "number of cronjobs not correct")
-	}
-}
-
-func TestPruneCommands(t *testing.T) {
-	keep := uint(2)
-	keepsince := uint(300)
-	expected := []string{
-		"ns;--keep=2;pipelinerun,taskrun",
-		"ns;--keep-since=300;pipelinerun,taskrun",
-	}
-	ns := "ns"
-	configs := []*pruneConfigPerNS{
-		{
-			config: v1alpha1.Prune{
-				Resources: []string{"pipelinerun", "taskrun"},
-				Keep:      &keep,
-				KeepSince: nil,
-				Schedule:  scheduleCommon,
-			},
-		},
-		{
-			config: v1alpha1.Prune{
-				Resources: []string{"pipelinerun", "taskrun"},
-				Keep:      nil,
-				KeepSince: &keepsince,
-				Schedule:  scheduleCommon,
-			},
-		},
-	}
-
-	for i, config := range configs {
-		cmd := generatePruneConfigPerNamespace(config, ns)
-		assert.Equal(t, cmd, expected[i])
-	}
-}
-
-func TestAnnotationCmd(t *testing.T) {
-	keep := uint(3)
-	annoUniqueSchedule := map[string]string{pruneSchedule: scheduleUnique}
-	annoCommonSchedule := map[string]string{pruneSchedule: scheduleCommon}
-	annoStrategyKeepSince := map[string]string{pruneStrategy: "keep-since", pruneKeepSince: "3200"}
-	annoStrategyKeep := map[string]string{pruneStrategy: "keep", pruneKeep: "50"}
-	annoKeepSinceAndKeep := map[string]string{pruneKeepSince: "3200", pruneKeep: "5"}
-	annoResourceTr := map[string]string{pruneResources: "taskrun"}
-	annoResourceTrPr := map[string]string{pruneResources: "taskrun, pipelinerun"}
-	annoSkip := map[string]string{pruneSkip: "true"}
-	defaultPrune := &v1alpha1.Prune{
+	defaultPrune := v1alpha1.Prune{
 		Resources: []string{"pipelinerun"},
 		Keep:      &keep,
 		Schedule:  scheduleCommon,
 	}
-	config := &v1alpha1.TektonConfig{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "config",
-		},
-		Spec: v1alpha1.TektonConfigSpec{
-			Profile:    "all",
-			Pruner:     *defaultPrune,
-			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "openshift-pipelines"},
-		},
-	}
 	client := fake.NewSimpleClientset(
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-api"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-api-url"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-api"}},
 		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-one"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-two", Annotations: annoUniqueSchedule}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-four", Annotations: annoCommonSchedule}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-six", Annotations: annoSkip}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-seven", Annotations: annoStrategyKeepSince}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-eight", Annotations: annoKeepSinceAndKeep}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-nine", Annotations: annoResourceTr}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-ten", Annotations: annoResourceTrPr}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-thirteen", Annotations: annoStrategyKeep}},
-	)
-	os.Setenv(JobsTKNImageName, "some")
-	defer os.Unsetenv(JobsTKNImageName)
-
-	err := Prune(context.TODO(), client, config)
-	if err != nil {
-		assert.Error(t, err, "pruning failed ")
-	}
-	cronjobs, err := client.BatchV1().CronJobs("").List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		assert.Error(t, err, "unable to get ns list")
-	}
-	// Only one ns with unique schedule than default
-	if len(cronjobs.Items) != 2 {
-		assert.Error(t, err, "number of cronjobs created is not right")
-	}
-	expected := map[string]struct{}{
-		"ns-two;--keep=3;pipelinerun":            {},
-		"ns-eight;--keep=5;pipelinerun":          {},
-		"ns-four;--keep=3;pipelinerun":           {},
-		"ns-nine;--keep=3;taskrun":               {},
-		"ns-one;--keep=3;pipelinerun":            {},
-		"ns-seven;--keep-since=3200;pipelinerun": {},
-		"ns-ten;--keep=3;taskrun,pipelinerun":    {},
-		"ns-thirteen;--keep=50;pipelinerun":      {},
-	}
-	for _, cronjob := range cronjobs.Items {
-		assert.Equal(t, len(cronjob.Spec.JobTemplate.Spec.Template.Spec.Containers), 1)
-		for _, container := range cronjob.Spec.JobTemplate.Spec.Template.Spec.Containers {
-			args := strings.Split(container.Args[1], " ")
-			if len(args) == 1 {
-				if _, ok := expected[args[0]]; !ok {
-					assert.Error(t, err, "expected args not found")
-				}
-			}
-			for _, arg := range args[1:] {
-				if _, ok := expected[arg]; !ok {
-					assert.Error(t, errors.New("not found"), "expected command not found")
-				}
-			}
-		}
-	}
-}
-
-// test the cronjob creation with only relevant config change
-func TestConfigChange(t *testing.T) {
-	keep := uint(3)
-	anno1 := map[string]string{pruneKeep: "200"}
-
-	defaultPrune := &v1alpha1.Prune{
-		Resources: []string{"pipelinerun"},
-		Keep:      &keep,
-		Schedule:  scheduleCommon,
-	}
-
-	config := &v1alpha1.TektonConfig{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "config",
-		},
-		Spec: v1alpha1.TektonConfigSpec{
-			Profile:    "all",
-			Pruner:     *defaultPrune,
-			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "openshift-pipelines"},
-		},
-	}
-
-	client := fake.NewSimpleClientset(
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-pipelines"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-api"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-api-url"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-api"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-one", Annotations: anno1}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-two", Annotations: map[string]string{pruneSchedule: scheduleUnique}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-six", Annotations: map[string]string{pruneSkip: "true"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-seven", Annotations: map[string]string{pruneStrategy: "keep-since", pruneKeepSince: "3200"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-eight", Annotations: map[string]string{pruneKeepSince: "3200", pruneKeep: "5"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-nine", Annotations: map[string]string{pruneResources: "taskrun"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-ten", Annotations: map[string]string{pruneResources: "taskrun, pipelinerun"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-thirteen", Annotations: map[string]string{pruneStrategy: "keep", pruneKeep: "50"}}},
 	)
-	os.Setenv(JobsTKNImageName, "some")
-	defer os.Unsetenv(JobsTKNImageName)
-
-	err := Prune(context.TODO(), client, config)
-	if err != nil {
-		assert.Error(t, err, "unable to initiate prune")
-	}
-	cronjobs, err := client.BatchV1().CronJobs("").List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		assert.Error(t, err, "unable to get cronjobs ")
-	}
-	oldCronName := cronjobs.Items[0].Name
-	// changes unrelated to prune config, should not give new cron
-	if _, err := client.CoreV1().Namespaces().Update(context.TODO(),
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-one",
-			Annotations: map[string]string{pruneKeep: "200", "garbage": "annotation",
-				pruneLastAppliedHash: "dced6f128ff38cd2686fcc2ddc3f38ff9c45f4ea3fddad0f99e722659ca57694"}}}, metav1.UpdateOptions{}); err != nil {
-		assert.Error(t, err, "unexpected error")
-	}
-
-	err = Prune(context.TODO(), client, config)
-	if err != nil {
-		assert.Error(t, err, "unable to initiate prune")
-	}
-	cronjobUnchanged, err := client.BatchV1().CronJobs("").List(context.TODO(), metav1.ListOptions{})
-	if cronjobUnchanged.Items[0].Name != oldCronName {
-		assert.Error(t, err, "cronjob was recreated")
-	}
-
-	// changes related to prune config, should give new cron
-	if _, err := client.CoreV1().Namespaces().Update(context.TODO(),
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-one",
-			Annotations: map[string]string{pruneKeep: "200", pruneResources: "pipelinerun, taskrun",
-				pruneLastAppliedHash: "dced6f128ff38cd2686fcc2ddc3f38ff9c45f4ea3fddad0f99e722659ca57694"}}}, metav1.UpdateOptions{}); err != nil {
-		assert.Error(t, err, "unexpected error")
-	}
-
-	err = Prune(context.TODO(), client, config)
-	if err != nil {
-		assert.Error(t, err, "unable to initiate prune")
-	}
-	cronjobChanged, err := client.BatchV1().CronJobs("").List(context.TODO(), metav1.ListOptions{})
-	if oldCronName == cronjobChanged.Items[0].Name {
-		assert.Error(t, err, "a new cronjob expected")
-	}
+	configs, err := PruneConfigs(context.TODO(), client, defaultPrune)
+	assert.NilError(t, err)
+
+	uintPtr := func(i uint) *uint { return &i }
+	expected := map[string]v1alpha1.Prune{
+		"ns-one":      {Resources: []string{"pipelinerun"}, Keep: uintPtr(3), Schedule: scheduleCommon},
+		"ns-two":      {Resources: []string{"pipelinerun"}, Keep: uintPtr(3), Schedule: scheduleUnique},
+		"ns-seven":    {Resources: []string{"pipelinerun"}, KeepSince: uintPtr(3200), Schedule: scheduleCommon},
+		"ns-eight":    {Resources: []string{"pipelinerun"}, Keep: uintPtr(5), Schedule: scheduleCommon},
+		"ns-nine":     {Resources: []string{"taskrun"}, Keep: uintPtr(3), Schedule: scheduleCommon},
+		"ns-ten":      {Resources: []string{"taskrun", "pipelinerun"}, Keep: uintPtr(3), Schedule: scheduleCommon},
+		"ns-thirteen": {Resources: []string{"pipelinerun"}, Keep: uintPtr(50), Schedule: scheduleCommon},
+	}
+	assert.DeepEqual(t, configs, expected)
 }
 
-// test the cronjob creation when NodeSelector and Toleration changes
-func TestNodeSelectorOrTolerationsChange(t *testing.T) {
-	keep := uint(3)
-	annoUniqueSchedule := map[string]string{pruneSchedule: scheduleUnique}
-	annoUniqueSchedule2 := map[string]string{pruneSchedule: scheduleUnique2}
-	defaultPrune := &v1alpha1.Prune{
-		Resources: []string{"pipelinerun"},
-		Keep:      &keep,
-		Schedule:  scheduleCommon,
-	}
-	tektonConfigSpecConfig := v1alpha1.Config{
-		NodeSelector: map[string]string{
-			"foo": "bar",
-		},
-		Tolerations: []corev1.Toleration{
-			{
-				Key:      "foo",
-				Operator: "equals",
-				Value:    "bar",
-				Effect:   "noSchedule",
-			},
-		},
-	}
-	tektonConfigSpecConfigEmpty := v1alpha1.Config{}
-
-	config1 := &v1alpha1.TektonConfig{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "config",
-		},
-		Spec: v1alpha1.TektonConfigSpec{
-			Profile:    "all",
-			Pruner:     *defaultPrune,
-			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "openshift-pipelines"},
-			Config:     tektonConfigSpecConfig,
-		},
-	}
-
-	config2 := &v1alpha1.TektonConfig{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "config",
-		},
-		Spec: v1alpha1.TektonConfigSpec{
-			Profile:    "all",
-			Pruner:     *defaultPrune,
-			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "openshift-pipelines"},
-			Config:     tektonConfigSpecConfigEmpty,
-		},
-	}
+func TestDeletePruneCronJobs(t *testing.T) {
 	client := fake.NewSimpleClientset(
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-pipelines"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-api"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-api-url"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-api"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-one"}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-two", Annotations: annoUniqueSchedule}},
-		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-three", Annotations: annoUniqueSchedule2}},
+		&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "resource-pruner", Namespace: "tekton-pipelines"}},
+		&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "tekton-resource-pruner-abcde", Namespace: "tekton-pipelines",
+			Labels: map[string]string{pruneCronLabel: "true"}}},
+		&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "tekton-pipelines"}},
 	)
-	os.Setenv(JobsTKNImageName, "some")
-	defer os.Unsetenv(JobsTKNImageName)
-
-	// Pruning with the nodes and Tolerations
-	err := Prune(context.TODO(), client, config1)
-	if err != nil {
-		assert.Error(t, err, "unable to initiate prune")
-	}
-
-	oldCronjobs, err := client.BatchV1().CronJobs("").List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		assert.Error(t, err, "unable to get cronjobs ")
-	}
-	oldCronList := []string{}
-	for _, cronjob := range oldCronjobs.Items {
-		oldCronList = append(oldCronList, cronjob.Name)
-	}
 
-	// Pruning after removing nodes and Tolerations
-	err = Prune(context.TODO(), client, config2)
-	if err != nil {
-		assert.Error(t, err, "unable to initiate prune")
-	}
+	err := DeletePruneCronJobs(context.TODO(), client, "tekton-pipelines")
+	assert.NilError(t, err)
 
-	newCronjobs, err := client.BatchV1().CronJobs("").List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		assert.Error(t, err, "unable to get cronjobs ")
-	}
-	newCronList := []string{}
-	for _, cronjob := range newCronjobs.Items {
-		newCronList = append(newCronList, cronjob.Name)
-	}
-	assert.Assert(t, len(oldCronList) == len(newCronList), "Number of cronJobs should be same after nodeselector and Toleration config change")
-	// checking if config change recreated all the cron Jobs
-	cronjobChanged := true
-	for _, oldCronName := range oldCronList {
-		for _, newCronName := range newCronList {
-			if oldCronName == newCronName {
-				cronjobChanged = false
-				break
-			}
-		}
-		assert.Assert(t, cronjobChanged, "nodeselector and Toleration Config change should recreate all the cron Jobs")
-	}
+	cronJobs, err := client.BatchV1().CronJobs("tekton-pipelines").List(context.TODO(), metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(cronJobs.Items), 1)
+	assert.Equal(t, cronJobs.Items[0].Name, "unrelated")
 
+	// nothing left to delete
+	assert.NilError(t, DeletePruneCronJobs(context.TODO(), client, "tekton-pipelines"))
 }
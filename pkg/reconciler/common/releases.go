@@ -41,9 +41,58 @@ var cache = map[string]mf.Manifest{}
 // per the spec in the component. If spec.version is empty, the latest
 // version known to the operator is returned.
 func TargetVersion(instance v1alpha1.TektonComponent) string {
+	if version := pinnedVersion(instance); version != "" {
+		return version
+	}
 	return latestRelease(instance)
 }
 
+// DesiredVersion returns the TargetVersion of a component, or an empty
+// string when no release of the component is shipped with the operator
+func DesiredVersion(instance v1alpha1.TektonComponent) string {
+	if version := pinnedVersion(instance); version != "" {
+		return version
+	}
+	versions, err := allReleases(instance)
+	if err != nil {
+		return ""
+	}
+	return versions[0]
+}
+
+// pinnedVersion returns the version in spec.version of the components
+// which support installing a version other than the latest
+func pinnedVersion(instance v1alpha1.TektonComponent) string {
+	switch ins := instance.(type) {
+	case *v1alpha1.TektonPipeline:
+		if ins != nil {
+			return ins.Spec.Version
+		}
+	case *v1alpha1.TektonTrigger:
+		if ins != nil {
+			return ins.Spec.Version
+		}
+	}
+	return ""
+}
+
+// ShippedVersions returns the versions of components shipped with the
+// operator which can be pinned in spec.version, keyed by the kind of
+// component. Components without any version under kodata are skipped
+func ShippedVersions() map[string][]string {
+	shipped := map[string][]string{}
+	components := map[string]v1alpha1.TektonComponent{
+		v1alpha1.KindTektonPipeline: &v1alpha1.TektonPipeline{},
+		v1alpha1.KindTektonTrigger:  &v1alpha1.TektonTrigger{},
+	}
+	for kind, instance := range components {
+		if versions, err := allReleases(instance); err == nil {
+			shipped[kind] = versions
+		}
+	}
+	return shipped
+}
+
 // TargetManifest returns the manifest for the TargetVersion
 func TargetManifest(instance v1alpha1.TektonComponent) (mf.Manifest, error) {
 	return Fetch(manifestPath(TargetVersion(instance), instance))
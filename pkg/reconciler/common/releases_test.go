@@ -49,6 +49,62 @@ func TestListReleases(t *testing.T) {
 	util.AssertDeepEqual(t, version, expectedVersionList)
 }
 
+func TestTargetVersion(t *testing.T) {
+	koPath := "testdata/kodata"
+	os.Setenv(KoEnvKey, koPath)
+	defer os.Unsetenv(KoEnvKey)
+
+	util.AssertEqual(t, TargetVersion(&v1alpha1.TektonTrigger{}), VERSION)
+	util.AssertEqual(t, TargetVersion((*v1alpha1.TektonTrigger)(nil)), VERSION)
+
+	trigger := &v1alpha1.TektonTrigger{}
+	trigger.Spec.Version = "0.14.3"
+	util.AssertEqual(t, TargetVersion(trigger), "0.14.3")
+}
+
+func TestDesiredVersion(t *testing.T) {
+	koPath := "testdata/kodata"
+	os.Setenv(KoEnvKey, koPath)
+	defer os.Unsetenv(KoEnvKey)
+
+	util.AssertEqual(t, DesiredVersion(&v1alpha1.TektonTrigger{}), VERSION)
+
+	trigger := &v1alpha1.TektonTrigger{}
+	trigger.Spec.Version = "0.14.3"
+	util.AssertEqual(t, DesiredVersion(trigger), "0.14.3")
+
+	// no release of chains is shipped under testdata
+	util.AssertEqual(t, DesiredVersion(&v1alpha1.TektonChain{}), "")
+}
+
+func TestTargetPayload(t *testing.T) {
+	koPath := "testdata/kodata"
+	os.Setenv(KoEnvKey, koPath)
+	defer os.Unsetenv(KoEnvKey)
+
+	ctrl := Controller{VersionConfigMap: "triggers-info"}
+	latest, err := TargetManifest(&v1alpha1.TektonTrigger{})
+	util.AssertEqual(t, err, nil)
+
+	trigger := &v1alpha1.TektonTrigger{}
+	manifest, version, err := ctrl.TargetPayload(trigger, latest, "v0.15.2")
+	util.AssertEqual(t, err, nil)
+	util.AssertEqual(t, version, "v0.15.2")
+	util.AssertDeepEqual(t, manifest.Resources(), latest.Resources())
+
+	trigger.Spec.Version = "0.14.3"
+	manifest, version, err = ctrl.TargetPayload(trigger, latest, "v0.15.2")
+	util.AssertEqual(t, err, nil)
+	util.AssertEqual(t, version, ReleaseVersionUnknown)
+	pinned, err := Fetch(manifestPath("0.14.3", trigger))
+	util.AssertEqual(t, err, nil)
+	util.AssertDeepEqual(t, manifest.Resources(), pinned.Resources())
+
+	trigger.Spec.Version = "0.10.0"
+	_, _, err = ctrl.TargetPayload(trigger, latest, "v0.15.2")
+	util.AssertEqual(t, err.Error(), "version 0.10.0 is not shipped with the operator")
+}
+
 func TestAppendManifest(t *testing.T) {
 
 	// Case 1
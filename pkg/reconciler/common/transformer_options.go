@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// AddOptions merges the overrides in options into the ConfigMaps and
+// Deployments with the same name. It is to be run after the other
+// transformers, so that the overrides take precedence
+func AddOptions(options *v1alpha1.AdditionalOptions) mf.Transformer {
+	configMaps := AddConfigMapOverrides(options)
+	deployments := AddDeploymentOverrides(options)
+	return func(u *unstructured.Unstructured) error {
+		if err := configMaps(u); err != nil {
+			return err
+		}
+		return deployments(u)
+	}
+}
+
+// AddConfigMapOverrides merges the data of the config map overrides in
+// options into the ConfigMaps with the same name
+func AddConfigMapOverrides(options *v1alpha1.AdditionalOptions) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if options == nil || u.GetKind() != "ConfigMap" {
+			return nil
+		}
+		override, ok := options.ConfigMaps[u.GetName()]
+		if !ok || len(override.Data) == 0 {
+			return nil
+		}
+
+		cm := &corev1.ConfigMap{}
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, cm)
+		if err != nil {
+			return err
+		}
+
+		cm.Data = mergeMaps(cm.Data, override.Data)
+
+		unstrObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cm)
+		if err != nil {
+			return err
+		}
+		u.SetUnstructuredContent(unstrObj)
+
+		return nil
+	}
+}
+
+// AddDeploymentOverrides merges the deployment overrides in options into the
+// Deployments with the same name
+func AddDeploymentOverrides(options *v1alpha1.AdditionalOptions) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if options == nil || u.GetKind() != "Deployment" {
+			return nil
+		}
+		override, ok := options.Deployments[u.GetName()]
+		if !ok {
+			return nil
+		}
+
+		d := &appsv1.Deployment{}
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, d)
+		if err != nil {
+			return err
+		}
+
+		d.Labels = mergeMaps(d.Labels, override.Labels)
+		d.Annotations = mergeMaps(d.Annotations, override.Annotations)
+		d.Spec.Template.Labels = mergeMaps(d.Spec.Template.Labels, override.Labels)
+		d.Spec.Template.Annotations = mergeMaps(d.Spec.Template.Annotations, override.Annotations)
+
+		if override.Replicas != nil {
+			d.Spec.Replicas = override.Replicas
+			d.Annotations = mergeMaps(d.Annotations, map[string]string{v1alpha1.ReplicasOverrideKey: "true"})
+		}
+		if override.Affinity != nil {
+			d.Spec.Template.Spec.Affinity = override.Affinity
+		}
+		if len(override.TopologySpreadConstraints) != 0 {
+			d.Spec.Template.Spec.TopologySpreadConstraints = override.TopologySpreadConstraints
+		}
+
+		containers := d.Spec.Template.Spec.Containers
+		for i := range containers {
+			containerOverride, ok := override.Containers[containers[i].Name]
+			if !ok {
+				continue
+			}
+			if containerOverride.Resources != nil {
+				containers[i].Resources = *containerOverride.Resources
+			}
+			containers[i].Env = mergeEnv(containers[i].Env, containerOverride.Env)
+			containers[i].Args = mergeArgs(containers[i].Args, containerOverride.Args)
+		}
+
+		unstrObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(d)
+		if err != nil {
+			return err
+		}
+		u.SetUnstructuredContent(unstrObj)
+
+		return nil
+	}
+}
+
+func mergeMaps(current, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return current
+	}
+	if current == nil {
+		current = map[string]string{}
+	}
+	for key, val := range overrides {
+		current[key] = val
+	}
+	return current
+}
+
+// mergeEnv replaces the env vars with the same name as the
+// overrides, the other overrides are appended
+func mergeEnv(env, overrides []corev1.EnvVar) []corev1.EnvVar {
+	for _, override := range overrides {
+		replaced := false
+		for i := range env {
+			if env[i].Name == override.Name {
+				env[i] = override
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			env = append(env, override)
+		}
+	}
+	return env
+}
+
+// mergeArgs replaces the args setting the same flag as the overrides, the
+// other overrides are appended. A flag is set either as "-flag=value" or as
+// "-flag value", in args as well as in the overrides
+func mergeArgs(args, overrides []string) []string {
+	merged := append([]string{}, args...)
+	for i := 0; i < len(overrides); i++ {
+		arg := overrides[i : i+argLen(overrides, i)]
+		i += len(arg) - 1
+
+		index := -1
+		if flag, ok := argFlag(arg[0]); ok {
+			for j := range merged {
+				if f, ok := argFlag(merged[j]); ok && f == flag {
+					index = j
+					break
+				}
+			}
+		}
+		if index < 0 {
+			merged = append(merged, arg...)
+			continue
+		}
+		rest := append([]string{}, merged[index+argLen(merged, index):]...)
+		merged = append(append(merged[:index], arg...), rest...)
+	}
+	return merged
+}
+
+// argFlag returns the name of flag set by arg
+func argFlag(arg string) (string, bool) {
+	if !strings.HasPrefix(arg, "-") {
+		return "", false
+	}
+	return strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0], true
+}
+
+// argLen returns 2 if args[i] is a flag followed by its value, 1 otherwise
+func argLen(args []string, i int) int {
+	if _, ok := argFlag(args[i]); ok && !strings.Contains(args[i], "=") &&
+		i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"path"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/ptr"
+)
+
+func TestAddConfigMapOverrides(t *testing.T) {
+	testData := path.Join("testdata", "test-replace-cm-values.yaml")
+	manifest, err := mf.ManifestFrom(mf.Recursive(testData))
+	assertNoEror(t, err)
+
+	options := &v1alpha1.AdditionalOptions{
+		ConfigMaps: map[string]v1alpha1.ConfigMapOverride{
+			"test1": {
+				Data: map[string]string{"enable-api-fields": "beta", "enable-spire": "true"},
+			},
+		},
+	}
+
+	manifest, err = manifest.Transform(AddOptions(options))
+	assertNoEror(t, err)
+
+	cm := &corev1.ConfigMap{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(manifest.Resources()[0].Object, cm)
+	assertNoEror(t, err)
+	assert.DeepEqual(t, cm.Data, map[string]string{
+		"foo":                       "bar",
+		"enable-tekton-oci-bundles": "false",
+		"enable-api-fields":         "beta",
+		"enable-spire":              "true",
+	})
+
+	cm = &corev1.ConfigMap{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(manifest.Resources()[1].Object, cm)
+	assertNoEror(t, err)
+	assert.Equal(t, cm.Data["default-timeout-minutes"], "60")
+}
+
+func TestAddDeploymentOverrides(t *testing.T) {
+	testData := path.Join("testdata", "test-add-configurations.yaml")
+	manifest, err := mf.ManifestFrom(mf.Recursive(testData))
+	assertNoEror(t, err)
+
+	resources := &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+	}
+	options := &v1alpha1.AdditionalOptions{
+		Deployments: map[string]v1alpha1.DeploymentOverride{
+			"controller": {
+				Labels:      map[string]string{"team": "ci"},
+				Annotations: map[string]string{"sidecar.istio.io/inject": "false"},
+				Replicas:    ptr.Int32(3),
+				Containers: map[string]v1alpha1.ContainerOverride{
+					"controller-deployment": {
+						Resources: resources,
+						Env:       []corev1.EnvVar{{Name: "KUBERNETES_MIN_VERSION", Value: "v1.21.0"}},
+						Args:      []string{"-bash-image=alpine", "-threads-per-controller", "4"},
+					},
+				},
+			},
+			"other": {
+				Replicas: ptr.Int32(0),
+			},
+		},
+	}
+
+	manifest, err = manifest.Transform(AddDeploymentOverrides(options))
+	assertNoEror(t, err)
+
+	d := &appsv1.Deployment{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(manifest.Resources()[0].Object, d)
+	assertNoEror(t, err)
+
+	assert.Equal(t, *d.Spec.Replicas, int32(3))
+	assert.Equal(t, d.Annotations[v1alpha1.ReplicasOverrideKey], "true")
+	assert.Equal(t, d.Labels["team"], "ci")
+	assert.DeepEqual(t, d.Spec.Template.Labels, map[string]string{"run": "test", "team": "ci"})
+	assert.Equal(t, d.Spec.Template.Annotations["sidecar.istio.io/inject"], "false")
+
+	controller := d.Spec.Template.Spec.Containers[0]
+	assert.DeepEqual(t, controller.Resources, *resources)
+	assert.DeepEqual(t, controller.Env, []corev1.EnvVar{{Name: "KUBERNETES_MIN_VERSION", Value: "v1.21.0"}})
+	assert.DeepEqual(t, controller.Args, []string{"-bash-image=alpine", "-nop=nop", "-threads-per-controller", "4"})
+
+	sidecar := d.Spec.Template.Spec.Containers[1]
+	assert.DeepEqual(t, sidecar.Args, []string{"-git", "git"})
+}
+
+func TestMergeArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		overrides []string
+		want      []string
+	}{
+		{
+			name:      "replace flag with value",
+			args:      []string{"-kube-api-qps", "5", "-v"},
+			overrides: []string{"-kube-api-qps=50"},
+			want:      []string{"-kube-api-qps=50", "-v"},
+		},
+		{
+			name:      "replace flag with equal",
+			args:      []string{"-kube-api-qps=5", "-v"},
+			overrides: []string{"--kube-api-qps", "50"},
+			want:      []string{"--kube-api-qps", "50", "-v"},
+		},
+		{
+			name:      "append new flag",
+			args:      []string{"-v"},
+			overrides: []string{"-kube-api-burst=100"},
+			want:      []string{"-v", "-kube-api-burst=100"},
+		},
+		{
+			name:      "append positional arg",
+			args:      []string{"-v"},
+			overrides: []string{"serve"},
+			want:      []string{"-v", "serve"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.DeepEqual(t, mergeArgs(test.args, test.overrides), test.want)
+		})
+	}
+}
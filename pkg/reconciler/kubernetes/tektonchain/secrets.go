@@ -0,0 +1,224 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonchain
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/shared/hash"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	chainsControllerDeployment = "tekton-chains-controller"
+
+	grafeasCredentialsVolume  = "grafeas-credentials"
+	grafeasCredentialsPath    = "/etc/chains/grafeas"
+	registryCredentialsVolume = "registry-credentials"
+	registryCredentialsPath   = "/etc/chains/registry"
+
+	// secretsCheckInterval is how often the Secrets of spec.secrets are
+	// checked for changes, they are not watched so that the operator does
+	// not cache the Secrets of the whole cluster
+	secretsCheckInterval = 5 * time.Minute
+)
+
+// secretNames returns the names of the Secrets referenced in spec.secrets
+func secretNames(refs *v1alpha1.ChainSecrets) []string {
+	if refs == nil {
+		return nil
+	}
+	unique := map[string]bool{}
+	for _, ref := range []*corev1.SecretKeySelector{refs.KMSAuthToken, refs.DocDBURL, refs.GrafeasCredentials} {
+		if ref != nil {
+			unique[ref.Name] = true
+		}
+	}
+	if refs.RegistryCredentials != nil {
+		unique[refs.RegistryCredentials.Name] = true
+	}
+	names := make([]string, 0, len(unique))
+	for name := range unique {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// requeueAfter returns when TektonChain is reconciled again, for the
+// rotation of the signing keys due in rotateIn or else to check the Secrets
+// of spec.secrets for changes
+func requeueAfter(tc *v1alpha1.TektonChain, rotateIn time.Duration) time.Duration {
+	if len(secretNames(tc.Spec.Secrets)) == 0 {
+		return rotateIn
+	}
+	if rotateIn <= 0 || rotateIn > secretsCheckInterval {
+		return secretsCheckInterval
+	}
+	return rotateIn
+}
+
+// secretsHash returns the hash of the data of the Secrets referenced in
+// spec.secrets, empty if there are none
+func (r *Reconciler) secretsHash(ctx context.Context, tc *v1alpha1.TektonChain) (string, error) {
+	names := secretNames(tc.Spec.Secrets)
+	if len(names) == 0 {
+		return "", nil
+	}
+	data := make(map[string]map[string][]byte, len(names))
+	for _, name := range names {
+		secret, err := r.kubeClientSet.CoreV1().Secrets(tc.Spec.TargetNamespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("secret %s/%s referenced in spec.secrets: %v", tc.Spec.TargetNamespace, name, err)
+		}
+		data[name] = secret.Data
+	}
+	return hash.Compute(data)
+}
+
+// specHash returns the hash of the spec of TektonChain, along with the data
+// of the Secrets it references so that the installer set is updated when the
+// credentials change
+func (r *Reconciler) specHash(ctx context.Context, tc *v1alpha1.TektonChain) (string, error) {
+	secretsHash, err := r.secretsHash(ctx, tc)
+	if err != nil {
+		return "", err
+	}
+	if secretsHash == "" {
+		return hash.Compute(tc.Spec)
+	}
+	return hash.Compute(struct {
+		Spec    v1alpha1.TektonChainSpec
+		Secrets string
+	}{tc.Spec, secretsHash})
+}
+
+// injectSecrets passes the credentials referenced in spec.secrets to the
+// chains controller as env vars and volumes, the pods are rolled out when the
+// data of the Secrets changes
+func injectSecrets(refs *v1alpha1.ChainSecrets, secretsHash string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if refs == nil || u.GetKind() != "Deployment" || u.GetName() != chainsControllerDeployment {
+			return nil
+		}
+
+		d := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, d); err != nil {
+			return err
+		}
+
+		var env []corev1.EnvVar
+		var volumes []corev1.Volume
+		var mounts []corev1.VolumeMount
+		secretEnv := func(name string, ref *corev1.SecretKeySelector) {
+			if ref != nil {
+				env = append(env, corev1.EnvVar{Name: name, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: ref}})
+			}
+		}
+		secretVolume := func(name, secret, key, file, mountPath string) {
+			volumes = append(volumes, corev1.Volume{
+				Name: name,
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: secret,
+						Items:      []corev1.KeyToPath{{Key: key, Path: file}},
+					},
+				},
+			})
+			mounts = append(mounts, corev1.VolumeMount{Name: name, MountPath: mountPath, ReadOnly: true})
+		}
+
+		secretEnv("VAULT_TOKEN", refs.KMSAuthToken)
+		secretEnv("MONGO_SERVER_URL", refs.DocDBURL)
+		if ref := refs.GrafeasCredentials; ref != nil {
+			secretVolume(grafeasCredentialsVolume, ref.Name, ref.Key, "credentials.json", grafeasCredentialsPath)
+			env = append(env, corev1.EnvVar{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: path.Join(grafeasCredentialsPath, "credentials.json")})
+		}
+		if ref := refs.RegistryCredentials; ref != nil {
+			secretVolume(registryCredentialsVolume, ref.Name, corev1.DockerConfigJsonKey, "config.json", registryCredentialsPath)
+			env = append(env, corev1.EnvVar{Name: "DOCKER_CONFIG", Value: registryCredentialsPath})
+		}
+
+		podSpec := &d.Spec.Template.Spec
+		for _, v := range volumes {
+			podSpec.Volumes = append(removeVolume(podSpec.Volumes, v.Name), v)
+		}
+		for i := range podSpec.Containers {
+			c := &podSpec.Containers[i]
+			for _, e := range env {
+				c.Env = append(removeEnv(c.Env, e.Name), e)
+			}
+			for _, m := range mounts {
+				c.VolumeMounts = append(removeVolumeMount(c.VolumeMounts, m.Name), m)
+			}
+		}
+
+		if secretsHash != "" {
+			if d.Spec.Template.Annotations == nil {
+				d.Spec.Template.Annotations = map[string]string{}
+			}
+			d.Spec.Template.Annotations[v1alpha1.SecretsHashKey] = secretsHash
+		}
+
+		unstrObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(d)
+		if err != nil {
+			return err
+		}
+		u.SetUnstructuredContent(unstrObj)
+		return nil
+	}
+}
+
+func removeEnv(env []corev1.EnvVar, name string) []corev1.EnvVar {
+	res := env[:0]
+	for _, e := range env {
+		if e.Name != name {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+func removeVolume(volumes []corev1.Volume, name string) []corev1.Volume {
+	res := volumes[:0]
+	for _, v := range volumes {
+		if v.Name != name {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func removeVolumeMount(mounts []corev1.VolumeMount, name string) []corev1.VolumeMount {
+	res := mounts[:0]
+	for _, m := range mounts {
+		if m.Name != name {
+			res = append(res, m)
+		}
+	}
+	return res
+}
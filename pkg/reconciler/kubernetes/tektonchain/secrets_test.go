@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonchain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testChainWithSecrets() *v1alpha1.TektonChain {
+	return &v1alpha1.TektonChain{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ChainResourceName},
+		Spec: v1alpha1.TektonChainSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "tekton-chains"},
+			Chain: v1alpha1.Chain{
+				Secrets: &v1alpha1.ChainSecrets{
+					KMSAuthToken: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "vault"},
+						Key:                  "token",
+					},
+					GrafeasCredentials: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "gcp"},
+						Key:                  "key.json",
+					},
+					RegistryCredentials: &corev1.LocalObjectReference{Name: "registry"},
+				},
+			},
+		},
+	}
+}
+
+func TestInjectSecrets(t *testing.T) {
+	d := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: chainsControllerDeployment, Namespace: "tekton-chains"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name: chainsControllerDeployment,
+						Env:  []corev1.EnvVar{{Name: "SYSTEM_NAMESPACE", Value: "tekton-chains"}, {Name: "DOCKER_CONFIG", Value: "/tmp"}},
+					}},
+				},
+			},
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(d)
+	assert.NilError(t, err)
+	u := &unstructured.Unstructured{Object: obj}
+
+	assert.NilError(t, injectSecrets(testChainWithSecrets().Spec.Secrets, "abc")(u))
+
+	got := &appsv1.Deployment{}
+	assert.NilError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, got))
+	assert.Equal(t, got.Spec.Template.Annotations[v1alpha1.SecretsHashKey], "abc")
+	assert.DeepEqual(t, got.Spec.Template.Spec.Containers[0].Env, []corev1.EnvVar{
+		{Name: "SYSTEM_NAMESPACE", Value: "tekton-chains"},
+		{Name: "VAULT_TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: testChainWithSecrets().Spec.Secrets.KMSAuthToken}},
+		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: "/etc/chains/grafeas/credentials.json"},
+		{Name: "DOCKER_CONFIG", Value: "/etc/chains/registry"},
+	})
+	assert.DeepEqual(t, got.Spec.Template.Spec.Containers[0].VolumeMounts, []corev1.VolumeMount{
+		{Name: grafeasCredentialsVolume, MountPath: grafeasCredentialsPath, ReadOnly: true},
+		{Name: registryCredentialsVolume, MountPath: registryCredentialsPath, ReadOnly: true},
+	})
+	assert.DeepEqual(t, got.Spec.Template.Spec.Volumes[1].Secret, &corev1.SecretVolumeSource{
+		SecretName: "registry",
+		Items:      []corev1.KeyToPath{{Key: corev1.DockerConfigJsonKey, Path: "config.json"}},
+	})
+}
+
+func TestSpecHashFollowsSecrets(t *testing.T) {
+	ctx := context.Background()
+	secret := func(name, value string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tekton-chains"},
+			Data:       map[string][]byte{"value": []byte(value)},
+		}
+	}
+	kube := fake.NewSimpleClientset(secret("vault", "one"), secret("gcp", "key"))
+	r := &Reconciler{kubeClientSet: kube}
+	tc := testChainWithSecrets()
+
+	_, err := r.specHash(ctx, tc)
+	assert.ErrorContains(t, err, "secret tekton-chains/registry referenced in spec.secrets")
+
+	_, err = kube.CoreV1().Secrets("tekton-chains").Create(ctx, secret("registry", "auth"), metav1.CreateOptions{})
+	assert.NilError(t, err)
+	before, err := r.specHash(ctx, tc)
+	assert.NilError(t, err)
+
+	_, err = kube.CoreV1().Secrets("tekton-chains").Update(ctx, secret("vault", "two"), metav1.UpdateOptions{})
+	assert.NilError(t, err)
+	after, err := r.specHash(ctx, tc)
+	assert.NilError(t, err)
+	assert.Assert(t, before != after)
+}
+
+func TestRequeueAfter(t *testing.T) {
+	tc := testChainWithSecrets()
+	assert.Equal(t, requeueAfter(tc, 0), secretsCheckInterval)
+	assert.Equal(t, requeueAfter(tc, time.Minute), time.Minute)
+	assert.Equal(t, requeueAfter(tc, time.Hour), secretsCheckInterval)
+
+	// without Secrets to check, TektonChain only waits for the rotation
+	assert.Equal(t, requeueAfter(&v1alpha1.TektonChain{}, 0), time.Duration(0))
+	assert.Equal(t, requeueAfter(&v1alpha1.TektonChain{}, time.Hour), time.Hour)
+}
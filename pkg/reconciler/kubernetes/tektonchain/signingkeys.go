@@ -0,0 +1,224 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonchain
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// SigningSecret holds the keys of the x509 signer of Chains
+	SigningSecret = "signing-secrets"
+	// PublicKeysConfigMap publishes the public keys of the generated key
+	// pairs for verifiers
+	PublicKeysConfigMap = "chains-public-keys"
+
+	privateKeyName = "x509.pem"
+	publicKeyName  = "cosign.pub"
+	// the public keys of the replaced key pairs are published as
+	// retired-<time of the rotation>.pub during the grace period
+	retiredKeyPrefix     = "retired-"
+	retiredKeySuffix     = ".pub"
+	retiredKeyTimeFormat = "20060102T150405Z"
+
+	defaultSigningKeysGracePeriod = 7 * 24 * time.Hour
+)
+
+// reconcileSigningKeys generates the signing key pair if spec.signingKeys
+// asks for it, rotates it when it is due and publishes the public keys. It
+// returns the time left until the next scheduled rotation, 0 if there is none
+func (r *Reconciler) reconcileSigningKeys(ctx context.Context, tc *v1alpha1.TektonChain, now time.Time) (time.Duration, error) {
+	logger := logging.FromContext(ctx)
+	sk := tc.Spec.SigningKeys
+	if sk == nil || !sk.Generate {
+		// the keys generated before are left for Chains to use
+		tc.Status.SigningKeys = nil
+		return 0, nil
+	}
+
+	secrets := r.kubeClientSet.CoreV1().Secrets(tc.Spec.TargetNamespace)
+	secret, err := secrets.Get(ctx, SigningSecret, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	annotations := secret.GetAnnotations()
+	rotated, generated := annotations[v1alpha1.SigningKeysRotatedKey]
+	if !generated && len(secret.Data) != 0 {
+		return 0, fmt.Errorf("secret %s/%s holds keys not generated by the operator, remove its data to let the operator generate keys",
+			tc.Spec.TargetNamespace, SigningSecret)
+	}
+
+	last, err := time.Parse(time.RFC3339, rotated)
+	request := tc.GetAnnotations()[v1alpha1.RotateSigningKeysKey]
+	due := err != nil ||
+		len(secret.Data[privateKeyName]) == 0 ||
+		request != annotations[v1alpha1.SigningKeysRotationRequestKey] ||
+		(sk.RotationPeriod != nil && !now.Before(last.Add(sk.RotationPeriod.Duration)))
+
+	if due {
+		privateKey, err := generatePrivateKey()
+		if err != nil {
+			return 0, err
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		last = now.UTC().Truncate(time.Second)
+		annotations[v1alpha1.SigningKeysRotatedKey] = last.Format(time.RFC3339)
+		annotations[v1alpha1.SigningKeysRotationRequestKey] = request
+		secret.SetAnnotations(annotations)
+		secret.Data = map[string][]byte{privateKeyName: privateKey}
+		if secret, err = secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return 0, err
+		}
+		logger.Infof("Rotated the signing keys in secret %s/%s", tc.Spec.TargetNamespace, SigningSecret)
+		common.RecordEvent(ctx, tc, corev1.EventTypeNormal, common.EventSigningKeysRotated,
+			"Generated a new signing key pair in secret %s/%s", tc.Spec.TargetNamespace, SigningSecret)
+	}
+
+	publicKey, err := publicKeyOf(secret.Data[privateKeyName])
+	if err != nil {
+		return 0, err
+	}
+	published, err := r.publishPublicKeys(ctx, tc, publicKey, now)
+	if err != nil {
+		return 0, err
+	}
+
+	status := &v1alpha1.SigningKeysStatus{
+		LastRotationTime: &metav1.Time{Time: last},
+		PublicKeys:       published,
+	}
+	tc.Status.SigningKeys = status
+	if sk.RotationPeriod == nil {
+		return 0, nil
+	}
+	next := last.Add(sk.RotationPeriod.Duration)
+	status.NextRotationTime = &metav1.Time{Time: next}
+	return next.Sub(now), nil
+}
+
+// publishPublicKeys sets the current public key in the public keys ConfigMap,
+// the key it replaces is kept for the grace period. It returns the number of
+// keys published
+func (r *Reconciler) publishPublicKeys(ctx context.Context, tc *v1alpha1.TektonChain, publicKey []byte, now time.Time) (int, error) {
+	configMaps := r.kubeClientSet.CoreV1().ConfigMaps(tc.Spec.TargetNamespace)
+	cm, err := configMaps.Get(ctx, PublicKeysConfigMap, metav1.GetOptions{})
+	create := apierrors.IsNotFound(err)
+	if err != nil {
+		if !create {
+			return 0, err
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      PublicKeysConfigMap,
+				Namespace: tc.Spec.TargetNamespace,
+				Labels: map[string]string{
+					v1alpha1.CreatedByKey: createdByValue,
+				},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(tc, tc.GetGroupVersionKind())},
+			},
+		}
+	}
+
+	data := map[string]string{}
+	for k, v := range cm.Data {
+		data[k] = v
+	}
+	if current, ok := data[publicKeyName]; ok && current != string(publicKey) {
+		data[retiredKeyPrefix+now.UTC().Format(retiredKeyTimeFormat)+retiredKeySuffix] = current
+	}
+	data[publicKeyName] = string(publicKey)
+
+	grace := defaultSigningKeysGracePeriod
+	if sk := tc.Spec.SigningKeys; sk.GracePeriod != nil {
+		grace = sk.GracePeriod.Duration
+	}
+	for k := range data {
+		if !strings.HasPrefix(k, retiredKeyPrefix) {
+			continue
+		}
+		retired, err := time.Parse(retiredKeyTimeFormat, strings.TrimSuffix(strings.TrimPrefix(k, retiredKeyPrefix), retiredKeySuffix))
+		if err == nil && now.Sub(retired) > grace {
+			delete(data, k)
+		}
+	}
+
+	switch {
+	case create:
+		cm.Data = data
+		_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+	case !reflect.DeepEqual(cm.Data, data):
+		cm.Data = data
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	return len(data), err
+}
+
+// generatePrivateKey returns a new ECDSA P-256 private key, PEM encoded as
+// expected by the x509 signer of Chains
+func generatePrivateKey() ([]byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// publicKeyOf returns the PEM encoded public key of a PEM encoded private key,
+// in the format accepted by cosign verify --key
+func publicKeyOf(privateKey []byte) ([]byte, error) {
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return nil, fmt.Errorf("%s of secret %s is not PEM encoded", privateKeyName, SigningSecret)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T in secret %s", key, SigningSecret)
+	}
+	der, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonchain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReconcileSigningKeys(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, time.June, 15, 10, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	kube := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: SigningSecret, Namespace: "tekton-chains"},
+	})
+	r := &Reconciler{kubeClientSet: kube}
+	tc := &v1alpha1.TektonChain{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ChainResourceName},
+		Spec: v1alpha1.TektonChainSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "tekton-chains"},
+			SigningKeys: &v1alpha1.SigningKeys{
+				Generate:       true,
+				RotationPeriod: &metav1.Duration{Duration: 30 * day},
+			},
+		},
+	}
+	keys := func() (string, map[string]string) {
+		secret, err := kube.CoreV1().Secrets("tekton-chains").Get(ctx, SigningSecret, metav1.GetOptions{})
+		assert.NilError(t, err)
+		cm, err := kube.CoreV1().ConfigMaps("tekton-chains").Get(ctx, PublicKeysConfigMap, metav1.GetOptions{})
+		assert.NilError(t, err)
+		public, err := publicKeyOf(secret.Data[privateKeyName])
+		assert.NilError(t, err)
+		assert.Equal(t, cm.Data[publicKeyName], string(public))
+		return string(secret.Data[privateKeyName]), cm.Data
+	}
+
+	// the keys are generated in the empty secret
+	rotateIn, err := r.reconcileSigningKeys(ctx, tc, now)
+	assert.NilError(t, err)
+	assert.Equal(t, rotateIn, 30*day)
+	first, published := keys()
+	assert.Equal(t, len(published), 1)
+	assert.DeepEqual(t, tc.Status.SigningKeys, &v1alpha1.SigningKeysStatus{
+		LastRotationTime: &metav1.Time{Time: now},
+		NextRotationTime: &metav1.Time{Time: now.Add(30 * day)},
+		PublicKeys:       1,
+	})
+
+	// nothing changes until a rotation is due
+	rotateIn, err = r.reconcileSigningKeys(ctx, tc, now.Add(day))
+	assert.NilError(t, err)
+	assert.Equal(t, rotateIn, 29*day)
+	current, _ := keys()
+	assert.Equal(t, current, first)
+
+	// a rotation on demand keeps the previous public key
+	tc.SetAnnotations(map[string]string{v1alpha1.RotateSigningKeysKey: "1"})
+	_, err = r.reconcileSigningKeys(ctx, tc, now.Add(2*day))
+	assert.NilError(t, err)
+	second, published := keys()
+	assert.Assert(t, second != first)
+	assert.Equal(t, len(published), 2)
+	assert.Equal(t, tc.Status.SigningKeys.PublicKeys, 2)
+	assert.Assert(t, published["retired-20220617T100000Z.pub"] != "")
+
+	// the previous public key is dropped after the grace period
+	_, err = r.reconcileSigningKeys(ctx, tc, now.Add(10*day))
+	assert.NilError(t, err)
+	current, published = keys()
+	assert.Equal(t, current, second)
+	assert.Equal(t, len(published), 1)
+
+	// the key pair is rotated once the rotation period elapsed
+	_, err = r.reconcileSigningKeys(ctx, tc, now.Add(32*day))
+	assert.NilError(t, err)
+	current, _ = keys()
+	assert.Assert(t, current != second)
+	assert.DeepEqual(t, tc.Status.SigningKeys.LastRotationTime, &metav1.Time{Time: now.Add(32 * day)})
+}
+
+func TestReconcileSigningKeysKeepsKeysCreatedByHand(t *testing.T) {
+	kube := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: SigningSecret, Namespace: "tekton-chains"},
+		Data:       map[string][]byte{"cosign.key": []byte("key")},
+	})
+	r := &Reconciler{kubeClientSet: kube}
+	tc := &v1alpha1.TektonChain{
+		Spec: v1alpha1.TektonChainSpec{
+			CommonSpec:  v1alpha1.CommonSpec{TargetNamespace: "tekton-chains"},
+			SigningKeys: &v1alpha1.SigningKeys{Generate: true},
+		},
+	}
+
+	_, err := r.reconcileSigningKeys(context.Background(), tc, time.Now())
+	assert.ErrorContains(t, err, "holds keys not generated by the operator")
+	secret, err := kube.CoreV1().Secrets("tekton-chains").Get(context.Background(), SigningSecret, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, secret.Data, map[string][]byte{"cosign.key": []byte("key")})
+}
@@ -19,6 +19,7 @@ package tektonchain
 import (
 	"context"
 	"fmt"
+	"time"
 
 	mf "github.com/manifestival/manifestival"
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
@@ -27,11 +28,12 @@ import (
 	tektonchainreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektonchain"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
 	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektoninstallerset"
-	"github.com/tektoncd/operator/pkg/reconciler/shared/hash"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"knative.dev/pkg/apis"
+	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 )
@@ -45,6 +47,8 @@ const (
 type Reconciler struct {
 	// operatorClientSet allows us to configure operator objects
 	operatorClientSet clientset.Interface
+	// kubeClientSet allows us to talk to the k8s for core APIs
+	kubeClientSet kubernetes.Interface
 	// manifest has the source manifest of Tekton Triggers for a
 	// particular version
 	manifest mf.Manifest
@@ -201,7 +205,7 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tc *v1alpha1.TektonChain
 		// Tekton Chain InstallerSet with computing new hash of TektonChain Spec
 
 		// Hash of TektonChain Spec
-		expectedSpecHash, err := hash.Compute(tc.Spec)
+		expectedSpecHash, err := r.specHash(ctx, tc)
 		if err != nil {
 			return err
 		}
@@ -327,6 +331,12 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tc *v1alpha1.TektonChain
 	// Mark InstallerSet Ready
 	tc.Status.MarkInstallerSetReady()
 
+	rotateIn, err := r.reconcileSigningKeys(ctx, tc, time.Now())
+	if err != nil {
+		tc.Status.MarkPostReconcilerFailed(fmt.Sprintf("Signing keys not ready: %s", err.Error()))
+		return err
+	}
+
 	if err := r.extension.PostReconcile(ctx, tc); err != nil {
 		tc.Status.MarkPostReconcilerFailed(fmt.Sprintf("PostReconciliation failed: %s", err.Error()))
 		return err
@@ -340,6 +350,11 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tc *v1alpha1.TektonChain
 		return err
 	}
 
+	// come back when the signing keys are due for rotation or the Secrets
+	// are to be checked
+	if requeueIn := requeueAfter(tc, rotateIn); requeueIn > 0 {
+		return controller.NewRequeueAfter(requeueIn)
+	}
 	return nil
 }
 
@@ -387,14 +402,20 @@ func (r *Reconciler) updateTektonChainStatus(tc *v1alpha1.TektonChain, createdIs
 func (r *Reconciler) transform(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
 	instance := comp.(*v1alpha1.TektonChain)
 	chainImages := common.ToLowerCaseKeys(common.ImagesFromEnv(common.ChainsImagePrefix))
+	secretsHash, err := r.secretsHash(ctx, instance)
+	if err != nil {
+		return err
+	}
 	extra := []mf.Transformer{
 		common.InjectOperandNameLabelOverwriteExisting(v1alpha1.OperandTektoncdChains),
 		common.ApplyProxySettings,
 		common.DeploymentImages(chainImages),
 		common.AddConfiguration(instance.Spec.Config),
 		common.AddConfigMapValues(ChainsConfig, instance.Spec.Chain),
+		injectSecrets(instance.Spec.Secrets, secretsHash),
 	}
 	extra = append(extra, r.extension.Transformers(instance)...)
+	extra = append(extra, common.AddOptions(instance.Spec.Options))
 	return common.Transform(ctx, manifest, instance, extra...)
 }
 
@@ -426,7 +447,7 @@ func (r *Reconciler) createInstallerSet(ctx context.Context, tc *v1alpha1.Tekton
 	// in further reconciliation we compute hash of tc spec and check with
 	// annotation, if they are same then we skip updating the object
 	// otherwise we update the manifest
-	specHash, err := hash.Compute(tc.Spec)
+	specHash, err := r.specHash(ctx, tc)
 	if err != nil {
 		return nil, err
 	}
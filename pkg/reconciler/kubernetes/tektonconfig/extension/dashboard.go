@@ -73,6 +73,7 @@ func createDashboard(ctx context.Context, clients op.TektonDashboardInterface, c
 				TargetNamespace: config.Spec.TargetNamespace,
 			},
 			Config:              config.Spec.Config,
+			Options:             config.Spec.Options,
 			DashboardProperties: config.Spec.Dashboard.DashboardProperties,
 		},
 	}
@@ -99,6 +100,11 @@ func updateDashboard(ctx context.Context, tdCR *v1alpha1.TektonDashboard, config
 		updated = true
 	}
 
+	if !reflect.DeepEqual(tdCR.Spec.Options, config.Spec.Options) {
+		tdCR.Spec.Options = config.Spec.Options
+		updated = true
+	}
+
 	if tdCR.ObjectMeta.OwnerReferences == nil {
 		ownerRef := *metav1.NewControllerRef(config, config.GroupVersionKind())
 		tdCR.ObjectMeta.OwnerReferences = []metav1.OwnerReference{ownerRef}
@@ -313,6 +313,7 @@ func (r *Reconciler) transform(ctx context.Context, manifest *mf.Manifest, comp
 		common.AddConfiguration(instance.Spec.Config),
 	}
 	extra = append(extra, r.extension.Transformers(instance)...)
+	extra = append(extra, common.AddOptions(instance.Spec.Options))
 	return common.Transform(ctx, manifest, instance, extra...)
 }
 
@@ -563,6 +563,7 @@ func (r *Reconciler) transform(ctx context.Context, manifest mf.Manifest, th *v1
 		addConfigMapKeyValue(uiConfigName, "AUTH_BASE_URL", th.Status.AuthRouteUrl),
 		addConfigMapKeyValue(uiConfigName, "API_VERSION", "v1"),
 		addConfigMapKeyValue(uiConfigName, "REDIRECT_URI", th.Status.UiRouteUrl),
+		common.AddOptions(th.Spec.Options),
 	}
 	trans = append(trans, extra...)
 
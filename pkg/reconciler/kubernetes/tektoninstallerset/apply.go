@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektoninstallerset
+
+import (
+	"context"
+
+	mfDynamic "github.com/manifestival/client-go-client/pkg/dynamic"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// FieldManager is the field manager used by TektonInstallerSet
+// for server-side apply
+const FieldManager = "tekton-operator"
+
+// Applier applies a resource using server-side apply and
+// returns the resource from the cluster
+type Applier interface {
+	Apply(obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+}
+
+type serverSideApplier struct {
+	resourceGetter mfDynamic.ResourceGetter
+}
+
+// NewServerSideApplier returns an Applier which applies resources with
+// FieldManager, conflicts with other field managers are returned as error
+// instead of being overwritten
+func NewServerSideApplier(resourceGetter mfDynamic.ResourceGetter) Applier {
+	return &serverSideApplier{resourceGetter: resourceGetter}
+}
+
+func (a *serverSideApplier) Apply(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	resource, err := a.resourceGetter.ResourceInterface(obj)
+	if err != nil {
+		return nil, err
+	}
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return resource.Patch(context.TODO(), obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+		FieldManager: FieldManager,
+	})
+}
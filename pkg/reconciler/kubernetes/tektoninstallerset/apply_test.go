@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektoninstallerset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/manifestival/manifestival/fake"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// fakeResource acts as the api server for server-side apply of a single
+// object whose replicas are owned by another field manager
+type fakeResource struct {
+	dynamic.ResourceInterface
+	replicas int64
+	options  []metav1.PatchOptions
+}
+
+func (r *fakeResource) Patch(_ context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, _ ...string) (*unstructured.Unstructured, error) {
+	r.options = append(r.options, options)
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if replicas != r.replicas && (options.Force == nil || !*options.Force) {
+		return nil, apierrs.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, name,
+			fmt.Errorf("conflict with \"horizontal-pod-autoscaler\": .spec.replicas"))
+	}
+	r.replicas = replicas
+	return obj, nil
+}
+
+type fakeResourceGetter struct {
+	resource dynamic.ResourceInterface
+}
+
+func (g fakeResourceGetter) ResourceInterface(*unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	return g.resource, nil
+}
+
+func TestServerSideApplierReportsConflict(t *testing.T) {
+	deployment := namespacedResource("apps/v1", "Deployment", "test", "controller")
+	assert.NilError(t, unstructured.SetNestedField(deployment.Object, int64(1), "spec", "replicas"))
+
+	resource := &fakeResource{replicas: 3}
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{deployment}))
+	assert.NilError(t, err)
+
+	i := NewInstaller(&manifest, fake.New(), logger)
+	i.applier = NewServerSideApplier(fakeResourceGetter{resource: resource})
+
+	err = i.EnsureDeploymentResources()
+	assert.Assert(t, apierrs.IsConflict(err))
+
+	// the fields of other field managers are not taken over
+	assert.Equal(t, len(resource.options), 1)
+	assert.Equal(t, resource.options[0].FieldManager, FieldManager)
+	assert.Assert(t, resource.options[0].Force == nil)
+	assert.Equal(t, resource.replicas, int64(3))
+
+	status := i.ResourcesStatus()
+	assert.Equal(t, len(status), 1)
+	assert.Equal(t, status[0].Result, v1alpha1.ResourceConflict)
+	assert.Assert(t, strings.Contains(status[0].Error, "horizontal-pod-autoscaler"))
+}
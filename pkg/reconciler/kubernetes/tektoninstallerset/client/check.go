@@ -86,7 +86,7 @@ func (i *InstallerSetClient) CheckSet(ctx context.Context, comp v1alpha1.TektonC
 		return nil, fmt.Errorf("invalid installerSet type")
 	}
 
-	if err := verifyMeta(i.resourceKind, isType, logger, iSets[0], comp, i.releaseVersion); err != nil {
+	if err := verifyMeta(i.resourceKind, isType, logger, iSets[0], comp, i.releaseVersion, i.componentVersion); err != nil {
 		logger.Errorf("%v/%v: meta check failed for installer type: %v", i.resourceKind, isType, err)
 		return iSets, err
 	}
@@ -114,7 +114,7 @@ func verifyMainInstallerSets(iSets []v1alpha1.TektonInstallerSet) error {
 	return nil
 }
 
-func verifyMeta(resourceKind, isType string, logger *zap.SugaredLogger, set v1alpha1.TektonInstallerSet, comp v1alpha1.TektonComponent, releaseVersion string) error {
+func verifyMeta(resourceKind, isType string, logger *zap.SugaredLogger, set v1alpha1.TektonInstallerSet, comp v1alpha1.TektonComponent, releaseVersion, componentVersion string) error {
 	// Release Version Check
 	logger.Infof("%v/%v: release version check", resourceKind, isType)
 
@@ -125,6 +125,11 @@ func verifyMeta(resourceKind, isType string, logger *zap.SugaredLogger, set v1al
 	if rVel != releaseVersion {
 		return ErrVersionDifferent
 	}
+	// a version pinned in spec is upgraded like a release, the sets
+	// created before the component version was recorded are kept
+	if cVel, ok := set.GetLabels()[v1alpha1.ComponentVersionKey]; ok && cVel != componentVersion {
+		return ErrVersionDifferent
+	}
 
 	// Target namespace check
 	logger.Infof("%v/%v: target namespace check", resourceKind, isType)
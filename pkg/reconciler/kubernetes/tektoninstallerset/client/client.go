@@ -33,6 +33,9 @@ const (
 	InstallerTypePre    = "pre"
 	InstallerTypePost   = "post"
 	InstallerTypeCustom = "custom"
+	// InstallerTypePrevious is the type of main installer sets of previous
+	// release, kept until the upgrade is complete
+	InstallerTypePrevious = "previous"
 )
 
 var (
@@ -54,6 +57,7 @@ type InstallerSetClient struct {
 	filterAndTransform FilterAndTransform
 	manifest           *mf.Manifest
 	metrics            Metrics
+	healthCheck        HealthCheck
 }
 
 func NewInstallerSetClient(clientSet clientSet.TektonInstallerSetInterface, manifest *mf.Manifest, releaseVersion, componentVersion string, resourceKind string, filterAndTransform FilterAndTransform, metrics Metrics) *InstallerSetClient {
@@ -67,3 +71,12 @@ func NewInstallerSetClient(clientSet clientSet.TektonInstallerSetInterface, mani
 		componentVersion:   componentVersion,
 	}
 }
+
+// WithManifest returns a copy of the client installing the manifest of
+// componentVersion instead, for a component pinning its version in spec
+func (i *InstallerSetClient) WithManifest(manifest *mf.Manifest, componentVersion string) *InstallerSetClient {
+	c := *i
+	c.manifest = manifest
+	c.componentVersion = componentVersion
+	return &c
+}
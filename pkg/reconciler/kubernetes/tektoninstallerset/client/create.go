@@ -33,7 +33,7 @@ func (i *InstallerSetClient) Create(ctx context.Context, comp v1alpha1.TektonCom
 
 	switch isType {
 	case InstallerTypeMain:
-		sets, err := i.makeMainSets(ctx, comp, manifest)
+		sets, err := i.makeMainSets(ctx, comp, manifest, "")
 		if err != nil {
 			logger.Errorf("installer set creation failed for main type: %v", err)
 			return sets, err
@@ -65,9 +65,11 @@ func (i *InstallerSetClient) Create(ctx context.Context, comp v1alpha1.TektonCom
 	return nil, nil
 }
 
-func (i *InstallerSetClient) makeMainSets(ctx context.Context, comp v1alpha1.TektonComponent, manifest *mf.Manifest) ([]v1alpha1.TektonInstallerSet, error) {
+// makeMainSets creates the main static and deployment sets. For a staged
+// upgrade, the deployment set has only the deployments of the stage
+func (i *InstallerSetClient) makeMainSets(ctx context.Context, comp v1alpha1.TektonComponent, manifest *mf.Manifest, stage string) ([]v1alpha1.TektonInstallerSet, error) {
 	staticManifest := manifest.Filter(mf.Not(mf.ByKind("Deployment")))
-	deploymentManifest := manifest.Filter(mf.ByKind("Deployment"))
+	deploymentManifest := stageManifest(manifest.Filter(mf.ByKind("Deployment")), stage)
 
 	kind := strings.ToLower(strings.TrimPrefix(i.resourceKind, "Tekton"))
 	staticName := fmt.Sprintf("%s-%s-%s-", kind, InstallerTypeMain, InstallerSubTypeStatic)
@@ -86,6 +88,9 @@ func (i *InstallerSetClient) makeMainSets(ctx context.Context, comp v1alpha1.Tek
 	if err != nil {
 		return nil, err
 	}
+	if stage != "" {
+		deploymentIS.Annotations[v1alpha1.UpgradeStageKey] = stage
+	}
 
 	deploymentIS, err = i.clientSet.Create(ctx, deploymentIS, metav1.CreateOptions{})
 	if err != nil {
@@ -110,9 +115,10 @@ func (i *InstallerSetClient) makeInstallerSet(ctx context.Context, comp v1alpha1
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: isName,
 			Labels: map[string]string{
-				v1alpha1.CreatedByKey:      i.resourceKind,
-				v1alpha1.ReleaseVersionKey: i.releaseVersion,
-				v1alpha1.InstallerSetType:  isType,
+				v1alpha1.CreatedByKey:        i.resourceKind,
+				v1alpha1.ReleaseVersionKey:   i.releaseVersion,
+				v1alpha1.ComponentVersionKey: i.componentVersion,
+				v1alpha1.InstallerSetType:    isType,
 			},
 			Annotations: map[string]string{
 				v1alpha1.TargetNamespaceKey: comp.GetSpec().GetTargetNamespace(),
@@ -120,8 +126,28 @@ func (i *InstallerSetClient) makeInstallerSet(ctx context.Context, comp v1alpha1
 			},
 			OwnerReferences: []metav1.OwnerReference{ownerRef},
 		},
-		Spec: v1alpha1.TektonInstallerSetSpec{
-			Manifests: transformedMf.Resources(),
-		},
+		Spec: installerSetSpec(comp, transformedMf),
 	}, nil
 }
+
+// installerSetSpec returns the spec of an installer set with the manifest
+// and the installer set options of component
+func installerSetSpec(comp v1alpha1.TektonComponent, manifest *mf.Manifest) v1alpha1.TektonInstallerSetSpec {
+	spec := v1alpha1.TektonInstallerSetSpec{
+		Manifests: manifest.Resources(),
+	}
+	if options := installerSetOptions(comp); options != nil {
+		spec.ContinueOnError = options.ContinueOnError
+		spec.ServerSideApply = options.ServerSideApply
+		spec.DriftPolicy = options.DriftPolicy
+	}
+	return spec
+}
+
+func installerSetOptions(comp v1alpha1.TektonComponent) *v1alpha1.InstallerSetOptions {
+	spec, ok := comp.GetSpec().(v1alpha1.InstallerSetOptionsSpec)
+	if !ok {
+		return nil
+	}
+	return spec.GetInstallerSetOptions()
+}
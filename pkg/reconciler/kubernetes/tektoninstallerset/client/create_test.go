@@ -132,3 +132,37 @@ func TestInstallerSetClient_Create(t *testing.T) {
 		})
 	}
 }
+
+func TestInstallerSetClient_CreateWithInstallerSetOptions(t *testing.T) {
+	ctx, _ := testing2.SetupFakeContext(t)
+	comp := &v1alpha1.TektonTrigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "trigger",
+		},
+		Spec: v1alpha1.TektonTriggerSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "test"},
+			Trigger: v1alpha1.Trigger{
+				InstallerSet: &v1alpha1.InstallerSetOptions{
+					ContinueOnError: true,
+					ServerSideApply: true,
+					DriftPolicy:     v1alpha1.DriftPolicyReport,
+				},
+			},
+		},
+	}
+
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{serviceAccount, deployment}))
+	assert.NilError(t, err)
+
+	client := NewInstallerSetClient(fake2.NewFakeISClient(), &manifest, "devel", "test-version", v1alpha1.KindTektonTrigger,
+		filterAndTransform(common.NoExtension(ctx)), &testMetrics{})
+
+	iSs, err := client.Create(ctx, comp, &manifest, InstallerTypeMain)
+	assert.NilError(t, err)
+	assert.Equal(t, len(iSs), 2)
+	for _, set := range iSs {
+		assert.Equal(t, set.Spec.ContinueOnError, true)
+		assert.Equal(t, set.Spec.ServerSideApply, true)
+		assert.Equal(t, set.Spec.DriftPolicy, v1alpha1.DriftPolicyReport)
+	}
+}
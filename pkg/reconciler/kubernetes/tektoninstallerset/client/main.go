@@ -18,14 +18,19 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	corev1 "k8s.io/api/core/v1"
 	"knative.dev/pkg/logging"
 )
 
 const (
-	metricsNew     = "NewInstall"
-	metricsUpgrade = "Upgrade"
+	metricsNew      = "NewInstall"
+	metricsUpgrade  = "Upgrade"
+	metricsRollback = "Rollback"
 )
 
 func (i *InstallerSetClient) MainSet(ctx context.Context, comp v1alpha1.TektonComponent) error {
@@ -39,27 +44,83 @@ func (i *InstallerSetClient) MainSet(ctx context.Context, comp v1alpha1.TektonCo
 
 	switch err {
 	case ErrNotFound:
-		logger.Infof("%v/%v: installer set not found, creating", i.resourceKind, setType)
-		sets, err = i.Create(ctx, comp, i.manifest, InstallerTypeMain)
+		restored, err := i.restorePreviousSet(ctx, comp)
+		if err != nil {
+			logger.Errorf("%v/%v: failed to restore previous installer set: %v", i.resourceKind, setType, err)
+			return err
+		}
+		if restored != nil {
+			logger.Infof("%v/%v: upgrade rolled back to previous installer sets", i.resourceKind, setType)
+			comp.GetStatus().MarkInstallerSetNotReady(v1alpha1.RolledBack)
+			return nil
+		}
+
+		staged, err := i.isStagedUpgrade(ctx, comp)
+		if err != nil {
+			logger.Errorf("%v/%v: failed to check previous installer set: %v", i.resourceKind, setType, err)
+			return err
+		}
+		if staged {
+			// the deployments are rolled out stage by stage,
+			// once the static set with CRDs is ready
+			logger.Infof("%v/%v: installer set not found, creating for staged upgrade", i.resourceKind, setType)
+			sets, err = i.makeMainSets(ctx, comp, i.manifest, upgradeStageCRDs)
+		} else {
+			logger.Infof("%v/%v: installer set not found, creating", i.resourceKind, setType)
+			sets, err = i.Create(ctx, comp, i.manifest, InstallerTypeMain)
+		}
 		if err != nil {
 			return nil
 		}
 		if comp.GetStatus().GetCondition(v1alpha1.InstallerSetAvailable).IsUnknown() {
 			i.metrics.LogMetrics(metricsNew, i.componentVersion, logger)
+			common.RecordEvent(ctx, comp, corev1.EventTypeNormal, common.EventInstallStarted,
+				"Installing %s %s with installer sets %s", i.resourceKind, i.componentVersion, setNames(sets))
+		}
+
+	case ErrVersionDifferent:
+		if i.isRolledBack(comp, sets) {
+			logger.Infof("%v/%v: upgrade was rolled back, waiting for spec update to retry", i.resourceKind, setType)
+			comp.GetStatus().MarkInstallerSetNotReady(v1alpha1.RolledBack)
+			// the sets retired by the rollback are deleted once the
+			// restored sets applied their resources again
+			if !allReady(sets) {
+				return nil
+			}
+			if err := i.CleanupPreviousSet(ctx); err != nil {
+				logger.Errorf("%v/%v: failed to cleanup rolled back installer set: %v", i.resourceKind, setType, err)
+				return err
+			}
+			return nil
 		}
+		// keep the installer sets of previous release until the
+		// new ones are ready, to be able to roll back the upgrade
+		logger.Infof("%v/%v: installer set release version differs, retiring", i.resourceKind, setType)
+		if err := i.RetireMainSet(ctx, sets); err != nil {
+			logger.Errorf("%v/%v: failed to retire main installer set: %v", i.resourceKind, setType, err)
+			return nil
+		}
+		i.metrics.LogMetrics(metricsUpgrade, i.componentVersion, logger)
+		common.RecordEvent(ctx, comp, corev1.EventTypeNormal, common.EventUpgradeStarted,
+			"Upgrading %s from release %s to %s (%s), installer sets %s kept until the upgrade is complete",
+			i.resourceKind, setRelease(sets[0]), i.releaseVersion, i.componentVersion, setNames(sets))
+		markComponentStatus(comp, v1alpha1.UpgradePending)
+		logger.Infof("%v/%v: returning, will create main installer sets in further reconcile", i.resourceKind, setType)
+		return v1alpha1.REQUEUE_EVENT_AFTER
 
-	case ErrInvalidState, ErrNsDifferent, ErrVersionDifferent:
+	case ErrInvalidState, ErrNsDifferent:
 		logger.Infof("%v/%v: installer set not in valid state : %v, cleaning up!", i.resourceKind, setType, err)
 		if err := i.CleanupMainSet(ctx); err != nil {
 			logger.Errorf("%v/%v: failed to cleanup main installer set: %v", i.resourceKind, setType, err)
 			return nil
 		}
-		if err == ErrVersionDifferent {
-			i.metrics.LogMetrics(metricsUpgrade, i.componentVersion, logger)
-			markComponentStatus(comp, v1alpha1.UpgradePending)
-		} else {
-			markComponentStatus(comp, v1alpha1.Reinstalling)
+		if err := i.CleanupPreviousSet(ctx); err != nil {
+			logger.Errorf("%v/%v: failed to cleanup previous installer set: %v", i.resourceKind, setType, err)
+			return nil
 		}
+		common.RecordEvent(ctx, comp, corev1.EventTypeWarning, common.EventInstallerSetRecreated,
+			"Recreating installer sets %s of %s %s: %v", setNames(sets), i.resourceKind, i.componentVersion, err)
+		markComponentStatus(comp, v1alpha1.Reinstalling)
 		logger.Infof("%v/%v: returning, will create main installer sets in further reconcile", i.resourceKind, setType)
 		return v1alpha1.REQUEUE_EVENT_AFTER
 
@@ -81,7 +142,34 @@ func (i *InstallerSetClient) MainSet(ctx context.Context, comp v1alpha1.TektonCo
 	for _, set := range sets {
 		if !set.Status.IsReady() {
 			logger.Infof("%v/%v: installer set %v no yet ready, wait !", i.resourceKind, setType, set.GetName())
-			return nil
+			if stage := upgradeStage(sets); stage != "" {
+				markStageWaiting(comp, stage, set)
+			}
+			return i.rollbackIfTimedOut(ctx, comp, sets)
+		}
+	}
+
+	if stage := upgradeStage(sets); stage != "" && stage != upgradeStageControllers {
+		return i.nextUpgradeStage(ctx, comp, sets, stage)
+	}
+
+	// previous installer sets are not required once the upgrade is complete
+	previous, err := i.previousSets(ctx)
+	if err != nil {
+		logger.Errorf("%v/%v: failed to get previous installer set: %v", i.resourceKind, setType, err)
+		return err
+	}
+	if err := i.CleanupPreviousSet(ctx); err != nil {
+		logger.Errorf("%v/%v: failed to cleanup previous installer set: %v", i.resourceKind, setType, err)
+		return err
+	}
+	for _, set := range previous {
+		// reported once, when the previous sets start being deleted
+		if set.GetDeletionTimestamp() == nil {
+			common.RecordEvent(ctx, comp, corev1.EventTypeNormal, common.EventUpgradeSucceeded,
+				"Upgraded %s to release %s (%s), installer sets %s are ready",
+				i.resourceKind, i.releaseVersion, i.componentVersion, setNames(sets))
+			break
 		}
 	}
 
@@ -96,3 +184,32 @@ func markComponentStatus(comp v1alpha1.TektonComponent, status string) {
 	comp.GetStatus().MarkPostReconcilerFailed(status)
 	comp.GetStatus().MarkNotReady(status)
 }
+
+// setNames returns the names of installer sets, comma separated
+func setNames(sets []v1alpha1.TektonInstallerSet) string {
+	names := make([]string, 0, len(sets))
+	for _, set := range sets {
+		names = append(names, set.GetName())
+	}
+	return strings.Join(names, ", ")
+}
+
+// allReady returns true if all the installer sets are ready
+func allReady(sets []v1alpha1.TektonInstallerSet) bool {
+	for _, set := range sets {
+		if !set.Status.IsReady() {
+			return false
+		}
+	}
+	return true
+}
+
+// setRelease returns the release version of an installer set, followed by
+// the component version if recorded
+func setRelease(set v1alpha1.TektonInstallerSet) string {
+	release := set.GetLabels()[v1alpha1.ReleaseVersionKey]
+	if version := set.GetLabels()[v1alpha1.ComponentVersionKey]; version != "" {
+		release = fmt.Sprintf("%s (%s)", release, version)
+	}
+	return release
+}
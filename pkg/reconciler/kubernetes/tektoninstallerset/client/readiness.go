@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// componentLabelKey is the label set on the workloads in the
+// release manifests of Tekton components
+const componentLabelKey = "app.kubernetes.io/component"
+
+// ReadinessRole returns the role of a workload which decides the
+// condition reporting its readiness.
+// The role is taken from the readiness role annotation, then from the
+// component label and at last from the name of the resource
+func ReadinessRole(u *unstructured.Unstructured) string {
+	if role, ok := u.GetAnnotations()[v1alpha1.ReadinessRoleKey]; ok {
+		return role
+	}
+	if component, ok := u.GetLabels()[componentLabelKey]; ok {
+		switch component {
+		case v1alpha1.ReadinessRoleWebhook, v1alpha1.ReadinessRoleController:
+			return component
+		}
+		return v1alpha1.ReadinessRoleWorkload
+	}
+	if strings.Contains(u.GetName(), "webhook") {
+		return v1alpha1.ReadinessRoleWebhook
+	}
+	if strings.Contains(u.GetName(), "controller") {
+		return v1alpha1.ReadinessRoleController
+	}
+	return v1alpha1.ReadinessRoleWorkload
+}
+
+// byReadinessRole returns a predicate matching the workloads with the role
+func byReadinessRole(role string) mf.Predicate {
+	return func(u *unstructured.Unstructured) bool {
+		return ReadinessRole(u) == role
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"gotest.tools/v3/assert"
+)
+
+func TestReadinessRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		resource    string
+		labels      map[string]string
+		annotations map[string]string
+		role        string
+	}{
+		{
+			name:     "role from name",
+			resource: "tekton-pipelines-webhook",
+			role:     v1alpha1.ReadinessRoleWebhook,
+		},
+		{
+			name:     "role from component label",
+			resource: "tekton-chains",
+			labels:   map[string]string{componentLabelKey: "controller"},
+			role:     v1alpha1.ReadinessRoleController,
+		},
+		{
+			name:     "component label other than webhook and controller",
+			resource: "tekton-pipelines-webhook-proxy",
+			labels:   map[string]string{componentLabelKey: "proxy"},
+			role:     v1alpha1.ReadinessRoleWorkload,
+		},
+		{
+			name:        "role from annotation",
+			resource:    "tekton-results-postgres",
+			labels:      map[string]string{componentLabelKey: "database"},
+			annotations: map[string]string{v1alpha1.ReadinessRoleKey: v1alpha1.ReadinessRoleNone},
+			role:        v1alpha1.ReadinessRoleNone,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			u := namespacedResource("apps/v1", "Deployment", "test", test.resource)
+			u.SetLabels(test.labels)
+			u.SetAnnotations(test.annotations)
+			assert.Equal(t, ReadinessRole(&u), test.role)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"github.com/tektoncd/operator/pkg/reconciler/shared/hash"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+)
+
+// RetireMainSet marks the main installer sets as previous instead of deleting
+// them on upgrade. The previous sets are not reconciled, they are kept until
+// the main sets of new release are ready so that the upgrade can be rolled back
+func (i *InstallerSetClient) RetireMainSet(ctx context.Context, sets []v1alpha1.TektonInstallerSet) error {
+	// only the last release is kept
+	if err := i.CleanupPreviousSet(ctx); err != nil {
+		return err
+	}
+	return i.retireSets(ctx, sets)
+}
+
+// retireSets marks the sets as previous, they are not reconciled anymore and
+// on delete only the resources they still control are deleted
+func (i *InstallerSetClient) retireSets(ctx context.Context, sets []v1alpha1.TektonInstallerSet) error {
+	logger := logging.FromContext(ctx).With("kind", i.resourceKind, "type", InstallerTypeMain)
+
+	for _, set := range sets {
+		set := set.DeepCopy()
+		labels := set.GetLabels()
+		labels[v1alpha1.InstallerSetType] = InstallerTypePrevious
+		set.SetLabels(labels)
+		annotations := set.GetAnnotations()
+		delete(annotations, v1alpha1.RolledBackFromKey)
+		set.SetAnnotations(annotations)
+
+		logger.Infof("retiring main installer set: %s", set.GetName())
+		if _, err := i.clientSet.Update(ctx, set, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to retire main installer set %s: %v", set.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// CleanupPreviousSet deletes the main installer sets of previous release
+func (i *InstallerSetClient) CleanupPreviousSet(ctx context.Context) error {
+	logger := logging.FromContext(ctx).With("kind", i.resourceKind, "type", InstallerTypePrevious)
+
+	sets, err := i.previousSets(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, set := range sets {
+		if set.GetDeletionTimestamp() != nil {
+			continue
+		}
+		logger.Infof("deleting previous installer set: %s", set.GetName())
+		err := i.clientSet.Delete(ctx, set.GetName(), metav1.DeleteOptions{
+			PropagationPolicy: &deletePropagationPolicy,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete previous installer set %s", set.GetName())
+		}
+	}
+	return nil
+}
+
+func (i *InstallerSetClient) previousSets(ctx context.Context) ([]v1alpha1.TektonInstallerSet, error) {
+	labelSelector := labels.NewSelector()
+	createdReq, _ := labels.NewRequirement(v1alpha1.CreatedByKey, selection.Equals, []string{i.resourceKind})
+	if createdReq != nil {
+		labelSelector = labelSelector.Add(*createdReq)
+	}
+	typeReq, _ := labels.NewRequirement(v1alpha1.InstallerSetType, selection.Equals, []string{InstallerTypePrevious})
+	if typeReq != nil {
+		labelSelector = labelSelector.Add(*typeReq)
+	}
+
+	list, err := i.clientSet.List(ctx, metav1.ListOptions{LabelSelector: labelSelector.String()})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// rollbackIfTimedOut rolls back the upgrade if the main sets are not ready
+// within the rollback timeout of component. The main sets are retired and the
+// previous sets restored as main sets, nothing is deleted until the restored
+// sets are ready and own their resources again. The retired sets are deleted
+// then, with only the resources of new release the restored sets don't own
+func (i *InstallerSetClient) rollbackIfTimedOut(ctx context.Context, comp v1alpha1.TektonComponent, sets []v1alpha1.TektonInstallerSet) error {
+	logger := logging.FromContext(ctx).With("kind", i.resourceKind, "type", InstallerTypeMain)
+
+	rollback := upgradeRollback(comp)
+	if !rollback.IsEnabled() {
+		return nil
+	}
+
+	previous, err := i.previousSets(ctx)
+	if err != nil {
+		return err
+	}
+	if len(previous) == 0 {
+		return nil
+	}
+
+	var started time.Time
+	for _, set := range sets {
+		if set.GetCreationTimestamp().After(started) {
+			started = set.GetCreationTimestamp().Time
+		}
+	}
+	if remaining := rollback.Timeout.Duration - time.Since(started); remaining > 0 {
+		return controller.NewRequeueAfter(remaining)
+	}
+
+	logger.Infof("main installer sets not ready in %v, rolling back to previous release", rollback.Timeout.Duration)
+	for _, set := range previous {
+		set := set.DeepCopy()
+		annotations := set.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[v1alpha1.RolledBackFromKey] = i.releaseVersion
+		set.SetAnnotations(annotations)
+		if _, err := i.clientSet.Update(ctx, set, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to mark previous installer set %s for rollback: %v", set.GetName(), err)
+		}
+	}
+
+	// the sets marked for rollback are restored in further reconcile if
+	// this one fails after retiring the main sets
+	if err := i.retireSets(ctx, sets); err != nil {
+		return err
+	}
+	if _, err := i.restorePreviousSet(ctx, comp); err != nil {
+		return err
+	}
+	i.metrics.LogMetrics(metricsRollback, i.componentVersion, logger)
+	common.RecordEvent(ctx, comp, corev1.EventTypeWarning, common.EventUpgradeFailed,
+		"Installer sets %s of %s release %s (%s) not ready in %v, rolling back to installer sets %s",
+		setNames(sets), i.resourceKind, i.releaseVersion, i.componentVersion, rollback.Timeout.Duration, setNames(previous))
+	markComponentStatus(comp, v1alpha1.RollingBack)
+	return v1alpha1.REQUEUE_EVENT_AFTER
+}
+
+// restorePreviousSet makes the previous sets marked for rollback the main sets
+// again. Returns nil if there is no rollback in progress
+func (i *InstallerSetClient) restorePreviousSet(ctx context.Context, comp v1alpha1.TektonComponent) ([]v1alpha1.TektonInstallerSet, error) {
+	logger := logging.FromContext(ctx).With("kind", i.resourceKind, "type", InstallerTypeMain)
+
+	previous, err := i.previousSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the sets retired by the rollback are previous sets as well
+	var marked []v1alpha1.TektonInstallerSet
+	for _, set := range previous {
+		if set.GetAnnotations()[v1alpha1.RolledBackFromKey] == i.releaseVersion {
+			marked = append(marked, set)
+		}
+	}
+	if len(marked) == 0 {
+		return nil, nil
+	}
+
+	// the spec is recorded as applied so that the upgrade is tried
+	// again only after the spec is updated
+	specHash, err := hash.Compute(comp.GetSpec())
+	if err != nil {
+		return nil, err
+	}
+
+	sets := make([]v1alpha1.TektonInstallerSet, 0, len(marked))
+	for _, set := range marked {
+		set := set.DeepCopy()
+		labels := set.GetLabels()
+		labels[v1alpha1.InstallerSetType] = InstallerTypeMain
+		set.SetLabels(labels)
+		annotations := set.GetAnnotations()
+		annotations[v1alpha1.LastAppliedHashKey] = specHash
+		set.SetAnnotations(annotations)
+
+		logger.Infof("restoring previous installer set: %s", set.GetName())
+		updated, err := i.clientSet.Update(ctx, set, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore previous installer set %s: %v", set.GetName(), err)
+		}
+		sets = append(sets, *updated)
+	}
+	return sets, nil
+}
+
+// isRolledBack returns true if the main sets were restored by rolling back
+// the upgrade to current release and the spec is not updated since
+func (i *InstallerSetClient) isRolledBack(comp v1alpha1.TektonComponent, sets []v1alpha1.TektonInstallerSet) bool {
+	if len(sets) == 0 || sets[0].GetAnnotations()[v1alpha1.RolledBackFromKey] != i.releaseVersion {
+		return false
+	}
+	specHash, err := hash.Compute(comp.GetSpec())
+	if err != nil {
+		return false
+	}
+	return sets[0].GetAnnotations()[v1alpha1.LastAppliedHashKey] == specHash
+}
+
+func upgradeRollback(comp v1alpha1.TektonComponent) *v1alpha1.UpgradeRollback {
+	spec, ok := comp.GetSpec().(v1alpha1.UpgradeRollbackSpec)
+	if !ok {
+		return nil
+	}
+	return spec.GetUpgradeRollback()
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"github.com/tektoncd/operator/pkg/reconciler/shared/hash"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
+	testing2 "knative.dev/pkg/reconciler/testing"
+)
+
+// newRollbackTestClient returns a fake clientset with the main sets of release
+// "old" and generating names of installer sets on create
+func newRollbackTestClient(t *testing.T, comp v1alpha1.TektonComponent) *fake.Clientset {
+	specHash, err := hash.Compute(comp.GetSpec())
+	assert.NilError(t, err)
+
+	var existing []runtime.Object
+	for _, name := range []string{"trigger-main-static-old", "trigger-main-deployment-old"} {
+		set := &v1alpha1.TektonInstallerSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					v1alpha1.CreatedByKey:      v1alpha1.KindTektonTrigger,
+					v1alpha1.InstallerSetType:  InstallerTypeMain,
+					v1alpha1.ReleaseVersionKey: "old",
+				},
+				Annotations: map[string]string{
+					v1alpha1.TargetNamespaceKey: "test",
+					v1alpha1.LastAppliedHashKey: specHash,
+				},
+			},
+		}
+		markReady(set)
+		existing = append(existing, set)
+	}
+
+	fakeClient := fake.NewSimpleClientset(existing...)
+	generated := 0
+	fakeClient.PrependReactor("create", "tektoninstallersets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		set := action.(k8stesting.CreateAction).GetObject().(*v1alpha1.TektonInstallerSet)
+		generated++
+		set.Name = fmt.Sprintf("%s%d", set.GenerateName, generated)
+		set.CreationTimestamp = metav1.Now()
+		return false, nil, nil
+	})
+	return fakeClient
+}
+
+func markReady(set *v1alpha1.TektonInstallerSet) {
+	set.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}
+}
+
+func listSets(t *testing.T, ctx context.Context, fakeClient *fake.Clientset, isType string) []v1alpha1.TektonInstallerSet {
+	list, err := fakeClient.OperatorV1alpha1().TektonInstallerSets().List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", v1alpha1.InstallerSetType, isType),
+	})
+	assert.NilError(t, err)
+	return list.Items
+}
+
+func TestInstallerSetClient_MainSetRollback(t *testing.T) {
+	ctx, _ := testing2.SetupFakeContext(t)
+
+	comp := &v1alpha1.TektonTrigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "trigger",
+		},
+		Spec: v1alpha1.TektonTriggerSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "test"},
+			Trigger: v1alpha1.Trigger{
+				Rollback: &v1alpha1.UpgradeRollback{Timeout: &metav1.Duration{Duration: time.Minute}},
+			},
+		},
+	}
+	comp.Status.InitializeConditions()
+
+	fakeClient := newRollbackTestClient(t, comp)
+	tisClient := fakeClient.OperatorV1alpha1().TektonInstallerSets()
+
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{serviceAccount, deployment}))
+	assert.NilError(t, err)
+
+	client := NewInstallerSetClient(tisClient, &manifest, "devel", "test-version", v1alpha1.KindTektonTrigger,
+		filterAndTransform(common.NoExtension(ctx)), &testMetrics{})
+
+	// upgrade retires the main sets of old release
+	assert.Equal(t, client.MainSet(ctx, comp), v1alpha1.REQUEUE_EVENT_AFTER)
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypeMain)), 0)
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypePrevious)), 2)
+
+	// main sets of new release are created and waited for until timeout
+	err = client.MainSet(ctx, comp)
+	ok, delay := controller.IsRequeueKey(err)
+	assert.Assert(t, ok)
+	assert.Assert(t, delay > 0 && delay <= time.Minute)
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypeMain)), 2)
+
+	for _, set := range listSets(t, ctx, fakeClient, InstallerTypeMain) {
+		set.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+		_, err := tisClient.Update(ctx, &set, metav1.UpdateOptions{})
+		assert.NilError(t, err)
+	}
+
+	// main sets not ready after timeout are retired and previous sets restored,
+	// nothing is deleted before the restored sets are ready
+	assert.Equal(t, client.MainSet(ctx, comp), v1alpha1.REQUEUE_EVENT_AFTER)
+	main := listSets(t, ctx, fakeClient, InstallerTypeMain)
+	assert.Equal(t, len(main), 2)
+	for _, set := range main {
+		assert.Equal(t, set.GetLabels()[v1alpha1.ReleaseVersionKey], "old")
+		assert.Equal(t, set.GetAnnotations()[v1alpha1.RolledBackFromKey], "devel")
+	}
+	for _, set := range listSets(t, ctx, fakeClient, InstallerTypePrevious) {
+		assert.Equal(t, set.GetLabels()[v1alpha1.ReleaseVersionKey], "devel")
+	}
+
+	// the retired sets are kept while the restored sets apply their resources
+	for _, set := range main {
+		set.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionFalse}}
+		_, err := tisClient.Update(ctx, &set, metav1.UpdateOptions{})
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, client.MainSet(ctx, comp))
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypePrevious)), 2)
+	assert.Assert(t, strings.Contains(comp.Status.GetCondition(v1alpha1.InstallerSetReady).Message, v1alpha1.RolledBack))
+
+	// and deleted once the restored sets are ready
+	for _, set := range listSets(t, ctx, fakeClient, InstallerTypeMain) {
+		markReady(&set)
+		_, err := tisClient.Update(ctx, &set, metav1.UpdateOptions{})
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, client.MainSet(ctx, comp))
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypePrevious)), 0)
+	assert.Assert(t, strings.Contains(comp.Status.GetCondition(v1alpha1.InstallerSetReady).Message, v1alpha1.RolledBack))
+
+	// rolled back sets are kept until the spec is updated
+	assert.NilError(t, client.MainSet(ctx, comp))
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypeMain)), 2)
+
+	comp.Spec.Rollback.Timeout.Duration = 2 * time.Minute
+	assert.Equal(t, client.MainSet(ctx, comp), v1alpha1.REQUEUE_EVENT_AFTER)
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypeMain)), 0)
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypePrevious)), 2)
+}
+
+func TestInstallerSetClient_MainSetRollbackSpecVersion(t *testing.T) {
+	ctx, _ := testing2.SetupFakeContext(t)
+
+	comp := &v1alpha1.TektonTrigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "trigger",
+		},
+		Spec: v1alpha1.TektonTriggerSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "test"},
+			Trigger: v1alpha1.Trigger{
+				Rollback: &v1alpha1.UpgradeRollback{Timeout: &metav1.Duration{Duration: time.Minute}},
+			},
+		},
+	}
+	comp.Status.InitializeConditions()
+
+	fakeClient := newRollbackTestClient(t, comp)
+	tisClient := fakeClient.OperatorV1alpha1().TektonInstallerSets()
+
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{serviceAccount, deployment}))
+	assert.NilError(t, err)
+
+	// the sets of the version shipped with the operator are installed
+	client := NewInstallerSetClient(tisClient, &manifest, "old", "v0.20.0", v1alpha1.KindTektonTrigger,
+		filterAndTransform(common.NoExtension(ctx)), &testMetrics{})
+	for _, set := range listSets(t, ctx, fakeClient, InstallerTypeMain) {
+		set.Labels[v1alpha1.ComponentVersionKey] = "v0.20.0"
+		_, err := tisClient.Update(ctx, &set, metav1.UpdateOptions{})
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, client.MainSet(ctx, comp))
+
+	// pinning another version in spec retires the sets like an upgrade
+	comp.Spec.Version = "v0.19.0"
+	pinned := client.WithManifest(&manifest, "v0.19.0")
+	assert.Equal(t, pinned.MainSet(ctx, comp), v1alpha1.REQUEUE_EVENT_AFTER)
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypeMain)), 0)
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypePrevious)), 2)
+
+	err = pinned.MainSet(ctx, comp)
+	ok, _ := controller.IsRequeueKey(err)
+	assert.Assert(t, ok)
+	for _, set := range listSets(t, ctx, fakeClient, InstallerTypeMain) {
+		assert.Equal(t, set.GetLabels()[v1alpha1.ComponentVersionKey], "v0.19.0")
+		set.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+		_, err := tisClient.Update(ctx, &set, metav1.UpdateOptions{})
+		assert.NilError(t, err)
+	}
+
+	// and rolls back to them when the pinned version is not ready in time
+	assert.Equal(t, pinned.MainSet(ctx, comp), v1alpha1.REQUEUE_EVENT_AFTER)
+	main := listSets(t, ctx, fakeClient, InstallerTypeMain)
+	assert.Equal(t, len(main), 2)
+	for _, set := range main {
+		assert.Equal(t, set.GetLabels()[v1alpha1.ComponentVersionKey], "v0.20.0")
+	}
+	assert.NilError(t, pinned.MainSet(ctx, comp))
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypePrevious)), 0)
+	assert.Assert(t, strings.Contains(comp.Status.GetCondition(v1alpha1.InstallerSetReady).Message, v1alpha1.RolledBack))
+}
+
+func TestInstallerSetClient_MainSetUpgradeComplete(t *testing.T) {
+	ctx, _ := testing2.SetupFakeContext(t)
+
+	comp := &v1alpha1.TektonTrigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "trigger",
+		},
+		Spec: v1alpha1.TektonTriggerSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "test"},
+		},
+	}
+	comp.Status.InitializeConditions()
+
+	fakeClient := newRollbackTestClient(t, comp)
+	tisClient := fakeClient.OperatorV1alpha1().TektonInstallerSets()
+
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{serviceAccount, deployment}))
+	assert.NilError(t, err)
+
+	client := NewInstallerSetClient(tisClient, &manifest, "devel", "test-version", v1alpha1.KindTektonTrigger,
+		filterAndTransform(common.NoExtension(ctx)), &testMetrics{})
+
+	assert.Equal(t, client.MainSet(ctx, comp), v1alpha1.REQUEUE_EVENT_AFTER)
+
+	// without rollback configured, previous sets are kept while waiting
+	assert.NilError(t, client.MainSet(ctx, comp))
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypePrevious)), 2)
+
+	for _, set := range listSets(t, ctx, fakeClient, InstallerTypeMain) {
+		markReady(&set)
+		_, err := tisClient.Update(ctx, &set, metav1.UpdateOptions{})
+		assert.NilError(t, err)
+	}
+
+	// previous sets are deleted once the main sets are ready
+	assert.NilError(t, client.MainSet(ctx, comp))
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypePrevious)), 0)
+	assert.Assert(t, comp.Status.GetCondition(v1alpha1.InstallerSetReady).IsTrue())
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/logging"
+)
+
+// Stages of staged upgrade, in the order they are rolled out. The CRDs and
+// other static resources are rolled out in the first stage, the webhook
+// deployments in the second and the rest of the deployments in the last
+const (
+	upgradeStageCRDs        = "crds"
+	upgradeStageWebhook     = "webhook"
+	upgradeStageControllers = "controllers"
+)
+
+// HealthCheck verifies a component once its webhook is rolled out by
+// a staged upgrade, before the controllers are rolled out
+type HealthCheck func(ctx context.Context, comp v1alpha1.TektonComponent) error
+
+// WithHealthCheck returns a copy of the client running the health check
+// between the webhook and controllers stages of staged upgrade
+func (i *InstallerSetClient) WithHealthCheck(check HealthCheck) *InstallerSetClient {
+	c := *i
+	c.healthCheck = check
+	return &c
+}
+
+// isStagedUpgrade returns true if the main sets are to be created in stages,
+// that is if the component asks for a staged upgrade and the sets of
+// previous release are still in place
+func (i *InstallerSetClient) isStagedUpgrade(ctx context.Context, comp v1alpha1.TektonComponent) (bool, error) {
+	if !upgradeStrategy(comp).IsStaged() {
+		return false, nil
+	}
+	previous, err := i.previousSets(ctx)
+	if err != nil {
+		return false, err
+	}
+	return len(previous) > 0, nil
+}
+
+// nextUpgradeStage rolls out the next stage of a staged upgrade once the main
+// sets of the current stage are ready. The upgrade is paused before rolling
+// out the controllers until the health check passes
+func (i *InstallerSetClient) nextUpgradeStage(ctx context.Context, comp v1alpha1.TektonComponent, sets []v1alpha1.TektonInstallerSet, stage string) error {
+	logger := logging.FromContext(ctx).With("kind", i.resourceKind, "type", InstallerTypeMain)
+
+	var next string
+	switch stage {
+	case upgradeStageCRDs:
+		next = upgradeStageWebhook
+	case upgradeStageWebhook:
+		if i.healthCheck != nil {
+			if err := i.healthCheck(ctx, comp); err != nil {
+				logger.Errorf("health check failed at stage %s: %v", stage, err)
+				comp.GetStatus().MarkInstallerSetNotReady(fmt.Sprintf("%s at stage %s, health check failed: %v", v1alpha1.UpgradePaused, stage, err))
+				if err := i.rollbackIfTimedOut(ctx, comp, sets); err == v1alpha1.REQUEUE_EVENT_AFTER {
+					return err
+				}
+				return v1alpha1.REQUEUE_EVENT_AFTER
+			}
+		}
+		next = upgradeStageControllers
+	default:
+		return nil
+	}
+
+	for _, set := range sets {
+		if !strings.Contains(set.GetName(), InstallerSubTypeDeployment) {
+			continue
+		}
+		logger.Infof("rolling out stage %s of upgrade with installer set: %s", next, set.GetName())
+		if err := i.updateStage(ctx, comp, set, next); err != nil {
+			return fmt.Errorf("failed to roll out stage %s with installer set %s: %v", next, set.GetName(), err)
+		}
+	}
+	comp.GetStatus().MarkInstallerSetNotReady(fmt.Sprintf("%s, rolling out %s", v1alpha1.UpgradeStaging, next))
+	return v1alpha1.REQUEUE_EVENT_AFTER
+}
+
+// markStageWaiting reports the stage of staged upgrade as waiting for the
+// installer set which is not ready, along with the reason it is not
+func markStageWaiting(comp v1alpha1.TektonComponent, stage string, set v1alpha1.TektonInstallerSet) {
+	msg := fmt.Sprintf("%s at stage %s, installer set %s not ready", v1alpha1.UpgradeWaiting, stage, set.GetName())
+	if ready := set.Status.GetCondition(apis.ConditionReady); ready != nil && ready.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, ready.Message)
+	}
+	comp.GetStatus().MarkInstallerSetNotReady(msg)
+}
+
+// updateStage updates the deployment set with the deployments of stage
+func (i *InstallerSetClient) updateStage(ctx context.Context, comp v1alpha1.TektonComponent, set v1alpha1.TektonInstallerSet, stage string) error {
+	deploymentManifest := stageManifest(i.manifest.Filter(mf.ByKind("Deployment")), stage)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		onCluster, err := i.clientSet.Get(ctx, set.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		manifest, err := i.filterAndTransform(ctx, &deploymentManifest, comp)
+		if err != nil {
+			return err
+		}
+
+		annotations := onCluster.GetAnnotations()
+		annotations[v1alpha1.UpgradeStageKey] = stage
+		onCluster.SetAnnotations(annotations)
+		onCluster.Spec = installerSetSpec(comp, manifest)
+
+		_, err = i.clientSet.Update(ctx, onCluster, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// upgradeStage returns the stage of staged upgrade recorded on the main
+// sets, empty if the sets were not created in stages
+func upgradeStage(sets []v1alpha1.TektonInstallerSet) string {
+	for _, set := range sets {
+		if stage, ok := set.GetAnnotations()[v1alpha1.UpgradeStageKey]; ok {
+			return stage
+		}
+	}
+	return ""
+}
+
+// stageManifest returns the deployments rolled out until the stage,
+// all the deployments if the sets are not created in stages
+func stageManifest(deployments mf.Manifest, stage string) mf.Manifest {
+	switch stage {
+	case upgradeStageCRDs:
+		return deployments.Filter(mf.Nothing)
+	case upgradeStageWebhook:
+		return deployments.Filter(byReadinessRole(v1alpha1.ReadinessRoleWebhook))
+	}
+	return deployments
+}
+
+func upgradeStrategy(comp v1alpha1.TektonComponent) *v1alpha1.Upgrade {
+	spec, ok := comp.GetSpec().(v1alpha1.UpgradeSpec)
+	if !ok {
+		return nil
+	}
+	return spec.GetUpgrade()
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	testing2 "knative.dev/pkg/reconciler/testing"
+)
+
+func markMainSetsReady(t *testing.T, ctx context.Context, fakeClient *fake.Clientset) {
+	for _, set := range listSets(t, ctx, fakeClient, InstallerTypeMain) {
+		markReady(&set)
+		_, err := fakeClient.OperatorV1alpha1().TektonInstallerSets().Update(ctx, &set, metav1.UpdateOptions{})
+		assert.NilError(t, err)
+	}
+}
+
+func deploymentSet(t *testing.T, ctx context.Context, fakeClient *fake.Clientset) v1alpha1.TektonInstallerSet {
+	for _, set := range listSets(t, ctx, fakeClient, InstallerTypeMain) {
+		if strings.Contains(set.GetName(), InstallerSubTypeDeployment) {
+			return set
+		}
+	}
+	t.Fatal("main deployment installer set not found")
+	return v1alpha1.TektonInstallerSet{}
+}
+
+func TestInstallerSetClient_MainSetStagedUpgrade(t *testing.T) {
+	ctx, _ := testing2.SetupFakeContext(t)
+
+	comp := &v1alpha1.TektonTrigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "trigger",
+		},
+		Spec: v1alpha1.TektonTriggerSpec{
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "test"},
+			Upgrade:    &v1alpha1.Upgrade{Strategy: v1alpha1.UpgradeStrategyStaged},
+		},
+	}
+	comp.Status.InitializeConditions()
+
+	fakeClient := newRollbackTestClient(t, comp)
+	tisClient := fakeClient.OperatorV1alpha1().TektonInstallerSets()
+
+	webhook := namespacedResource("apps/v1", "Deployment", "test", "test-webhook")
+	controller := namespacedResource("apps/v1", "Deployment", "test", "test-controller")
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{serviceAccount, webhook, controller}))
+	assert.NilError(t, err)
+
+	healthErr := fmt.Errorf("webhook not serving")
+	client := NewInstallerSetClient(tisClient, &manifest, "devel", "test-version", v1alpha1.KindTektonTrigger,
+		filterAndTransform(common.NoExtension(ctx)), &testMetrics{}).
+		WithHealthCheck(func(context.Context, v1alpha1.TektonComponent) error {
+			return healthErr
+		})
+
+	assert.Equal(t, client.MainSet(ctx, comp), v1alpha1.REQUEUE_EVENT_AFTER)
+
+	// the static set is rolled out first, without the deployments
+	assert.NilError(t, client.MainSet(ctx, comp))
+	set := deploymentSet(t, ctx, fakeClient)
+	assert.Equal(t, set.GetAnnotations()[v1alpha1.UpgradeStageKey], upgradeStageCRDs)
+	assert.Equal(t, len(set.Spec.Manifests), 0)
+
+	// the stage waits for the sets, and tells which one is not ready
+	set.Status.MarkNotReady("CRDs not established")
+	_, err = tisClient.Update(ctx, &set, metav1.UpdateOptions{})
+	assert.NilError(t, err)
+	assert.NilError(t, client.MainSet(ctx, comp))
+	msg := comp.Status.GetCondition(v1alpha1.InstallerSetReady).Message
+	assert.Assert(t, strings.Contains(msg, fmt.Sprintf("%s at stage %s, installer set %s not ready: Ready: CRDs not established",
+		v1alpha1.UpgradeWaiting, upgradeStageCRDs, set.GetName())), msg)
+
+	// the webhook is rolled out once the static set is ready
+	markMainSetsReady(t, ctx, fakeClient)
+	assert.Equal(t, client.MainSet(ctx, comp), v1alpha1.REQUEUE_EVENT_AFTER)
+	set = deploymentSet(t, ctx, fakeClient)
+	assert.Equal(t, set.GetAnnotations()[v1alpha1.UpgradeStageKey], upgradeStageWebhook)
+	assert.Equal(t, len(set.Spec.Manifests), 1)
+	assert.Equal(t, set.Spec.Manifests[0].GetName(), "test-webhook")
+
+	// the upgrade is paused while the health check fails
+	assert.Equal(t, client.MainSet(ctx, comp), v1alpha1.REQUEUE_EVENT_AFTER)
+	set = deploymentSet(t, ctx, fakeClient)
+	assert.Equal(t, set.GetAnnotations()[v1alpha1.UpgradeStageKey], upgradeStageWebhook)
+	assert.Assert(t, strings.Contains(comp.Status.GetCondition(v1alpha1.InstallerSetReady).Message, v1alpha1.UpgradePaused))
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypePrevious)), 2)
+
+	// the controllers are rolled out once the health check passes
+	healthErr = nil
+	assert.Equal(t, client.MainSet(ctx, comp), v1alpha1.REQUEUE_EVENT_AFTER)
+	set = deploymentSet(t, ctx, fakeClient)
+	assert.Equal(t, set.GetAnnotations()[v1alpha1.UpgradeStageKey], upgradeStageControllers)
+	assert.Equal(t, len(set.Spec.Manifests), 2)
+
+	// previous sets are deleted once all the stages are ready
+	markMainSetsReady(t, ctx, fakeClient)
+	assert.NilError(t, client.MainSet(ctx, comp))
+	assert.Equal(t, len(listSets(t, ctx, fakeClient, InstallerTypePrevious)), 0)
+	assert.Assert(t, comp.Status.GetCondition(v1alpha1.InstallerSetReady).IsTrue())
+}
+
+func TestStageManifest(t *testing.T) {
+	webhook := namespacedResource("apps/v1", "Deployment", "test", "test-webhook")
+	controller := namespacedResource("apps/v1", "Deployment", "test", "test-controller")
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{webhook, controller}))
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(stageManifest(manifest, upgradeStageCRDs).Resources()), 0)
+	assert.Equal(t, len(stageManifest(manifest, upgradeStageWebhook).Resources()), 1)
+	assert.Equal(t, len(stageManifest(manifest, upgradeStageControllers).Resources()), 2)
+	assert.Equal(t, len(stageManifest(manifest, "").Resources()), 2)
+}
@@ -76,7 +76,9 @@ func (i *InstallerSetClient) updateMainSets(ctx context.Context, comp v1alpha1.T
 		if strings.Contains(is.GetName(), InstallerSubTypeStatic) {
 			manifest = &staticManifest
 		} else {
-			manifest = &deploymentManifest
+			// the deployments of stages not reached are not rolled out yet
+			stagedManifest := stageManifest(deploymentManifest, is.GetAnnotations()[v1alpha1.UpgradeStageKey])
+			manifest = &stagedManifest
 		}
 
 		updatedSet, err := i.updateSet(ctx, comp, is, manifest)
@@ -112,7 +114,7 @@ func (i *InstallerSetClient) updateSet(ctx context.Context, comp v1alpha1.Tekton
 		current[v1alpha1.LastAppliedHashKey] = specHash
 		onCluster.SetAnnotations(current)
 
-		onCluster.Spec.Manifests = manifest.Resources()
+		onCluster.Spec = installerSetSpec(comp, manifest)
 
 		updatedSet, err = i.clientSet.Update(ctx, onCluster, metav1.UpdateOptions{})
 		if err != nil {
@@ -51,6 +51,13 @@ func TestInstallerSetClient_Update(t *testing.T) {
 		},
 		Spec: v1alpha1.TektonTriggerSpec{
 			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "test"},
+			Trigger: v1alpha1.Trigger{
+				InstallerSet: &v1alpha1.InstallerSetOptions{
+					ContinueOnError: true,
+					ServerSideApply: true,
+					DriftPolicy:     v1alpha1.DriftPolicyReport,
+				},
+			},
 		},
 	}
 
@@ -170,6 +177,13 @@ func TestInstallerSetClient_Update(t *testing.T) {
 			}
 			assert.NilError(t, gotErr)
 
+			// the installer set options of the component are applied on update
+			for _, set := range updatedISs {
+				assert.Equal(t, set.Spec.ContinueOnError, true)
+				assert.Equal(t, set.Spec.ServerSideApply, true)
+				assert.Equal(t, set.Spec.DriftPolicy, v1alpha1.DriftPolicyReport)
+			}
+
 			// based on transformer all the resource namespace should be changed
 			if tt.setType != InstallerTypeMain {
 				assert.Equal(t, updatedISs[0].Spec.Manifests[0].GetNamespace(), updatedNs)
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	mfc "github.com/manifestival/client-go-client"
+	mfDynamic "github.com/manifestival/client-go-client/pkg/dynamic"
 	"go.uber.org/zap"
 	"k8s.io/client-go/tools/cache"
 
@@ -27,7 +28,14 @@ import (
 	operatorclient "github.com/tektoncd/operator/pkg/client/injection/client"
 	tektonInstallerinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektoninstallerset"
 	tektonInstallerReconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektoninstallerset"
-	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
+	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment/filtered"
+	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap/filtered"
+	serviceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/service/filtered"
+	serviceaccountinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/serviceaccount/filtered"
+	clusterroleinformer "knative.dev/pkg/client/injection/kube/informers/rbac/v1/clusterrole/filtered"
+	clusterrolebindinginformer "knative.dev/pkg/client/injection/kube/informers/rbac/v1/clusterrolebinding/filtered"
+	roleinformer "knative.dev/pkg/client/injection/kube/informers/rbac/v1/role/filtered"
+	rolebindinginformer "knative.dev/pkg/client/injection/kube/informers/rbac/v1/rolebinding/filtered"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
@@ -50,9 +58,21 @@ func NewExtendedController() injection.ControllerConstructor {
 			logger.Fatalw("Error creating client from injected config", zap.Error(err))
 		}
 
+		resourceGetter, err := mfDynamic.NewForConfig(injection.GetConfig(ctx))
+		if err != nil {
+			logger.Fatalw("Error creating resource getter from injected config", zap.Error(err))
+		}
+
+		metrics, err := NewRecorder()
+		if err != nil {
+			logger.Errorf("Failed to create installer set metrics recorder %v", err)
+		}
+
 		c := &Reconciler{
 			operatorClientSet: operatorclient.Get(ctx),
 			mfClient:          mfclient,
+			applier:           NewServerSideApplier(resourceGetter),
+			metrics:           metrics,
 		}
 		impl := tektonInstallerReconciler.NewImpl(ctx, c)
 
@@ -60,10 +80,26 @@ func NewExtendedController() injection.ControllerConstructor {
 
 		tektonInstallerinformer.Get(ctx).Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
 
-		deploymentinformer.Get(ctx).Informer().AddEventHandler(cache.FilteringResourceEventHandler{
-			FilterFunc: controller.FilterController(&v1alpha1.TektonInstallerSet{}),
-			Handler:    controller.HandleAll(impl.EnqueueControllerOf),
-		})
+		// the resources of the installer sets are watched so that drift
+		// is noticed when they are changed, the other kinds are checked
+		// on resync. Only the resources labelled by installer sets are
+		// cached, the selector is set on the context by the main
+		selector := v1alpha1.ManagedByInstallerSetKey
+		for _, informer := range []cache.SharedIndexInformer{
+			deploymentinformer.Get(ctx, selector).Informer(),
+			configmapinformer.Get(ctx, selector).Informer(),
+			serviceinformer.Get(ctx, selector).Informer(),
+			serviceaccountinformer.Get(ctx, selector).Informer(),
+			roleinformer.Get(ctx, selector).Informer(),
+			rolebindinginformer.Get(ctx, selector).Informer(),
+			clusterroleinformer.Get(ctx, selector).Informer(),
+			clusterrolebindinginformer.Get(ctx, selector).Informer(),
+		} {
+			informer.AddEventHandler(cache.FilteringResourceEventHandler{
+				FilterFunc: controller.FilterController(&v1alpha1.TektonInstallerSet{}),
+				Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+			})
+		}
 
 		return impl
 	}
@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektoninstallerset
+
+import (
+	"encoding/base64"
+	"reflect"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// isDrifted returns true if the live resource on cluster differs from the
+// desired resource in the manifest.
+// Only the fields present in the desired resource are compared, so the fields
+// defaulted by api server or added by other controllers are not a drift
+func isDrifted(desired, live *unstructured.Unstructured) bool {
+	return !isSubset(normalize(desired), normalize(live))
+}
+
+// normalize returns the content of resource which is compared for drift,
+// status and metadata except labels and annotations are dropped
+func normalize(u *unstructured.Unstructured) map[string]interface{} {
+	obj := u.DeepCopy().Object
+	delete(obj, "status")
+
+	metadata := map[string]interface{}{}
+	if labels := u.GetLabels(); len(labels) != 0 {
+		metadata["labels"] = toInterfaceMap(labels)
+	}
+	annotations := u.GetAnnotations()
+	delete(annotations, v1alpha1.LastAppliedHashKey)
+	if len(annotations) != 0 {
+		metadata["annotations"] = toInterfaceMap(annotations)
+	}
+	obj["metadata"] = metadata
+
+	// api server saves stringData of secret in data
+	if u.GetKind() == "Secret" {
+		stringData, _, _ := unstructured.NestedStringMap(obj, "stringData")
+		if len(stringData) != 0 {
+			data, ok := obj["data"].(map[string]interface{})
+			if !ok {
+				data = map[string]interface{}{}
+			}
+			for k, v := range stringData {
+				data[k] = base64.StdEncoding.EncodeToString([]byte(v))
+			}
+			obj["data"] = data
+			delete(obj, "stringData")
+		}
+	}
+	return obj
+}
+
+func toInterfaceMap(m map[string]string) map[string]interface{} {
+	res := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	return res
+}
+
+// isSubset returns true if all fields in desired have the same value in live.
+// Empty values in desired are treated same as unset
+func isSubset(desired, live interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return len(d) == 0 && live == nil
+		}
+		for k, v := range d {
+			lv, found := l[k]
+			if !found {
+				if isEmpty(v) {
+					continue
+				}
+				return false
+			}
+			if !isSubset(v, lv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		// an empty list may be filled by other controllers
+		// for eg. aggregated cluster roles
+		if len(d) == 0 {
+			return true
+		}
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(d) {
+			return false
+		}
+		for i := range d {
+			if !isSubset(d[i], l[i]) {
+				return false
+			}
+		}
+		return true
+	case int64:
+		return equalNumber(float64(d), live)
+	case float64:
+		return equalNumber(d, live)
+	case nil:
+		return true
+	}
+	return reflect.DeepEqual(desired, live)
+}
+
+func equalNumber(d float64, live interface{}) bool {
+	switch l := live.(type) {
+	case int64:
+		return d == float64(l)
+	case float64:
+		return d == l
+	}
+	return false
+}
+
+func isEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return len(val) == 0
+	case []interface{}:
+		return len(val) == 0
+	case string:
+		return val == ""
+	}
+	return false
+}
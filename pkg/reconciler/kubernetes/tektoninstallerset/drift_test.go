@@ -0,0 +1,228 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektoninstallerset
+
+import (
+	"context"
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/manifestival/manifestival/fake"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"github.com/tektoncd/operator/pkg/reconciler/shared/hash"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/controller"
+)
+
+func TestIsDrifted(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired map[string]interface{}
+		live    map[string]interface{}
+		drifted bool
+	}{
+		{
+			name: "same data",
+			desired: map[string]interface{}{
+				"data": map[string]interface{}{"key": "value"},
+			},
+			live: map[string]interface{}{
+				"data": map[string]interface{}{"key": "value"},
+			},
+			drifted: false,
+		},
+		{
+			name: "changed data",
+			desired: map[string]interface{}{
+				"data": map[string]interface{}{"key": "value"},
+			},
+			live: map[string]interface{}{
+				"data": map[string]interface{}{"key": "edited"},
+			},
+			drifted: true,
+		},
+		{
+			name: "removed data",
+			desired: map[string]interface{}{
+				"data": map[string]interface{}{"key": "value"},
+			},
+			live:    map[string]interface{}{},
+			drifted: true,
+		},
+		{
+			name: "defaulted fields and status",
+			desired: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{"port": int64(9090)},
+					},
+				},
+			},
+			live: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"clusterIP": "10.0.0.1",
+					"ports": []interface{}{
+						map[string]interface{}{"port": float64(9090), "protocol": "TCP"},
+					},
+				},
+				"status": map[string]interface{}{"loadBalancer": map[string]interface{}{}},
+			},
+			drifted: false,
+		},
+		{
+			name: "rule added to role",
+			desired: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{"verbs": []interface{}{"get"}},
+				},
+			},
+			live: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{"verbs": []interface{}{"get"}},
+					map[string]interface{}{"verbs": []interface{}{"delete"}},
+				},
+			},
+			drifted: true,
+		},
+		{
+			name: "empty rules filled by aggregation",
+			desired: map[string]interface{}{
+				"rules": []interface{}{},
+			},
+			live: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{"verbs": []interface{}{"get"}},
+				},
+			},
+			drifted: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			desired := namespacedResource("v1", "ConfigMap", "test", "config")
+			live := namespacedResource("v1", "ConfigMap", "test", "config")
+			live.SetAnnotations(map[string]string{v1alpha1.LastAppliedHashKey: "abcd"})
+			live.SetResourceVersion("123")
+			for k, v := range test.desired {
+				desired.Object[k] = v
+			}
+			for k, v := range test.live {
+				live.Object[k] = v
+			}
+			assert.Equal(t, isDrifted(&desired, &live), test.drifted)
+		})
+	}
+}
+
+func TestIsDrifted_SecretStringData(t *testing.T) {
+	desired := namespacedResource("v1", "Secret", "test", "secret")
+	desired.Object["stringData"] = map[string]interface{}{"key": "value"}
+
+	live := namespacedResource("v1", "Secret", "test", "secret")
+	live.Object["data"] = map[string]interface{}{"key": "dmFsdWU="}
+
+	assert.Equal(t, isDrifted(&desired, &live), false)
+
+	live.Object["data"] = map[string]interface{}{"key": "ZWRpdGVk"}
+	assert.Equal(t, isDrifted(&desired, &live), true)
+}
+
+func TestEnsureResources_DriftPolicy(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	desired := namespacedResource("v1", "ConfigMap", "test", "config")
+	desired.Object["data"] = map[string]interface{}{"key": "value"}
+	expectedHash, err := hash.Compute(desired.Object)
+	assert.NilError(t, err)
+
+	// config map edited by hand keeping the hash annotation
+	edited := namespacedResource("v1", "ConfigMap", "test", "config")
+	edited.Object["data"] = map[string]interface{}{"key": "edited"}
+	edited.SetAnnotations(map[string]string{v1alpha1.LastAppliedHashKey: expectedHash})
+
+	tests := []struct {
+		policy   v1alpha1.DriftPolicy
+		result   v1alpha1.ResourceApplyResult
+		drifted  int
+		expected string
+	}{
+		{policy: "", result: v1alpha1.ResourceUnchanged, drifted: 0, expected: "edited"},
+		{policy: v1alpha1.DriftPolicyIgnore, result: v1alpha1.ResourceUnchanged, drifted: 0, expected: "edited"},
+		{policy: v1alpha1.DriftPolicyReport, result: v1alpha1.ResourceDrifted, drifted: 1, expected: "edited"},
+		{policy: v1alpha1.DriftPolicyEnforce, result: v1alpha1.ResourceUpdated, drifted: 1, expected: "value"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.policy), func(t *testing.T) {
+			live := edited.DeepCopy()
+			fakeClient := fake.New(live)
+
+			manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{*desired.DeepCopy()}))
+			assert.NilError(t, err)
+
+			i := NewInstaller(&manifest, fakeClient, logger)
+			i.driftPolicy = test.policy
+			assert.NilError(t, i.EnsureNamespaceScopedResources())
+
+			assert.Equal(t, i.ResourcesStatus()[0].Result, test.result)
+			assert.Equal(t, len(i.DriftedResources()), test.drifted)
+
+			res, err := fakeClient.Get(&desired)
+			assert.NilError(t, err)
+			value, _, _ := unstructured.NestedString(res.Object, "data", "key")
+			assert.Equal(t, value, test.expected)
+		})
+	}
+}
+
+func TestReportDrift_OnTransitions(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	ctx := controller.WithEventRecorder(context.Background(), recorder)
+	r := &Reconciler{}
+
+	installerSet := &v1alpha1.TektonInstallerSet{}
+	installerSet.Spec.DriftPolicy = v1alpha1.DriftPolicyReport
+	installerSet.Status.InitializeConditions()
+
+	config := namespacedResource("v1", "ConfigMap", "test", "config")
+	service := namespacedResource("v1", "Service", "test", "webhook")
+
+	// a resource left drifted is reported once
+	r.reportDrift(ctx, installerSet, []unstructured.Unstructured{config})
+	r.reportDrift(ctx, installerSet, []unstructured.Unstructured{config})
+	assert.Equal(t, len(recorder.Events), 1)
+	assert.Equal(t, <-recorder.Events, "Warning "+common.EventResourcesDrifted+" Resources drifted from manifests: ConfigMap test/config")
+
+	// another resource drifting is reported
+	r.reportDrift(ctx, installerSet, []unstructured.Unstructured{config, service})
+	assert.Equal(t, len(recorder.Events), 1)
+	<-recorder.Events
+
+	// and so is a resource drifting again after it was back in sync
+	r.reportDrift(ctx, installerSet, nil)
+	assert.Assert(t, installerSet.Status.GetCondition(v1alpha1.ResourcesInSync).IsTrue())
+	r.reportDrift(ctx, installerSet, []unstructured.Unstructured{config})
+	assert.Equal(t, len(recorder.Events), 1)
+	assert.Assert(t, installerSet.Status.GetCondition(v1alpha1.ResourcesInSync).IsFalse())
+}
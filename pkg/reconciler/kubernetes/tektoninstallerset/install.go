@@ -17,7 +17,6 @@ limitations under the License.
 package tektoninstallerset
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
@@ -28,11 +27,10 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/kubernetes/scheme"
-	"knative.dev/pkg/logging"
 	"knative.dev/pkg/ptr"
 )
 
@@ -48,6 +46,16 @@ type installer struct {
 	clusterScoped   []unstructured.Unstructured
 	namespaceScoped []unstructured.Unstructured
 	deployment      []unstructured.Unstructured
+	// continueOnError when set, applies rest of the resources
+	// even if a resource fails
+	continueOnError bool
+	// applier when set, applies resources using server-side apply
+	applier Applier
+	// driftPolicy decides whether resources are checked for drift
+	// and the drifted resources are reverted or only reported
+	driftPolicy v1alpha1.DriftPolicy
+	drifted     []unstructured.Unstructured
+	resources   []v1alpha1.InstallerSetResourceStatus
 }
 
 func NewInstaller(manifest *mf.Manifest, mfClient mf.Client, logger *zap.SugaredLogger) *installer {
@@ -59,6 +67,8 @@ func NewInstaller(manifest *mf.Manifest, mfClient mf.Client, logger *zap.Sugared
 		clusterScoped:   []unstructured.Unstructured{},
 		namespaceScoped: []unstructured.Unstructured{},
 		deployment:      []unstructured.Unstructured{},
+		drifted:         []unstructured.Unstructured{},
+		resources:       []v1alpha1.InstallerSetResourceStatus{},
 	}
 
 	// we filter out resource as some resources are dependent on others
@@ -111,64 +121,201 @@ func isClusterScoped(kind string) bool {
 }
 
 func (i *installer) ensureResources(resources []unstructured.Unstructured) error {
+	failed := []string{}
 	for _, r := range resources {
-		expectedHash, err := hash.Compute(r.Object)
-		if err != nil {
+		var err error
+		if i.applier != nil {
+			err = i.applyResource(&r)
+		} else {
+			err = i.ensureResource(&r)
+		}
+		if err == nil {
+			continue
+		}
+		if !i.continueOnError {
 			return err
 		}
+		failed = append(failed, fmt.Sprintf("%s %s: %v", r.GetKind(), resourceName(&r), err))
+	}
+	if len(failed) != 0 {
+		return fmt.Errorf("failed to apply %d resource(s): %s", len(failed), strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+func (i *installer) ensureResource(r *unstructured.Unstructured) error {
+	expectedHash, err := hash.Compute(r.Object)
+	if err != nil {
+		i.recordStatus(r, v1alpha1.ResourceFailed, "", err)
+		return err
+	}
 
-		i.logger.Infof("fetching resource %s: %s/%s", r.GetKind(), r.GetNamespace(), r.GetName())
+	i.logger.Infof("fetching resource %s: %s/%s", r.GetKind(), r.GetNamespace(), r.GetName())
 
-		res, err := i.mfClient.Get(&r)
-		if err != nil {
-			if apierrs.IsNotFound(err) {
-				i.logger.Infof("resource not found, creating %s: %s/%s", r.GetKind(), r.GetNamespace(), r.GetName())
-				// add hash on the resource of expected manifest and create
-				anno := r.GetAnnotations()
-				if anno == nil {
-					anno = map[string]string{}
-				}
-				anno[v1alpha1.LastAppliedHashKey] = expectedHash
-				r.SetAnnotations(anno)
-				err = i.mfClient.Create(&r)
-				if err != nil {
-					return err
-				}
-				continue
+	res, err := i.mfClient.Get(r)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			i.logger.Infof("resource not found, creating %s: %s/%s", r.GetKind(), r.GetNamespace(), r.GetName())
+			// add hash on the resource of expected manifest and create
+			anno := r.GetAnnotations()
+			if anno == nil {
+				anno = map[string]string{}
 			}
-			return err
+			anno[v1alpha1.LastAppliedHashKey] = expectedHash
+			r.SetAnnotations(anno)
+			if err := i.mfClient.Create(r); err != nil {
+				i.recordStatus(r, v1alpha1.ResourceFailed, expectedHash, err)
+				return err
+			}
+			i.recordStatus(r, v1alpha1.ResourceCreated, expectedHash, nil)
+			return nil
 		}
+		i.recordStatus(r, v1alpha1.ResourceFailed, expectedHash, err)
+		return err
+	}
 
-		i.logger.Infof("found resource %s: %s/%s, checking for update!", r.GetKind(), r.GetNamespace(), r.GetName())
+	i.logger.Infof("found resource %s: %s/%s, checking for update!", r.GetKind(), r.GetNamespace(), r.GetName())
 
-		// if resource exist then check if expected hash is different from the one
-		// on the resource
-		hashOnResource := res.GetAnnotations()[v1alpha1.LastAppliedHashKey]
+	// if resource exist then check if expected hash is different from the one
+	// on the resource
+	hashOnResource := res.GetAnnotations()[v1alpha1.LastAppliedHashKey]
 
-		if expectedHash == hashOnResource {
-			continue
+	if expectedHash == hashOnResource {
+		if !i.checkDrift(r, res) {
+			i.recordStatus(r, v1alpha1.ResourceUnchanged, expectedHash, nil)
+			return nil
+		}
+		if i.driftPolicy == v1alpha1.DriftPolicyReport {
+			i.recordStatus(r, v1alpha1.ResourceDrifted, expectedHash, nil)
+			return nil
 		}
+		i.logger.Infof("reverting drifted resource %s: %s/%s", r.GetKind(), r.GetNamespace(), r.GetName())
+	}
 
-		i.logger.Infof("updating resource %s: %s/%s", r.GetKind(), r.GetNamespace(), r.GetName())
+	i.logger.Infof("updating resource %s: %s/%s", r.GetKind(), r.GetNamespace(), r.GetName())
 
-		anno := r.GetAnnotations()
-		if anno == nil {
-			anno = map[string]string{}
-		}
-		anno[v1alpha1.LastAppliedHashKey] = expectedHash
-		r.SetAnnotations(anno)
+	anno := r.GetAnnotations()
+	if anno == nil {
+		anno = map[string]string{}
+	}
+	anno[v1alpha1.LastAppliedHashKey] = expectedHash
+	r.SetAnnotations(anno)
 
-		installManifests, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{r}), mf.UseClient(i.mfClient))
-		if err != nil {
-			return err
-		}
-		if err := installManifests.Apply(); err != nil {
+	installManifests, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{*r}), mf.UseClient(i.mfClient))
+	if err != nil {
+		i.recordStatus(r, v1alpha1.ResourceFailed, expectedHash, err)
+		return err
+	}
+	if err := installManifests.Apply(); err != nil {
+		i.recordStatus(r, v1alpha1.ResourceFailed, expectedHash, err)
+		return err
+	}
+	i.recordStatus(r, v1alpha1.ResourceUpdated, expectedHash, nil)
+	return nil
+}
+
+// applyResource applies the resource using server-side apply, fields owned by
+// other field managers are not overwritten and are reported as conflict
+func (i *installer) applyResource(r *unstructured.Unstructured) error {
+	expectedHash, err := hash.Compute(r.Object)
+	if err != nil {
+		i.recordStatus(r, v1alpha1.ResourceFailed, "", err)
+		return err
+	}
+
+	existing, err := i.mfClient.Get(r)
+	if err != nil && !apierrs.IsNotFound(err) {
+		i.recordStatus(r, v1alpha1.ResourceFailed, expectedHash, err)
+		return err
+	}
+
+	// apply reverts the drifted fields owned by tekton-operator,
+	// so skip the apply if the drift is only to be reported
+	if existing != nil && i.checkDrift(r, existing) && i.driftPolicy == v1alpha1.DriftPolicyReport {
+		i.recordStatus(r, v1alpha1.ResourceDrifted, expectedHash, nil)
+		return nil
+	}
+
+	anno := r.GetAnnotations()
+	if anno == nil {
+		anno = map[string]string{}
+	}
+	anno[v1alpha1.LastAppliedHashKey] = expectedHash
+	r.SetAnnotations(anno)
+
+	i.logger.Infof("applying resource %s: %s/%s", r.GetKind(), r.GetNamespace(), r.GetName())
+
+	applied, err := i.applier.Apply(r)
+	if err != nil {
+		if apierrs.IsConflict(err) {
+			i.recordStatus(r, v1alpha1.ResourceConflict, expectedHash, err)
 			return err
 		}
+		i.recordStatus(r, v1alpha1.ResourceFailed, expectedHash, err)
+		return err
+	}
+
+	switch {
+	case existing == nil:
+		i.recordStatus(r, v1alpha1.ResourceCreated, expectedHash, nil)
+	case existing.GetResourceVersion() == applied.GetResourceVersion():
+		i.recordStatus(r, v1alpha1.ResourceUnchanged, expectedHash, nil)
+	default:
+		i.recordStatus(r, v1alpha1.ResourceUpdated, expectedHash, nil)
 	}
 	return nil
 }
 
+// checkDrift returns true if the resource on cluster has drifted from the
+// expected resource, drifted resources are saved to be reported by the reconciler
+func (i *installer) checkDrift(expected, existing *unstructured.Unstructured) bool {
+	if i.driftPolicy != v1alpha1.DriftPolicyEnforce && i.driftPolicy != v1alpha1.DriftPolicyReport {
+		return false
+	}
+	if !isDrifted(expected, existing) {
+		return false
+	}
+	i.logger.Infof("resource drifted from manifest %s: %s/%s", expected.GetKind(), expected.GetNamespace(), expected.GetName())
+	i.drifted = append(i.drifted, *expected)
+	return true
+}
+
+// DriftedResources returns the resources found drifted from the manifest
+func (i *installer) DriftedResources() []unstructured.Unstructured {
+	return i.drifted
+}
+
+// recordStatus saves the apply result of a resource to be reported
+// in the installer set status
+func (i *installer) recordStatus(r *unstructured.Unstructured, result v1alpha1.ResourceApplyResult, hash string, err error) {
+	status := v1alpha1.InstallerSetResourceStatus{
+		InstallerSetResource: v1alpha1.InstallerSetResource{
+			APIVersion: r.GetAPIVersion(),
+			Kind:       r.GetKind(),
+			Namespace:  r.GetNamespace(),
+			Name:       r.GetName(),
+		},
+		Hash:   hash,
+		Result: result,
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	i.resources = append(i.resources, status)
+}
+
+// ResourcesStatus returns the apply status of resources attempted by the installer
+func (i *installer) ResourcesStatus() []v1alpha1.InstallerSetResourceStatus {
+	return i.resources
+}
+
+func resourceName(r *unstructured.Unstructured) string {
+	if r.GetNamespace() == "" {
+		return r.GetName()
+	}
+	return r.GetNamespace() + "/" + r.GetName()
+}
+
 func (i *installer) EnsureCRDs() error {
 	return i.ensureResources(i.crds)
 }
@@ -182,7 +329,14 @@ func (i *installer) EnsureNamespaceScopedResources() error {
 }
 
 func (i *installer) EnsureDeploymentResources() error {
+	// with server-side apply, fields changed by others like replicas
+	// are owned by their managers, so deployments need no special handling
+	if i.applier != nil {
+		return i.ensureResources(i.deployment)
+	}
+
 	reconcileAgain := false
+	failed := []string{}
 	for _, d := range i.deployment {
 		if err := i.ensureDeployment(&d); err != nil {
 			// if error is RECONCILER_AGAIN_ERR, then
@@ -191,9 +345,15 @@ func (i *installer) EnsureDeploymentResources() error {
 				reconcileAgain = true
 				continue
 			}
-			return err
+			if !i.continueOnError {
+				return err
+			}
+			failed = append(failed, fmt.Sprintf("%s %s: %v", d.GetKind(), resourceName(&d), err))
 		}
 	}
+	if len(failed) != 0 {
+		return fmt.Errorf("failed to apply %d resource(s): %s", len(failed), strings.Join(failed, "; "))
+	}
 	// if atleast 1 instance in the loop returned RECONCILE_AGAIN_ERR, then
 	// return RECONCILE_AGAIN_ERR
 	if reconcileAgain {
@@ -207,8 +367,11 @@ func computeDeploymentHash(d appsv1.Deployment) (string, error) {
 	// that later if user updates replicas, we can exclude that change.
 	// setting the replicas to same const and checking the hash
 	// so that we can allow only replica change revert any other change
-	// done to the deployment spec
-	d.Spec.Replicas = ptr.Int32(replicasForHash)
+	// done to the deployment spec. Replicas set by an override are
+	// part of the hash, so that changes to them are reverted too
+	if !hasReplicasOverride(&d) {
+		d.Spec.Replicas = ptr.Int32(replicasForHash)
+	}
 
 	return hash.Compute(d.Spec)
 }
@@ -237,7 +400,12 @@ func (i *installer) createDeployment(expected *unstructured.Unstructured) error
 	}
 	expected.SetUnstructuredContent(unstrObj)
 
-	return i.mfClient.Create(expected)
+	if err := i.mfClient.Create(expected); err != nil {
+		i.recordStatus(expected, v1alpha1.ResourceFailed, hash, err)
+		return err
+	}
+	i.recordStatus(expected, v1alpha1.ResourceCreated, hash, nil)
+	return nil
 }
 
 func (i *installer) updateDeployment(existing *unstructured.Unstructured, existingDeployment, expectedDeployment *appsv1.Deployment) error {
@@ -247,7 +415,18 @@ func (i *installer) updateDeployment(existing *unstructured.Unstructured, existi
 	onClusterReplicas := existingDeployment.Spec.Replicas
 
 	existingDeployment.Spec = expectedDeployment.Spec
-	existingDeployment.Spec.Replicas = onClusterReplicas
+	if !hasReplicasOverride(expectedDeployment) {
+		existingDeployment.Spec.Replicas = onClusterReplicas
+	}
+	existingDeployment.OwnerReferences = expectedDeployment.OwnerReferences
+
+	// labels and annotations of the manifest are merged into the ones on
+	// cluster, the ones added by others like kubectl are kept
+	existingDeployment.Labels = mergeMetadata(existingDeployment.Labels, expectedDeployment.Labels)
+	existingDeployment.Annotations = mergeMetadata(existingDeployment.Annotations, expectedDeployment.Annotations)
+	if !hasReplicasOverride(expectedDeployment) {
+		delete(existingDeployment.Annotations, v1alpha1.ReplicasOverrideKey)
+	}
 
 	// compute new hash of spec and add as annotation
 	newHash, err := computeDeploymentHash(*existingDeployment)
@@ -269,9 +448,47 @@ func (i *installer) updateDeployment(existing *unstructured.Unstructured, existi
 
 	err = i.mfClient.Update(existing)
 	if err != nil {
+		i.recordStatus(existing, v1alpha1.ResourceFailed, newHash, err)
 		return v1alpha1.RECONCILE_AGAIN_ERR
 	}
-	return err
+	i.recordStatus(existing, v1alpha1.ResourceUpdated, newHash, nil)
+	return nil
+}
+
+func hasReplicasOverride(d *appsv1.Deployment) bool {
+	return d.Annotations[v1alpha1.ReplicasOverrideKey] == "true"
+}
+
+// isMetadataApplied returns true if the labels and annotations of expected
+// deployment are set on the existing one
+func isMetadataApplied(existing, expected *appsv1.Deployment) bool {
+	if hasReplicasOverride(existing) != hasReplicasOverride(expected) {
+		return false
+	}
+	for k, v := range expected.Labels {
+		if existing.Labels[k] != v {
+			return false
+		}
+	}
+	for k, v := range expected.Annotations {
+		if k != v1alpha1.LastAppliedHashKey && existing.Annotations[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeMetadata(current, expected map[string]string) map[string]string {
+	if len(expected) == 0 {
+		return current
+	}
+	if current == nil {
+		current = map[string]string{}
+	}
+	for k, v := range expected {
+		current[k] = v
+	}
+	return current
 }
 
 func (i *installer) ensureDeployment(expected *unstructured.Unstructured) error {
@@ -283,11 +500,9 @@ func (i *installer) ensureDeployment(expected *unstructured.Unstructured) error
 		// If deployment doesn't exist, then create new
 		if apierrs.IsNotFound(err) {
 			i.logger.Infof("resource not found, creating %s: %s/%s", expected.GetKind(), expected.GetNamespace(), expected.GetName())
-			if err := i.createDeployment(expected); err == nil {
-				return err
-			}
-			return nil
+			return i.createDeployment(expected)
 		}
+		i.recordStatus(expected, v1alpha1.ResourceFailed, "", err)
 		return err
 	}
 
@@ -330,10 +545,16 @@ func (i *installer) ensureDeployment(expected *unstructured.Unstructured) error
 			return fmt.Errorf("failed to compute hash of expected deployment: %v", err)
 		}
 
-		if expectedDepSpecHash != hashFromAnnotation {
+		// the deployment is taken over by the installer set of a new
+		// release even if its spec is the same, otherwise it would be
+		// deleted along with the installer set of previous release
+		if expectedDepSpecHash != hashFromAnnotation ||
+			!equality.Semantic.DeepEqual(existingDeployment.OwnerReferences, expectedDeployment.OwnerReferences) ||
+			!isMetadataApplied(existingDeployment, expectedDeployment) {
 			return i.updateDeployment(existing, existingDeployment, expectedDeployment)
 		}
 
+		i.recordStatus(expected, v1alpha1.ResourceUnchanged, hashFromAnnotation, nil)
 		return nil
 	}
 
@@ -344,83 +565,35 @@ func (i *installer) ensureDeployment(expected *unstructured.Unstructured) error
 }
 
 func (i *installer) IsWebhookReady() error {
-	for _, u := range i.deployment {
-		if !strings.Contains(u.GetName(), "webhook") {
-			continue
-		}
-		err := i.isDeploymentReady(&u)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return i.areWorkloadsReady(i.workloads(v1alpha1.ReadinessRoleWebhook))
 }
 
 func (i *installer) IsControllerReady() error {
-	for _, u := range i.deployment {
-		if !strings.Contains(u.GetName(), "controller") {
-			continue
-		}
-		err := i.isDeploymentReady(&u)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return i.areWorkloadsReady(i.workloads(v1alpha1.ReadinessRoleController))
 }
 
+// AllDeploymentsReady checks the Deployments which are
+// neither webhook nor controller
 func (i *installer) AllDeploymentsReady() error {
-	for _, u := range i.deployment {
-		if strings.Contains(u.GetName(), "controller") ||
-			strings.Contains(u.GetName(), "webhook") {
-			continue
-		}
-		err := i.isDeploymentReady(&u)
-		if err != nil {
-			return err
+	deployments := []unstructured.Unstructured{}
+	for _, u := range i.workloads(v1alpha1.ReadinessRoleWorkload) {
+		if u.GetKind() == "Deployment" {
+			deployments = append(deployments, u)
 		}
 	}
-	return nil
+	return i.areWorkloadsReady(deployments)
 }
 
-func (i *installer) IsJobCompleted(ctx context.Context, labels map[string]string, installSetName string) error {
-	for _, u := range i.manifest.Filter(mf.ByKind("Job")).Resources() {
-		resource, err := i.mfClient.Get(&u)
-		if err != nil {
-			return err
-		}
-		job := &batchv1.Job{}
-		if err := scheme.Scheme.Convert(resource, job, nil); err != nil {
-			return err
+// WorkloadsReady checks the StatefulSets, DaemonSets and Jobs
+// which are neither webhook nor controller
+func (i *installer) WorkloadsReady() error {
+	workloads := []unstructured.Unstructured{}
+	for _, u := range i.workloads(v1alpha1.ReadinessRoleWorkload) {
+		if u.GetKind() != "Deployment" {
+			workloads = append(workloads, u)
 		}
-
-		logger := logging.FromContext(ctx)
-		if !isJobCompleted(job) {
-			logger.Info("job not ready in installerset, name: %s, created-by: %s, in namespace: %s", installSetName, labels[v1alpha1.CreatedByKey], job.GetNamespace())
-			return fmt.Errorf("Job not successful")
-		}
-	}
-
-	return nil
-}
-
-func (i *installer) isDeploymentReady(d *unstructured.Unstructured) error {
-	resource, err := i.mfClient.Get(d)
-	if err != nil {
-		return err
 	}
-
-	deployment := &appsv1.Deployment{}
-	err = runtime.DefaultUnstructuredConverter.FromUnstructured(resource.Object, deployment)
-	if err != nil {
-		return err
-	}
-
-	if !isDeploymentAvailable(deployment) {
-		return fmt.Errorf("%s deployment not ready", deployment.GetName())
-	}
-
-	return nil
+	return i.areWorkloadsReady(workloads)
 }
 
 func isDeploymentAvailable(d *appsv1.Deployment) bool {
@@ -17,7 +17,8 @@ limitations under the License.
 package tektoninstallerset
 
 import (
-	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	mf "github.com/manifestival/manifestival"
@@ -30,9 +31,13 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/ptr"
 )
 
 var (
@@ -105,6 +110,263 @@ func TestEnsureResources_UpdateResource(t *testing.T) {
 	assert.Equal(t, res.GetAnnotations()[v1alpha1.LastAppliedHashKey], expectedHash)
 }
 
+func TestEnsureResources_ContinueOnError(t *testing.T) {
+	brokenConfigMap := namespacedResource("v1", "ConfigMap", "test", "broken")
+	configMap := namespacedResource("v1", "ConfigMap", "test", "config")
+
+	fakeClient := fake.New()
+	create := fakeClient.Stubs.Create
+	fakeClient.Stubs.Create = func(u *unstructured.Unstructured) error {
+		if u.GetName() == brokenConfigMap.GetName() {
+			return fmt.Errorf("admission webhook denied the request")
+		}
+		return create(u)
+	}
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{brokenConfigMap, configMap}))
+	if err != nil {
+		t.Fatalf("Failed to generate manifest: %v", err)
+	}
+
+	// without continueOnError rest of the resources are not attempted
+	i := NewInstaller(&manifest, fakeClient, logger)
+	err = i.EnsureNamespaceScopedResources()
+	assert.Error(t, err, "admission webhook denied the request")
+	assert.Equal(t, len(i.ResourcesStatus()), 1)
+	assert.Equal(t, i.ResourcesStatus()[0].Name, "broken")
+	assert.Equal(t, i.ResourcesStatus()[0].Result, v1alpha1.ResourceFailed)
+	assert.Equal(t, i.ResourcesStatus()[0].Error, "admission webhook denied the request")
+
+	_, err = fakeClient.Get(&configMap)
+	assert.Assert(t, apierrs.IsNotFound(err))
+
+	// with continueOnError rest of the resources are applied
+	i = NewInstaller(&manifest, fakeClient, logger)
+	i.continueOnError = true
+	err = i.EnsureNamespaceScopedResources()
+	assert.Error(t, err, "failed to apply 1 resource(s): ConfigMap test/broken: admission webhook denied the request")
+
+	status := i.ResourcesStatus()
+	assert.Equal(t, len(status), 2)
+	assert.Equal(t, status[0].Result, v1alpha1.ResourceFailed)
+	assert.Equal(t, status[1].Name, "config")
+	assert.Equal(t, status[1].Result, v1alpha1.ResourceCreated)
+	assert.Equal(t, status[1].Error, "")
+
+	res, err := fakeClient.Get(&configMap)
+	assert.NilError(t, err)
+	assert.Equal(t, status[1].Hash, res.GetAnnotations()[v1alpha1.LastAppliedHashKey])
+
+	// applying again reports unchanged resource
+	i = NewInstaller(&manifest, fakeClient, logger)
+	i.continueOnError = true
+	_ = i.EnsureNamespaceScopedResources()
+	assert.Equal(t, i.ResourcesStatus()[1].Result, v1alpha1.ResourceUnchanged)
+}
+
+// fakeApplier applies resources to the fake manifestival client and
+// returns conflict for the resources in conflicts
+type fakeApplier struct {
+	client    mf.Client
+	conflicts map[string]bool
+	applied   []string
+}
+
+func (a *fakeApplier) Apply(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	a.applied = append(a.applied, obj.GetName())
+	if a.conflicts[obj.GetName()] {
+		return nil, apierrs.NewConflict(schema.GroupResource{Resource: obj.GetKind()}, obj.GetName(),
+			fmt.Errorf("conflict with \"kube-controller-manager\": .spec.replicas"))
+	}
+	existing, err := a.client.Get(obj)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return nil, err
+	}
+	applied := obj.DeepCopy()
+	switch {
+	case existing == nil:
+		applied.SetResourceVersion("1")
+	case existing.GetAnnotations()[v1alpha1.LastAppliedHashKey] == obj.GetAnnotations()[v1alpha1.LastAppliedHashKey]:
+		applied.SetResourceVersion(existing.GetResourceVersion())
+	default:
+		applied.SetResourceVersion(existing.GetResourceVersion() + "1")
+	}
+	return applied, a.client.Create(applied)
+}
+
+func TestEnsureResources_ServerSideApply(t *testing.T) {
+	configMap := namespacedResource("v1", "ConfigMap", "test", "config")
+	deployment := namespacedResource("apps/v1", "Deployment", "test", "controller")
+	conflicting := namespacedResource("apps/v1", "Deployment", "test", "webhook")
+
+	fakeClient := fake.New()
+	applier := &fakeApplier{client: fakeClient, conflicts: map[string]bool{"webhook": true}}
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{configMap, deployment, conflicting}))
+	if err != nil {
+		t.Fatalf("Failed to generate manifest: %v", err)
+	}
+
+	i := NewInstaller(&manifest, fakeClient, logger)
+	i.applier = applier
+	i.continueOnError = true
+
+	assert.NilError(t, i.EnsureNamespaceScopedResources())
+	err = i.EnsureDeploymentResources()
+	assert.ErrorContains(t, err, "Deployment test/webhook")
+
+	// deployments are applied the same way as other resources
+	assert.DeepEqual(t, applier.applied, []string{"config", "controller", "webhook"})
+
+	status := i.ResourcesStatus()
+	assert.Equal(t, len(status), 3)
+	assert.Equal(t, status[0].Result, v1alpha1.ResourceCreated)
+	assert.Equal(t, status[1].Result, v1alpha1.ResourceCreated)
+	assert.Equal(t, status[2].Result, v1alpha1.ResourceConflict)
+	assert.Assert(t, strings.Contains(status[2].Error, "kube-controller-manager"))
+
+	i = NewInstaller(&manifest, fakeClient, logger)
+	i.applier = applier
+	assert.NilError(t, i.EnsureNamespaceScopedResources())
+	assert.Equal(t, i.ResourcesStatus()[0].Result, v1alpha1.ResourceUnchanged)
+}
+
+func TestEnsureDeploymentResources_Upgrade(t *testing.T) {
+	deployment := func(name, image string, owner types.UID) unstructured.Unstructured {
+		d := &appsv1.Deployment{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test",
+				Name:      name,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: v1alpha1.SchemeGroupVersion.String(),
+					Kind:       "TektonInstallerSet",
+					Name:       string(owner),
+					UID:        owner,
+					Controller: ptr.Bool(true),
+				}},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: name, Image: image}}},
+				},
+			},
+		}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(d)
+		assert.NilError(t, err)
+		return unstructured.Unstructured{Object: obj}
+	}
+	ensure := func(client mf.Client, deployments ...unstructured.Unstructured) {
+		manifest, err := mf.ManifestFrom(mf.Slice(deployments))
+		assert.NilError(t, err)
+		i := NewInstaller(&manifest, client, zap.NewNop().Sugar())
+		assert.NilError(t, i.EnsureDeploymentResources())
+	}
+	fakeClient := fake.New()
+
+	// the deployments of previous release
+	ensure(fakeClient,
+		deployment("controller", "controller:v1", "previous"),
+		deployment("webhook", "webhook:v1", "previous"))
+
+	// the new release changes the controller but not the webhook
+	controller := deployment("controller", "controller:v2", "main")
+	webhook := deployment("webhook", "webhook:v1", "main")
+	ensure(fakeClient, controller, webhook)
+
+	// the installer set of previous release is deleted once the upgrade
+	// is complete, the deployments it still controls are garbage collected
+	for _, d := range []unstructured.Unstructured{controller, webhook} {
+		if deletableBy(fakeClient, "previous", false)(&d) {
+			assert.NilError(t, fakeClient.Delete(&d))
+		}
+	}
+
+	for _, d := range []unstructured.Unstructured{controller, webhook} {
+		res, err := fakeClient.Get(&d)
+		assert.NilError(t, err)
+		assert.Assert(t, isControlledBy(res, "main"), "%s is not controlled by the main set", d.GetName())
+	}
+}
+
+func TestEnsureDeploymentResources_UpdateOverrides(t *testing.T) {
+	deployment := func(replicas int32, labels, annotations map[string]string) unstructured.Unstructured {
+		d := &appsv1.Deployment{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "test",
+				Name:        "controller",
+				Labels:      labels,
+				Annotations: annotations,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.Int32(replicas),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "controller", Image: "controller:v1"}}},
+				},
+			},
+		}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(d)
+		assert.NilError(t, err)
+		return unstructured.Unstructured{Object: obj}
+	}
+	ensure := func(client mf.Client, d unstructured.Unstructured) *appsv1.Deployment {
+		manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{d}))
+		assert.NilError(t, err)
+		i := NewInstaller(&manifest, client, zap.NewNop().Sugar())
+		assert.NilError(t, i.EnsureDeploymentResources())
+		res, err := client.Get(&d)
+		assert.NilError(t, err)
+		got := &appsv1.Deployment{}
+		assert.NilError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(res.Object, got))
+		return got
+	}
+	scale := func(client mf.Client, d *appsv1.Deployment, replicas int32) {
+		d.Spec.Replicas = ptr.Int32(replicas)
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(d)
+		assert.NilError(t, err)
+		assert.NilError(t, client.Update(&unstructured.Unstructured{Object: obj}))
+	}
+	fakeClient := fake.New()
+
+	// the replicas scaled on cluster are kept without override
+	got := ensure(fakeClient, deployment(1, nil, nil))
+	scale(fakeClient, got, 2)
+	got = ensure(fakeClient, deployment(1, nil, nil))
+	assert.Equal(t, *got.Spec.Replicas, int32(2))
+
+	// the overrides are applied to the existing deployment
+	overridden := deployment(3, map[string]string{"team": "ci"}, map[string]string{
+		"sidecar.istio.io/inject":    "false",
+		v1alpha1.ReplicasOverrideKey: "true",
+	})
+	got = ensure(fakeClient, overridden)
+	assert.Equal(t, *got.Spec.Replicas, int32(3))
+	assert.Equal(t, got.Labels["team"], "ci")
+	assert.Equal(t, got.Annotations["sidecar.istio.io/inject"], "false")
+
+	// the replicas scaled on cluster are reverted to the override
+	scale(fakeClient, got, 5)
+	got = ensure(fakeClient, overridden)
+	assert.Equal(t, *got.Spec.Replicas, int32(3))
+	appliedHash := got.Annotations[v1alpha1.LastAppliedHashKey]
+	got = ensure(fakeClient, overridden)
+	assert.Equal(t, got.Annotations[v1alpha1.LastAppliedHashKey], appliedHash)
+
+	// the replicas are kept again once the override is removed
+	got = ensure(fakeClient, deployment(1, nil, nil))
+	assert.Equal(t, *got.Spec.Replicas, int32(3))
+	_, ok := got.Annotations[v1alpha1.ReplicasOverrideKey]
+	assert.Assert(t, !ok)
+	scale(fakeClient, got, 4)
+	got = ensure(fakeClient, deployment(1, nil, nil))
+	assert.Equal(t, *got.Spec.Replicas, int32(4))
+}
+
 var (
 	readyControllerDeployment = &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -335,7 +597,7 @@ func TestJobCompleted(t *testing.T) {
 	logger := zap.New(observer).Sugar()
 	i := NewInstaller(&manifest, client, logger)
 
-	err = i.IsJobCompleted(context.Background(), nil, "")
+	err = i.WorkloadsReady()
 	if err != nil {
 		t.Fatal("Unexpected Error: ", err)
 	}
@@ -354,7 +616,7 @@ func TestJobFailed(t *testing.T) {
 	logger := zap.New(observer).Sugar()
 	i := NewInstaller(&manifest, client, logger)
 
-	err = i.IsJobCompleted(context.Background(), nil, "")
+	err = i.WorkloadsReady()
 	if err == nil {
 		t.Fatal("Expected Error but got nil ")
 	}
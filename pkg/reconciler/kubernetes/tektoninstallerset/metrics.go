@@ -0,0 +1,262 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektoninstallerset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektoninstallerset/client"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"knative.dev/pkg/metrics"
+)
+
+var (
+	driftCount = stats.Float64("installerset_resource_drift_count",
+		"number of resources found drifted from the installer set manifest",
+		stats.UnitDimensionless)
+	resourceCount = stats.Int64("installerset_resources",
+		"number of resources in the installer set manifest",
+		stats.UnitDimensionless)
+	applyErrorCount = stats.Int64("installerset_apply_errors_count",
+		"number of resources of installer sets which failed to be applied",
+		stats.UnitDimensionless)
+)
+
+// Recorder holds keys for TektonInstallerSet metrics
+type Recorder struct {
+	initialized bool
+	kind        tag.Key
+	policy      tag.Key
+	component   tag.Key
+	setType     tag.Key
+
+	// kinds holds the kinds of resources recorded last per installer set
+	// component and type, to reset the ones removed from the manifest
+	mu    sync.Mutex
+	kinds map[string]map[string]bool
+}
+
+// NewRecorder creates a new metrics recorder instance
+// to log the TektonInstallerSet related metrics
+func NewRecorder() (*Recorder, error) {
+	r := &Recorder{
+		initialized: true,
+		kinds:       map[string]map[string]bool{},
+	}
+
+	kind, err := tag.NewKey("kind")
+	if err != nil {
+		return nil, err
+	}
+	r.kind = kind
+
+	policy, err := tag.NewKey("policy")
+	if err != nil {
+		return nil, err
+	}
+	r.policy = policy
+
+	component, err := tag.NewKey("component")
+	if err != nil {
+		return nil, err
+	}
+	r.component = component
+
+	setType, err := tag.NewKey("type")
+	if err != nil {
+		return nil, err
+	}
+	r.setType = setType
+
+	err = view.Register(
+		&view.View{
+			Description: driftCount.Description(),
+			Measure:     driftCount,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{r.kind, r.policy},
+		},
+		&view.View{
+			Description: resourceCount.Description(),
+			Measure:     resourceCount,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{r.component, r.setType, r.kind},
+		},
+		&view.View{
+			Description: applyErrorCount.Description(),
+			Measure:     applyErrorCount,
+			Aggregation: view.Sum(),
+			TagKeys:     []tag.Key{r.component, r.kind},
+		},
+	)
+
+	if err != nil {
+		r.initialized = false
+		return r, err
+	}
+
+	return r, nil
+}
+
+// CountDrift logs a resource of kind found drifted, with the drift
+// policy of the installer set
+func (r *Recorder) CountDrift(kind, policy string) error {
+	if !r.initialized {
+		return fmt.Errorf(
+			"ignoring the metrics recording for installer set, failed to initialize the metrics recorder")
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.kind, kind),
+		tag.Insert(r.policy, policy),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, driftCount.M(1))
+	return nil
+}
+
+func (r *Recorder) LogDrift(kind, policy string, logger *zap.SugaredLogger) {
+	if r == nil {
+		return
+	}
+	if err := r.CountDrift(kind, policy); err != nil {
+		logger.Warnf("%v: Failed to log the metrics : %v", v1alpha1.KindTektonInstallerSet, err)
+	}
+}
+
+// RecordResources logs the number of resources per kind in the manifest of
+// an installer set, identified by the component which created it and its type
+func (r *Recorder) RecordResources(component, setType string, counts map[string]int64) error {
+	if !r.initialized {
+		return fmt.Errorf(
+			"ignoring the metrics recording for installer set, failed to initialize the metrics recorder")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := component + "/" + setType
+	kinds := map[string]bool{}
+	for kind := range counts {
+		kinds[kind] = true
+	}
+	// the kinds removed from the manifest are reset instead of reporting
+	// their last count
+	for kind := range r.kinds[key] {
+		if !kinds[kind] {
+			counts[kind] = 0
+		}
+	}
+	r.kinds[key] = kinds
+
+	for kind, count := range counts {
+		ctx, err := tag.New(
+			context.Background(),
+			tag.Insert(r.component, component),
+			tag.Insert(r.setType, setType),
+			tag.Insert(r.kind, kind),
+		)
+		if err != nil {
+			return err
+		}
+		metrics.Record(ctx, resourceCount.M(count))
+	}
+	return nil
+}
+
+// CountApplyErrors logs the resources of kind which started failing to be
+// applied
+func (r *Recorder) CountApplyErrors(component, kind string, errors int64) error {
+	if !r.initialized {
+		return fmt.Errorf(
+			"ignoring the metrics recording for installer set, failed to initialize the metrics recorder")
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.component, component),
+		tag.Insert(r.kind, kind),
+	)
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, applyErrorCount.M(errors))
+	return nil
+}
+
+// LogResources logs the number of resources per kind in the manifest and
+// the resources which started failing to apply in the last reconcile of an
+// installer set, previous is the status of the resources before it
+func (r *Recorder) LogResources(installerSet *v1alpha1.TektonInstallerSet, previous []v1alpha1.InstallerSetResourceStatus,
+	resources []unstructured.Unstructured, logger *zap.SugaredLogger) {
+	if r == nil {
+		return
+	}
+	component := installerSet.GetLabels()[v1alpha1.CreatedByKey]
+	setType := installerSet.GetLabels()[v1alpha1.InstallerSetType]
+	// the static and deployment main sets are recorded apart
+	for _, subType := range []string{client.InstallerSubTypeStatic, client.InstallerSubTypeDeployment} {
+		if setType == client.InstallerTypeMain && strings.Contains(installerSet.GetName(), "-"+subType+"-") {
+			setType += "-" + subType
+		}
+	}
+
+	counts := map[string]int64{}
+	for _, res := range resources {
+		counts[res.GetKind()]++
+	}
+	if err := r.RecordResources(component, setType, counts); err != nil {
+		logger.Warnf("%v: Failed to log the metrics : %v", v1alpha1.KindTektonInstallerSet, err)
+	}
+
+	// a resource is counted once when it starts failing, not on every
+	// reconcile while it keeps failing
+	failing := map[v1alpha1.InstallerSetResource]bool{}
+	for _, res := range previous {
+		if isApplyError(res.Result) {
+			failing[res.InstallerSetResource] = true
+		}
+	}
+	errors := map[string]int64{}
+	for _, res := range installerSet.Status.Resources {
+		if isApplyError(res.Result) && !failing[res.InstallerSetResource] {
+			errors[res.Kind]++
+		}
+	}
+	for kind, count := range errors {
+		if err := r.CountApplyErrors(component, kind, count); err != nil {
+			logger.Warnf("%v: Failed to log the metrics : %v", v1alpha1.KindTektonInstallerSet, err)
+		}
+	}
+}
+
+func isApplyError(result v1alpha1.ResourceApplyResult) bool {
+	return result == v1alpha1.ResourceFailed || result == v1alpha1.ResourceConflict
+}
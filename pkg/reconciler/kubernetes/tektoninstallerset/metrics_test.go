@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektoninstallerset
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"go.opencensus.io/stats/view"
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/metrics"
+)
+
+// newTestRecorder returns a recorder whose views are unregistered after
+// the test, to be registered again by the next one
+func newTestRecorder(t *testing.T) *Recorder {
+	t.Helper()
+	metrics.InitForTesting()
+	r, err := NewRecorder()
+	assert.NilError(t, err)
+	t.Cleanup(func() {
+		for _, name := range []string{"installerset_resource_drift_count", "installerset_resources", "installerset_apply_errors_count"} {
+			view.Unregister(view.Find(name))
+		}
+	})
+	return r
+}
+
+func TestRecordResources(t *testing.T) {
+	r := newTestRecorder(t)
+
+	counts := func() []string {
+		rows, err := view.RetrieveData("installerset_resources")
+		assert.NilError(t, err)
+		var res []string
+		for _, row := range rows {
+			s := ""
+			for _, tag := range row.Tags {
+				s += tag.Key.Name() + "=" + tag.Value + ","
+			}
+			res = append(res, fmt.Sprintf("%s %v", s, row.Data.(*view.LastValueData).Value))
+		}
+		sort.Strings(res)
+		return res
+	}
+
+	assert.NilError(t, r.RecordResources("TektonPipeline", "main-deployment", map[string]int64{"Deployment": 3, "Service": 2}))
+	assert.NilError(t, r.RecordResources("TektonTrigger", "main-deployment", map[string]int64{"Deployment": 2}))
+	// the services are removed from the manifest
+	assert.NilError(t, r.RecordResources("TektonPipeline", "main-deployment", map[string]int64{"Deployment": 4}))
+
+	assert.DeepEqual(t, counts(), []string{
+		"component=TektonPipeline,kind=Deployment,type=main-deployment, 4",
+		"component=TektonPipeline,kind=Service,type=main-deployment, 0",
+		"component=TektonTrigger,kind=Deployment,type=main-deployment, 2",
+	})
+}
+
+func TestLogResourcesApplyErrors(t *testing.T) {
+	r := newTestRecorder(t)
+	logger := zap.NewNop().Sugar()
+
+	errors := func() []string {
+		rows, err := view.RetrieveData("installerset_apply_errors_count")
+		assert.NilError(t, err)
+		var res []string
+		for _, row := range rows {
+			s := ""
+			for _, tag := range row.Tags {
+				s += tag.Key.Name() + "=" + tag.Value + ","
+			}
+			res = append(res, fmt.Sprintf("%s %v", s, row.Data.(*view.SumData).Value))
+		}
+		sort.Strings(res)
+		return res
+	}
+
+	failed := func(name string) v1alpha1.InstallerSetResourceStatus {
+		return v1alpha1.InstallerSetResourceStatus{
+			InstallerSetResource: v1alpha1.InstallerSetResource{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "test", Name: name},
+			Result:               v1alpha1.ResourceFailed,
+		}
+	}
+	installerSet := &v1alpha1.TektonInstallerSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "pipeline-main-deployment-abc12",
+			Labels: map[string]string{v1alpha1.CreatedByKey: "TektonPipeline", v1alpha1.InstallerSetType: "main"},
+		},
+	}
+
+	installerSet.Status.Resources = []v1alpha1.InstallerSetResourceStatus{failed("controller")}
+	r.LogResources(installerSet, nil, nil, logger)
+
+	// a resource which keeps failing is not counted again
+	previous := installerSet.Status.Resources
+	installerSet.Status.Resources = []v1alpha1.InstallerSetResourceStatus{failed("controller"), failed("webhook")}
+	r.LogResources(installerSet, previous, nil, logger)
+
+	assert.DeepEqual(t, errors(), []string{"component=TektonPipeline,kind=Deployment, 2"})
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektoninstallerset
+
+import (
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Inventory returns the list of resources in the installer manifest
+func (i *installer) Inventory() []v1alpha1.InstallerSetResource {
+	inventory := []v1alpha1.InstallerSetResource{}
+	for _, r := range i.manifest.Resources() {
+		inventory = append(inventory, v1alpha1.InstallerSetResource{
+			APIVersion: r.GetAPIVersion(),
+			Kind:       r.GetKind(),
+			Namespace:  r.GetNamespace(),
+			Name:       r.GetName(),
+		})
+	}
+	return inventory
+}
+
+// PruneRemovedResources deletes the resources from previous inventory which
+// are not part of the manifest anymore.
+// CRDs, Namespaces and PersistentVolumeClaims are never deleted as they are
+// owned by the owner of installer set, and resources which are not controlled
+// by the installer set (for eg. moved to another installer set) are skipped
+func (i *installer) PruneRemovedResources(previous []v1alpha1.InstallerSetResource, owner types.UID) error {
+	current := map[inventoryKey]bool{}
+	for _, r := range i.Inventory() {
+		current[keyOf(r)] = true
+	}
+
+	for _, r := range previous {
+		if current[keyOf(r)] || !isPrunable(r.Kind) {
+			continue
+		}
+
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(r.APIVersion)
+		u.SetKind(r.Kind)
+		u.SetNamespace(r.Namespace)
+		u.SetName(r.Name)
+
+		existing, err := i.mfClient.Get(u)
+		if err != nil {
+			if apierrs.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		if !isControlledBy(existing, owner) {
+			i.logger.Infof("skipping removal of %s: %s/%s, not controlled by installer set", r.Kind, r.Namespace, r.Name)
+			continue
+		}
+
+		i.logger.Infof("deleting resource removed from manifest %s: %s/%s", r.Kind, r.Namespace, r.Name)
+		if err := i.mfClient.Delete(existing); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// inventoryKey identifies a resource of the inventory regardless of the
+// version of its API, so that a resource moved to a new version of its API
+// by a release is not seen as removed
+type inventoryKey struct {
+	Group     string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func keyOf(r v1alpha1.InstallerSetResource) inventoryKey {
+	gv, _ := schema.ParseGroupVersion(r.APIVersion)
+	return inventoryKey{Group: gv.Group, Kind: r.Kind, Namespace: r.Namespace, Name: r.Name}
+}
+
+func isPrunable(kind string) bool {
+	switch strings.ToLower(kind) {
+	case "customresourcedefinition", "namespace", "persistentvolumeclaim":
+		return false
+	}
+	return true
+}
+
+func isControlledBy(u *unstructured.Unstructured, owner types.UID) bool {
+	ref := metav1.GetControllerOf(u)
+	return ref != nil && ref.UID == owner
+}
+
+// deletableBy returns a predicate matching the resources which are deleted
+// with the installer set, the ones controlled by owner and if uncontrolled is
+// true the ones without a controller. Resources taken over by another
+// installer set, for eg. on upgrade, are not matched
+func deletableBy(client mf.Client, owner types.UID, uncontrolled bool) mf.Predicate {
+	return func(u *unstructured.Unstructured) bool {
+		existing, err := client.Get(u)
+		if err != nil {
+			return !apierrs.IsNotFound(err)
+		}
+		ref := metav1.GetControllerOf(existing)
+		if ref == nil {
+			return uncontrolled
+		}
+		return ref.UID == owner
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektoninstallerset
+
+import (
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/manifestival/manifestival/fake"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/ptr"
+)
+
+func ownedResource(apiVersion, kind, ns, name string, owner types.UID) unstructured.Unstructured {
+	resource := namespacedResource(apiVersion, kind, ns, name)
+	resource.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       v1alpha1.KindTektonInstallerSet,
+		Name:       "test-set",
+		UID:        owner,
+		Controller: ptr.Bool(true),
+	}})
+	return resource
+}
+
+func toInventory(resources ...unstructured.Unstructured) []v1alpha1.InstallerSetResource {
+	inventory := []v1alpha1.InstallerSetResource{}
+	for _, r := range resources {
+		inventory = append(inventory, v1alpha1.InstallerSetResource{
+			APIVersion: r.GetAPIVersion(),
+			Kind:       r.GetKind(),
+			Namespace:  r.GetNamespace(),
+			Name:       r.GetName(),
+		})
+	}
+	return inventory
+}
+
+func TestPruneRemovedResources(t *testing.T) {
+	owner := types.UID("installer-set-uid")
+
+	kept := ownedResource("v1", "ServiceAccount", "test", "kept", owner)
+	removed := ownedResource("v1", "ConfigMap", "test", "removed", owner)
+	movedToOtherSet := ownedResource("v1", "ConfigMap", "test", "moved", "other-uid")
+	namespace := ownedResource("v1", "Namespace", "", "test", owner)
+	alreadyDeleted := ownedResource("v1", "Secret", "test", "deleted", owner)
+
+	fakeClient := fake.New(&kept, &removed, &movedToOtherSet, &namespace)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{kept}))
+	assert.NilError(t, err)
+
+	i := NewInstaller(&manifest, fakeClient, logger)
+
+	previous := toInventory(kept, removed, movedToOtherSet, namespace, alreadyDeleted)
+	err = i.PruneRemovedResources(previous, owner)
+	assert.NilError(t, err)
+
+	_, err = fakeClient.Get(&removed)
+	assert.Assert(t, apierrs.IsNotFound(err))
+
+	for _, r := range []unstructured.Unstructured{kept, movedToOtherSet, namespace} {
+		_, err = fakeClient.Get(&r)
+		assert.NilError(t, err)
+	}
+
+	assert.DeepEqual(t, i.Inventory(), toInventory(kept))
+}
+
+func TestPruneRemovedResourcesKeepsNewAPIVersion(t *testing.T) {
+	owner := types.UID("installer-set-uid")
+
+	// the release moved the PodDisruptionBudget from policy/v1beta1 to policy/v1
+	pdb := ownedResource("policy/v1", "PodDisruptionBudget", "test", "webhook", owner)
+	fakeClient := fake.New(&pdb)
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{pdb}))
+	assert.NilError(t, err)
+
+	i := NewInstaller(&manifest, fakeClient, zap.NewNop().Sugar())
+	previous := toInventory(ownedResource("policy/v1beta1", "PodDisruptionBudget", "test", "webhook", owner))
+	assert.NilError(t, i.PruneRemovedResources(previous, owner))
+
+	_, err = fakeClient.Get(&pdb)
+	assert.NilError(t, err)
+}
+
+func TestDeletableBy(t *testing.T) {
+	owned := ownedResource("v1", "ConfigMap", "test", "owned", "old-set")
+	adopted := ownedResource("v1", "ConfigMap", "test", "adopted", "new-set")
+	webhook := namespacedResource("admissionregistration.k8s.io/v1", "ValidatingWebhookConfiguration", "", "webhook")
+	missing := namespacedResource("v1", "ConfigMap", "test", "missing")
+
+	client := fake.New(owned.DeepCopy(), adopted.DeepCopy(), webhook.DeepCopy())
+	in := []unstructured.Unstructured{owned, adopted, webhook, missing}
+	manifest, err := mf.ManifestFrom(mf.Slice(in), mf.UseClient(client))
+	assert.NilError(t, err)
+
+	deletable := manifest.Filter(deletableBy(client, "old-set", true))
+	assert.DeepEqual(t, toInventory(deletable.Resources()...), toInventory(owned, webhook))
+
+	// resources without controller are kept for retired installer sets
+	deletable = manifest.Filter(deletableBy(client, "old-set", false))
+	assert.DeepEqual(t, toInventory(deletable.Resources()...), toInventory(owned))
+}
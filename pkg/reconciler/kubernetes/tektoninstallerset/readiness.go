@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektoninstallerset
+
+import (
+	"fmt"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektoninstallerset/client"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// workloads returns the Deployments, StatefulSets, DaemonSets
+// and Jobs in manifest with the role
+func (i *installer) workloads(role string) []unstructured.Unstructured {
+	res := []unstructured.Unstructured{}
+	for _, u := range i.manifest.Filter(mf.Any(mf.ByKind("Deployment"), mf.ByKind("StatefulSet"), mf.ByKind("DaemonSet"), mf.ByKind("Job"))).Resources() {
+		if client.ReadinessRole(&u) == role {
+			res = append(res, u)
+		}
+	}
+	return res
+}
+
+// CRDsEstablished checks if all the CRDs are established
+func (i *installer) CRDsEstablished() error {
+	for _, u := range i.crds {
+		resource, err := i.mfClient.Get(&u)
+		if err != nil {
+			return err
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resource.Object, crd); err != nil {
+			return err
+		}
+		if !isCRDEstablished(crd) {
+			return fmt.Errorf("%s crd not established", crd.GetName())
+		}
+	}
+	return nil
+}
+
+func (i *installer) areWorkloadsReady(workloads []unstructured.Unstructured) error {
+	for _, u := range workloads {
+		if err := i.isWorkloadReady(&u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *installer) isWorkloadReady(u *unstructured.Unstructured) error {
+	resource, err := i.mfClient.Get(u)
+	if err != nil {
+		return err
+	}
+
+	switch u.GetKind() {
+	case "Deployment":
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resource.Object, deployment); err != nil {
+			return err
+		}
+		if !isDeploymentAvailable(deployment) {
+			return fmt.Errorf("%s deployment not ready", deployment.GetName())
+		}
+	case "StatefulSet":
+		statefulSet := &appsv1.StatefulSet{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resource.Object, statefulSet); err != nil {
+			return err
+		}
+		if !isStatefulSetReady(statefulSet) {
+			return fmt.Errorf("%s statefulset not ready", statefulSet.GetName())
+		}
+	case "DaemonSet":
+		daemonSet := &appsv1.DaemonSet{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resource.Object, daemonSet); err != nil {
+			return err
+		}
+		if !isDaemonSetReady(daemonSet) {
+			return fmt.Errorf("%s daemonset not ready", daemonSet.GetName())
+		}
+	case "Job":
+		job := &batchv1.Job{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resource.Object, job); err != nil {
+			return err
+		}
+		if !isJobCompleted(job) {
+			return fmt.Errorf("%s job not completed", job.GetName())
+		}
+	}
+	return nil
+}
+
+func isStatefulSetReady(s *appsv1.StatefulSet) bool {
+	replicas := int32(1)
+	if s.Spec.Replicas != nil {
+		replicas = *s.Spec.Replicas
+	}
+	return s.Status.ObservedGeneration >= s.Generation &&
+		s.Status.ReadyReplicas >= replicas &&
+		s.Status.UpdatedReplicas >= replicas
+}
+
+func isDaemonSetReady(d *appsv1.DaemonSet) bool {
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.NumberReady >= d.Status.DesiredNumberScheduled &&
+		d.Status.UpdatedNumberScheduled >= d.Status.DesiredNumberScheduled
+}
+
+func isCRDEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	for _, c := range crd.Status.Conditions {
+		if c.Type == apiextensionsv1.Established && c.Status == apiextensionsv1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektoninstallerset
+
+import (
+	"testing"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/manifestival/manifestival/fake"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/ptr"
+)
+
+func TestWorkloadsReady(t *testing.T) {
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "postgres", Generation: 2},
+		Spec:       appsv1.StatefulSetSpec{Replicas: ptr.Int32(1)},
+		Status:     appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 0, UpdatedReplicas: 1},
+	}
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "agent"},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3, UpdatedNumberScheduled: 3},
+	}
+
+	in := []unstructured.Unstructured{
+		namespacedResource("apps/v1", "StatefulSet", "test", "postgres"),
+		namespacedResource("apps/v1", "DaemonSet", "test", "agent"),
+	}
+
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	client := fake.New([]runtime.Object{statefulSet, daemonSet}...)
+	manifest, err := mf.ManifestFrom(mf.Slice(in), mf.UseClient(client))
+	assert.NilError(t, err)
+
+	i := NewInstaller(&manifest, client, logger)
+	assert.Error(t, i.WorkloadsReady(), "postgres statefulset not ready")
+
+	statefulSet.Status.ReadyReplicas = 1
+	client = fake.New([]runtime.Object{statefulSet, daemonSet}...)
+	i = NewInstaller(&manifest, client, logger)
+	assert.NilError(t, i.WorkloadsReady())
+
+	daemonSet.Status.NumberReady = 1
+	client = fake.New([]runtime.Object{statefulSet, daemonSet}...)
+	i = NewInstaller(&manifest, client, logger)
+	assert.Error(t, i.WorkloadsReady(), "agent daemonset not ready")
+
+	// workloads with role none are not checked
+	in[1].SetAnnotations(map[string]string{v1alpha1.ReadinessRoleKey: v1alpha1.ReadinessRoleNone})
+	manifest, err = mf.ManifestFrom(mf.Slice(in), mf.UseClient(client))
+	assert.NilError(t, err)
+	i = NewInstaller(&manifest, client, logger)
+	assert.NilError(t, i.WorkloadsReady())
+}
+
+func TestWebhookReady_StatefulSetRole(t *testing.T) {
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "interceptors"},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 0},
+	}
+
+	u := namespacedResource("apps/v1", "StatefulSet", "test", "interceptors")
+	u.SetAnnotations(map[string]string{v1alpha1.ReadinessRoleKey: v1alpha1.ReadinessRoleWebhook})
+
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	client := fake.New(statefulSet)
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{u}), mf.UseClient(client))
+	assert.NilError(t, err)
+
+	i := NewInstaller(&manifest, client, logger)
+	assert.Error(t, i.IsWebhookReady(), "interceptors statefulset not ready")
+	assert.NilError(t, i.WorkloadsReady())
+}
+
+func TestCRDsEstablished(t *testing.T) {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: "tasks.tekton.dev"},
+	}
+	u := namespacedResource("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "tasks.tekton.dev")
+
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(crd)
+	assert.NilError(t, err)
+	live := &unstructured.Unstructured{Object: obj}
+
+	client := fake.New(live)
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{u}), mf.UseClient(client))
+	assert.NilError(t, err)
+
+	i := NewInstaller(&manifest, client, logger)
+	assert.Error(t, i.CRDsEstablished(), "tasks.tekton.dev crd not established")
+
+	crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{{
+		Type:   apiextensionsv1.Established,
+		Status: apiextensionsv1.ConditionTrue,
+	}}
+	obj, err = runtime.DefaultUnstructuredConverter.ToUnstructured(crd)
+	assert.NilError(t, err)
+
+	client = fake.New(&unstructured.Unstructured{Object: obj})
+	i = NewInstaller(&manifest, client, logger)
+	assert.NilError(t, i.CRDsEstablished())
+}
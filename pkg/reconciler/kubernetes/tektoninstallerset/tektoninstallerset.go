@@ -19,12 +19,17 @@ package tektoninstallerset
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	mf "github.com/manifestival/manifestival"
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	clientset "github.com/tektoncd/operator/pkg/client/clientset/versioned"
 	tektonInstallerreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektoninstallerset"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektoninstallerset/client"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 )
@@ -33,6 +38,8 @@ import (
 type Reconciler struct {
 	operatorClientSet clientset.Interface
 	mfClient          mf.Client
+	applier           Applier
+	metrics           *Recorder
 }
 
 // Reconciler implements controller.Reconciler
@@ -54,6 +61,12 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, installerSet *v1alpha1.Te
 	// TektonInstallerSet
 	// They will be deleted when the component CR is deleted
 	deleteManifests = deleteManifests.Filter(mf.Not(mf.Any(mf.ByKind("Namespace"), mf.CRDs, mf.ByKind("PersistentVolumeClaim"))))
+
+	// Resources of an installer set retired on upgrade may have been taken
+	// over by the new installer sets, delete only the ones still controlled
+	// by it
+	retired := installerSet.GetLabels()[v1alpha1.InstallerSetType] == client.InstallerTypePrevious
+	deleteManifests = deleteManifests.Filter(deletableBy(r.mfClient, installerSet.GetUID(), !retired))
 	err = deleteManifests.Delete()
 	if err != nil {
 		logger.Error("failed to delete resources")
@@ -71,9 +84,17 @@ func getReference(tis *v1alpha1.TektonInstallerSet) []v1.OwnerReference {
 // ReconcileKind compares the actual state with the desired, and attempts to
 // converge the two.
 func (r *Reconciler) ReconcileKind(ctx context.Context, installerSet *v1alpha1.TektonInstallerSet) pkgreconciler.Event {
-	installerSet.Status.InitializeConditions()
 	logger := logging.FromContext(ctx).With("installerSet", fmt.Sprintf("%s/%s", installerSet.Namespace, installerSet.Name))
 
+	// Installer sets retired on upgrade are kept as they are, to be restored
+	// if the upgrade is rolled back
+	if installerSet.GetLabels()[v1alpha1.InstallerSetType] == client.InstallerTypePrevious {
+		logger.Info("installer set retired on upgrade, skipping reconcile")
+		return nil
+	}
+
+	installerSet.Status.InitializeConditions()
+
 	installManifests, err := mf.ManifestFrom(installerSet.Spec.Manifests, mf.UseClient(r.mfClient))
 	if err != nil {
 		logger.Error("Error creating initial manifest: ", err)
@@ -90,6 +111,7 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, installerSet *v1alpha1.T
 	installManifests, err = installManifests.Transform(
 		injectOwner(getReference(installerSet)),
 		injectOwnerForCRDsAndNamespace(installerSetOwner),
+		injectManagedLabel(),
 	)
 	if err != nil {
 		logger.Error("failed to transform manifest")
@@ -97,6 +119,18 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, installerSet *v1alpha1.T
 	}
 
 	installer := NewInstaller(&installManifests, r.mfClient, logger)
+	installer.continueOnError = installerSet.Spec.ContinueOnError
+	installer.driftPolicy = installerSet.Spec.DriftPolicy
+	if installerSet.Spec.ServerSideApply {
+		installer.applier = r.applier
+	}
+
+	// Report the apply status of each resource attempted in this reconcile
+	defer func() {
+		previous := installerSet.Status.Resources
+		installerSet.Status.Resources = installer.ResourcesStatus()
+		r.metrics.LogResources(installerSet, previous, installManifests.Resources(), logger)
+	}()
 
 	// Install CRDs
 	err = installer.EnsureCRDs()
@@ -138,6 +172,17 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, installerSet *v1alpha1.T
 	// Update Status for Deployment Resources
 	installerSet.Status.MarkDeploymentsAvailable()
 
+	r.reportDrift(ctx, installerSet, installer.DriftedResources())
+
+	// Delete resources which were applied previously but are
+	// removed from the manifests now
+	err = installer.PruneRemovedResources(installerSet.Status.Inventory, installerSet.GetUID())
+	if err != nil {
+		logger.Error("failed to delete resources removed from manifest: ", err)
+		return err
+	}
+	installerSet.Status.Inventory = installer.Inventory()
+
 	// Check if webhook is ready
 	err = installer.IsWebhookReady()
 	if err != nil {
@@ -158,14 +203,6 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, installerSet *v1alpha1.T
 	// Update Ready status of Controller
 	installerSet.Status.MarkControllerReady()
 
-	// job
-	labels := installerSet.GetLabels()
-	installSetname := installerSet.GetName()
-	err = installer.IsJobCompleted(ctx, labels, installSetname)
-	if err != nil {
-		return err
-	}
-
 	// Check if any other deployment exists other than controller
 	// and webhook and is ready
 	err = installer.AllDeploymentsReady()
@@ -177,9 +214,72 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, installerSet *v1alpha1.T
 	// Mark all deployments ready
 	installerSet.Status.MarkAllDeploymentsReady()
 
+	// Check if StatefulSets and DaemonSets are ready and Jobs are completed
+	err = installer.WorkloadsReady()
+	if err != nil {
+		installerSet.Status.MarkWorkloadsNotReady(err.Error())
+		return v1alpha1.REQUEUE_EVENT_AFTER
+	}
+
+	installerSet.Status.MarkWorkloadsReady()
+
+	// Check if CRDs are established
+	err = installer.CRDsEstablished()
+	if err != nil {
+		installerSet.Status.MarkCRDsNotEstablished(err.Error())
+		return v1alpha1.REQUEUE_EVENT_AFTER
+	}
+
+	installerSet.Status.MarkCRDsEstablished()
+
 	return nil
 }
 
+// reportDrift reports the resources drifted from the manifests through
+// condition, event and metric as per the drift policy of installer set
+func (r *Reconciler) reportDrift(ctx context.Context, installerSet *v1alpha1.TektonInstallerSet, drifted []unstructured.Unstructured) {
+	policy := installerSet.Spec.DriftPolicy
+	if policy != v1alpha1.DriftPolicyEnforce && policy != v1alpha1.DriftPolicyReport {
+		return
+	}
+	logger := logging.FromContext(ctx)
+
+	if len(drifted) == 0 {
+		installerSet.Status.MarkResourcesInSync()
+		return
+	}
+
+	names := []string{}
+	for _, d := range drifted {
+		names = append(names, fmt.Sprintf("%s %s", d.GetKind(), resourceName(&d)))
+	}
+	msg := strings.Join(names, ", ")
+
+	// resources left drifted are reported once, not on every reconcile
+	// until they are back in sync
+	previous := installerSet.Status.GetCondition(v1alpha1.ResourcesInSync)
+	if policy == v1alpha1.DriftPolicyReport {
+		installerSet.Status.MarkResourcesDrifted(msg)
+		current := installerSet.Status.GetCondition(v1alpha1.ResourcesInSync)
+		if previous != nil && previous.IsFalse() && previous.Message == current.Message {
+			return
+		}
+	}
+	for _, d := range drifted {
+		r.metrics.LogDrift(d.GetKind(), string(policy), logger)
+	}
+
+	if policy == v1alpha1.DriftPolicyEnforce {
+		// drifted resources are reverted so they are in sync now
+		installerSet.Status.MarkResourcesInSync()
+		common.RecordEvent(ctx, installerSet, corev1.EventTypeNormal, common.EventDriftCorrected,
+			"Reverted resources drifted from manifests of release %s: %s", installerSet.GetLabels()[v1alpha1.ReleaseVersionKey], msg)
+		return
+	}
+	common.RecordEvent(ctx, installerSet, corev1.EventTypeWarning, common.EventResourcesDrifted,
+		"Resources drifted from manifests: %s", msg)
+}
+
 func (r *Reconciler) handleError(err error, installerSet *v1alpha1.TektonInstallerSet) error {
 	if err == v1alpha1.RECONCILE_AGAIN_ERR {
 		return v1alpha1.REQUEUE_EVENT_AFTER
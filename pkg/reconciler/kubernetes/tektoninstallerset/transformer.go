@@ -18,6 +18,7 @@ package tektoninstallerset
 
 import (
 	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -50,3 +51,17 @@ func injectOwnerForCRDsAndNamespace(owner []v1.OwnerReference) mf.Transformer {
 		return nil
 	}
 }
+
+// injectManagedLabel labels the resources as applied by an installer set,
+// so that they are watched by the operator
+func injectManagedLabel() mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		labels := u.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[v1alpha1.ManagedByInstallerSetKey] = "true"
+		u.SetLabels(labels)
+		return nil
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/ptr"
 )
@@ -94,3 +95,19 @@ func TestInjectOwner_NonCRDs(t *testing.T) {
 	}
 
 }
+
+func TestInjectManagedLabel(t *testing.T) {
+
+	testData := path.Join("testdata", "test-non-crd.yaml")
+	sourceManifest, _ := mf.ManifestFrom(mf.Recursive(testData))
+
+	manifest, err := sourceManifest.Transform(injectManagedLabel())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	for _, r := range manifest.Resources() {
+		if r.GetLabels()[v1alpha1.ManagedByInstallerSetKey] != "true" {
+			t.Fatalf("%s %s is not labelled as managed", r.GetKind(), r.GetName())
+		}
+	}
+}
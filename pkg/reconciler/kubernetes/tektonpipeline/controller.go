@@ -69,9 +69,11 @@ func NewExtendedController(generator common.ExtensionGenerator) injection.Contro
 			kubeClientSet:   kubeclient.Get(ctx),
 			extension:       generator(ctx),
 			manifest:        manifest,
+			ctrl:            ctrl,
 			pipelineVersion: pipelineVer,
 			installerSetClient: client.NewInstallerSetClient(tisClient, &manifest,
-				operatorVer, pipelineVer, v1alpha1.KindTektonPipeline, filterAndTransform(generator(ctx)), metrics),
+				operatorVer, pipelineVer, v1alpha1.KindTektonPipeline, filterAndTransform(generator(ctx)), metrics).
+				WithHealthCheck(healthCheck(manifest.Client)),
 		}
 		impl := tektonPipelineReconciler.NewImpl(ctx, c)
 
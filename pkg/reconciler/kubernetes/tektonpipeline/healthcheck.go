@@ -0,0 +1,53 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonpipeline
+
+import (
+	"context"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektoninstallerset/client"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// healthCheck verifies the Pipelines webhook rolled out by a staged upgrade
+// admits a sample Task, by creating it in dry-run mode
+func healthCheck(mfClient mf.Client) client.HealthCheck {
+	return func(ctx context.Context, comp v1alpha1.TektonComponent) error {
+		sample := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "tekton.dev/v1beta1",
+				"kind":       "Task",
+				"metadata": map[string]interface{}{
+					"generateName": "tekton-operator-health-check-",
+					"namespace":    comp.GetSpec().GetTargetNamespace(),
+				},
+				"spec": map[string]interface{}{
+					"steps": []interface{}{
+						map[string]interface{}{
+							"name":   "health-check",
+							"image":  "busybox",
+							"script": "exit 0",
+						},
+					},
+				},
+			},
+		}
+		return mfClient.Create(sample, mf.DryRunAll)
+	}
+}
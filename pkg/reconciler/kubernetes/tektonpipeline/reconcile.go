@@ -47,6 +47,8 @@ type Reconciler struct {
 	manifest mf.Manifest
 	// Platform-specific behavior to affect the transform
 	extension common.Extension
+	// ctrl loads the manifest of version pinned in spec
+	ctrl common.Controller
 	// kube client to interact with core k8s resources
 	kubeClientSet kubernetes.Interface
 	// version of pipelines which we are installing
@@ -61,7 +63,6 @@ var _ tektonpipelinereconciler.Interface = (*Reconciler)(nil)
 func (r *Reconciler) ReconcileKind(ctx context.Context, tp *v1alpha1.TektonPipeline) pkgreconciler.Event {
 	logger := logging.FromContext(ctx).With("name", tp.GetName())
 	tp.Status.InitializeConditions()
-	tp.Status.SetVersion(r.pipelineVersion)
 
 	if tp.GetName() != v1alpha1.PipelineResourceName {
 		msg := fmt.Sprintf("Resource ignored, Expected Name: %s, Got Name: %s",
@@ -76,6 +77,15 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tp *v1alpha1.TektonPipel
 	// Pass the object through defaulting
 	tp.SetDefaults(ctx)
 
+	// Install the version pinned in spec if any
+	manifest, pipelineVersion, err := r.ctrl.TargetPayload(tp, r.manifest, r.pipelineVersion)
+	if err != nil {
+		logger.Error(err)
+		tp.Status.MarkNotReady(err.Error())
+		return nil
+	}
+	installerSetClient := r.installerSetClient.WithManifest(&manifest, pipelineVersion)
+
 	if err := r.targetNamespaceCheck(ctx, tp); err != nil {
 		return err
 	}
@@ -88,11 +98,16 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tp *v1alpha1.TektonPipel
 	// Mark PreReconcile Complete
 	tp.Status.MarkPreReconcilerComplete()
 
-	if err := r.installerSetClient.MainSet(ctx, tp); err != nil {
+	if err := installerSetClient.MainSet(ctx, tp); err != nil {
 		logger.Errorf("failed for main set: %v", err)
 		return err
 	}
 
+	// report the version once it is installed
+	if tp.Status.GetCondition(v1alpha1.InstallerSetReady).IsTrue() {
+		tp.Status.SetVersion(pipelineVersion)
+	}
+
 	if err := r.extension.PostReconcile(ctx, tp); err != nil {
 		tp.Status.MarkPostReconcilerFailed(fmt.Sprintf("PostReconciliation failed: %s", err.Error()))
 		return err
@@ -51,6 +51,7 @@ func filterAndTransform(extension common.Extension) client.FilterAndTransform {
 			common.DeploymentImages(images),
 			common.InjectLabelOnNamespace(proxyLabel),
 			common.AddConfiguration(pipeline.Spec.Config),
+			common.AddOptions(pipeline.Spec.Options),
 		}
 		trns = append(trns, extra...)
 
@@ -22,13 +22,13 @@ import (
 
 	mf "github.com/manifestival/manifestival"
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
-	"github.com/tektoncd/operator/pkg/reconciler/shared/hash"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func (r *Reconciler) createInstallerSet(ctx context.Context, tr *v1alpha1.TektonResult) (*v1alpha1.TektonInstallerSet, error) {
 
-	if err := r.transform(ctx, &r.manifest, tr); err != nil {
+	manifest := r.manifest.Append()
+	if err := r.transform(ctx, &manifest, tr); err != nil {
 		tr.Status.MarkNotReady("transformation failed: " + err.Error())
 		return nil, err
 	}
@@ -37,13 +37,13 @@ func (r *Reconciler) createInstallerSet(ctx context.Context, tr *v1alpha1.Tekton
 	// in further reconciliation we compute hash of td spec and check with
 	// annotation, if they are same then we skip updating the object
 	// otherwise we update the manifest
-	specHash, err := hash.Compute(tr.Spec)
+	specHash, err := r.specHash(ctx, tr)
 	if err != nil {
 		return nil, err
 	}
 
 	// create installer set
-	tis := r.makeInstallerSet(tr, r.manifest, specHash)
+	tis := r.makeInstallerSet(tr, manifest, specHash)
 	createdIs, err := r.operatorClientSet.OperatorV1alpha1().TektonInstallerSets().
 		Create(ctx, tis, metav1.CreateOptions{})
 	if err != nil {
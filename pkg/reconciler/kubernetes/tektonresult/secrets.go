@@ -0,0 +1,306 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonresult
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"github.com/tektoncd/operator/pkg/reconciler/shared/hash"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	dbUserKey     = "POSTGRES_USER"
+	dbPasswordKey = "POSTGRES_PASSWORD"
+	defaultDBUser = "postgres"
+
+	apiService = "tekton-results-api-service"
+
+	// the generated certificates are renewed a month before they expire
+	certValidity    = 365 * 24 * time.Hour
+	certRenewBefore = 30 * 24 * time.Hour
+)
+
+func dbSecretName(db *v1alpha1.ResultsDB) string {
+	if db == nil || db.SecretName == "" {
+		return DbSecretName
+	}
+	return db.SecretName
+}
+
+func tlsSecretName(tls *v1alpha1.ResultsTLS) string {
+	if tls == nil || tls.SecretName == "" {
+		return TlsSecretName
+	}
+	return tls.SecretName
+}
+
+// reconcileSecrets makes sure the database and TLS Secrets exist, creating
+// them when the operator is in charge. It returns the time left until the
+// generated certificate is renewed, 0 if there is none
+func (r *Reconciler) reconcileSecrets(ctx context.Context, tr *v1alpha1.TektonResult, now time.Time) (time.Duration, error) {
+	if err := r.reconcileDBSecret(ctx, tr); err != nil {
+		return 0, err
+	}
+	return r.reconcileTLSSecret(ctx, tr, now)
+}
+
+// reconcileDBSecret generates the credentials of a provisioned database which
+// are missing, the Secret of an external database has to be created by hand.
+// The Secret has no owner so that it is kept along with the data of Postgres
+func (r *Reconciler) reconcileDBSecret(ctx context.Context, tr *v1alpha1.TektonResult) error {
+	logger := logging.FromContext(ctx)
+	name := dbSecretName(tr.Spec.DB)
+	secrets := r.kubeClientSet.CoreV1().Secrets(tr.Spec.TargetNamespace)
+
+	secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	create := apierrors.IsNotFound(err)
+	if err != nil && !create {
+		logger.Error(err)
+		return err
+	}
+	if !tr.Spec.DB.IsProvisioned() {
+		if create {
+			logger.Error(err)
+			tr.Status.MarkDependencyMissing(fmt.Sprintf("%s secret is missing", name))
+		}
+		return err
+	}
+
+	if create {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: tr.Spec.TargetNamespace,
+				Labels: map[string]string{
+					v1alpha1.CreatedByKey: createdByValue,
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+		}
+	}
+	data := map[string][]byte{}
+	for k, v := range secret.Data {
+		data[k] = v
+	}
+	changed := create
+	if len(data[dbUserKey]) == 0 {
+		data[dbUserKey] = []byte(defaultDBUser)
+		changed = true
+	}
+	if len(data[dbPasswordKey]) == 0 {
+		password, err := generatePassword()
+		if err != nil {
+			return err
+		}
+		data[dbPasswordKey] = password
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	secret.Data = data
+	if create {
+		_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+	} else {
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		logger.Error(err)
+		tr.Status.MarkDependencyMissing(fmt.Sprintf("%s secret is missing", name))
+		return err
+	}
+	logger.Infof("Generated the database credentials in secret %s/%s", tr.Spec.TargetNamespace, name)
+	return nil
+}
+
+// reconcileTLSSecret generates a self-signed certificate for the API server
+// if spec.tls asks for it, and renews it when it is about to expire. A
+// certificate provided by hand is used as it is
+func (r *Reconciler) reconcileTLSSecret(ctx context.Context, tr *v1alpha1.TektonResult, now time.Time) (time.Duration, error) {
+	logger := logging.FromContext(ctx)
+	name := tlsSecretName(tr.Spec.TLS)
+	secrets := r.kubeClientSet.CoreV1().Secrets(tr.Spec.TargetNamespace)
+
+	secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	create := apierrors.IsNotFound(err)
+	if err != nil && !create {
+		logger.Error(err)
+		return 0, err
+	}
+	if tr.Spec.TLS == nil || !tr.Spec.TLS.Generate {
+		if create {
+			logger.Error(err)
+			tr.Status.MarkDependencyMissing(fmt.Sprintf("%s secret is missing", name))
+		}
+		return 0, err
+	}
+	if !create && secret.GetLabels()[v1alpha1.CreatedByKey] != createdByValue {
+		return 0, nil
+	}
+
+	var notAfter time.Time
+	if !create {
+		notAfter, err = certificateExpiry(secret.Data[corev1.TLSCertKey])
+	}
+	if create || err != nil || !now.Before(notAfter.Add(-certRenewBefore)) {
+		var cert, key []byte
+		cert, key, notAfter, err = generateCertificate(tr.Spec.TargetNamespace, now)
+		if err != nil {
+			return 0, err
+		}
+		if create {
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: tr.Spec.TargetNamespace,
+					Labels: map[string]string{
+						v1alpha1.CreatedByKey: createdByValue,
+					},
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(tr, tr.GetGroupVersionKind())},
+				},
+				Type: corev1.SecretTypeTLS,
+			}
+		}
+		secret.Data = map[string][]byte{
+			corev1.TLSCertKey:       cert,
+			corev1.TLSPrivateKeyKey: key,
+		}
+		if create {
+			_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+		} else {
+			_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return 0, err
+		}
+		logger.Infof("Generated a self-signed certificate in secret %s/%s", tr.Spec.TargetNamespace, name)
+		common.RecordEvent(ctx, tr, corev1.EventTypeNormal, common.EventCertificateGenerated,
+			"Generated a self-signed certificate in secret %s/%s, valid until %s",
+			tr.Spec.TargetNamespace, name, notAfter.Format(time.RFC3339))
+	}
+	return notAfter.Add(-certRenewBefore).Sub(now), nil
+}
+
+// secretsHash returns the hash of the data of the database and TLS Secrets,
+// the pods of Results are rolled out when it changes
+func (r *Reconciler) secretsHash(ctx context.Context, tr *v1alpha1.TektonResult) (string, error) {
+	data := map[string]map[string][]byte{}
+	for _, name := range []string{dbSecretName(tr.Spec.DB), tlsSecretName(tr.Spec.TLS)} {
+		secret, err := r.kubeClientSet.CoreV1().Secrets(tr.Spec.TargetNamespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("secret %s/%s: %v", tr.Spec.TargetNamespace, name, err)
+		}
+		data[name] = secret.Data
+	}
+	return hash.Compute(data)
+}
+
+// specHash returns the hash of the spec of TektonResult along with the data
+// of its Secrets, so that the installer set is updated when they change
+func (r *Reconciler) specHash(ctx context.Context, tr *v1alpha1.TektonResult) (string, error) {
+	secretsHash, err := r.secretsHash(ctx, tr)
+	if err != nil {
+		return "", err
+	}
+	return hash.Compute(struct {
+		Spec    v1alpha1.TektonResultSpec
+		Secrets string
+	}{tr.Spec, secretsHash})
+}
+
+// generatePassword returns a random password safe to use in a connection URL
+func generatePassword() ([]byte, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return []byte(base64.RawURLEncoding.EncodeToString(b)), nil
+}
+
+// generateCertificate returns a PEM encoded self-signed certificate and
+// private key for the API service in namespace, along with its expiry
+func generateCertificate(namespace string, now time.Time) ([]byte, []byte, time.Time, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+
+	host := fmt.Sprintf("%s.%s.svc.cluster.local", apiService, namespace)
+	notAfter := now.Add(certValidity).UTC().Truncate(time.Second)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames: []string{
+			apiService,
+			fmt.Sprintf("%s.%s", apiService, namespace),
+			fmt.Sprintf("%s.%s.svc", apiService, namespace),
+			host,
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		// the certificate is its own root for the clients to trust
+		IsCA: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}),
+		notAfter, nil
+}
+
+// certificateExpiry returns the expiry of a PEM encoded certificate
+func certificateExpiry(cert []byte) (time.Time, error) {
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return time.Time{}, fmt.Errorf("%s is not PEM encoded", corev1.TLSCertKey)
+	}
+	c, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return c.NotAfter, nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonresult
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/ptr"
+)
+
+func testResult(props v1alpha1.ResultsAPIProperties) *v1alpha1.TektonResult {
+	return &v1alpha1.TektonResult{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ResultResourceName},
+		Spec: v1alpha1.TektonResultSpec{
+			CommonSpec:           v1alpha1.CommonSpec{TargetNamespace: "tekton-pipelines"},
+			ResultsAPIProperties: props,
+		},
+	}
+}
+
+func TestReconcileSecrets(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, time.June, 15, 10, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	kube := fake.NewSimpleClientset()
+	r := &Reconciler{kubeClientSet: kube}
+	tr := testResult(v1alpha1.ResultsAPIProperties{TLS: &v1alpha1.ResultsTLS{Generate: true}})
+	secret := func(name string) *corev1.Secret {
+		s, err := kube.CoreV1().Secrets("tekton-pipelines").Get(ctx, name, metav1.GetOptions{})
+		assert.NilError(t, err)
+		return s
+	}
+
+	// the database credentials and the certificate are generated
+	renewIn, err := r.reconcileSecrets(ctx, tr, now)
+	assert.NilError(t, err)
+	assert.Equal(t, renewIn, 335*day)
+	db := secret(DbSecretName)
+	assert.Equal(t, string(db.Data[dbUserKey]), defaultDBUser)
+	assert.Assert(t, len(db.Data[dbPasswordKey]) >= 32)
+	cert := secret(TlsSecretName)
+	notAfter, err := certificateExpiry(cert.Data[corev1.TLSCertKey])
+	assert.NilError(t, err)
+	assert.Equal(t, notAfter, now.Add(certValidity))
+
+	// nothing changes until the certificate is about to expire
+	renewIn, err = r.reconcileSecrets(ctx, tr, now.Add(day))
+	assert.NilError(t, err)
+	assert.Equal(t, renewIn, 334*day)
+	assert.DeepEqual(t, secret(DbSecretName).Data, db.Data)
+	assert.DeepEqual(t, secret(TlsSecretName).Data, cert.Data)
+
+	// the certificate is renewed a month before it expires
+	_, err = r.reconcileSecrets(ctx, tr, now.Add(340*day))
+	assert.NilError(t, err)
+	assert.Assert(t, string(secret(TlsSecretName).Data[corev1.TLSCertKey]) != string(cert.Data[corev1.TLSCertKey]))
+	assert.DeepEqual(t, secret(DbSecretName).Data, db.Data)
+}
+
+func TestReconcileSecretsProvidedByHand(t *testing.T) {
+	ctx := context.Background()
+	kube := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-tls", Namespace: "tekton-pipelines"},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert")},
+	})
+	r := &Reconciler{kubeClientSet: kube}
+	tr := testResult(v1alpha1.ResultsAPIProperties{
+		DB: &v1alpha1.ResultsDB{
+			Provision:  ptr.Bool(false),
+			Host:       "postgres.example.com",
+			SecretName: "my-db",
+		},
+		TLS: &v1alpha1.ResultsTLS{SecretName: "my-tls", Generate: true},
+	})
+
+	// the secret of an external database is not generated
+	_, err := r.reconcileSecrets(ctx, tr, time.Now())
+	assert.ErrorContains(t, err, `"my-db" not found`)
+	ready := tr.Status.GetCondition(v1alpha1.DependenciesInstalled)
+	assert.Equal(t, ready.Message, "Dependency missing: my-db secret is missing")
+
+	_, err = kube.CoreV1().Secrets("tekton-pipelines").Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-db", Namespace: "tekton-pipelines"},
+	}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+
+	// a certificate created by hand is left alone
+	renewIn, err := r.reconcileSecrets(ctx, tr, time.Now())
+	assert.NilError(t, err)
+	assert.Equal(t, renewIn, time.Duration(0))
+	cert, err := kube.CoreV1().Secrets("tekton-pipelines").Get(ctx, "my-tls", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, string(cert.Data[corev1.TLSCertKey]), "cert")
+}
@@ -19,6 +19,7 @@ package tektonresult
 import (
 	"context"
 	"fmt"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,10 +31,11 @@ import (
 	tektonresultconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektonresult"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
 	"github.com/tektoncd/operator/pkg/reconciler/kubernetes/tektoninstallerset"
-	"github.com/tektoncd/operator/pkg/reconciler/shared/hash"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes"
 	"knative.dev/pkg/apis"
+	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 )
@@ -137,8 +139,10 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tr *v1alpha1.TektonResul
 		return fmt.Errorf(errMsg)
 	}
 
-	// check if the secrets are created
-	if err := r.validateSecretsAreCreated(ctx, tr); err != nil {
+	// create the secrets the operator is in charge of, and check the
+	// others are created
+	renewIn, err := r.reconcileSecrets(ctx, tr, time.Now())
+	if err != nil {
 		return err
 	}
 	tr.Status.MarkDependenciesInstalled()
@@ -211,7 +215,7 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tr *v1alpha1.TektonResul
 		// TektonInstallerSet with computing new hash of TektonResult Spec
 
 		// Hash of TektonResult Spec
-		expectedSpecHash, err := hash.Compute(tr.Spec)
+		expectedSpecHash, err := r.specHash(ctx, tr)
 		if err != nil {
 			return err
 		}
@@ -221,7 +225,8 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tr *v1alpha1.TektonResul
 
 		if lastAppliedHash != expectedSpecHash {
 
-			if err := r.transform(ctx, &r.manifest, tr); err != nil {
+			manifest := r.manifest.Append()
+			if err := r.transform(ctx, &manifest, tr); err != nil {
 				logger.Error("manifest transformation failed:  ", err)
 				return err
 			}
@@ -232,7 +237,7 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tr *v1alpha1.TektonResul
 			installedTIS.SetAnnotations(current)
 
 			// Update the manifests
-			installedTIS.Spec.Manifests = r.manifest.Resources()
+			installedTIS.Spec.Manifests = manifest.Resources()
 
 			if _, err = r.operatorClientSet.OperatorV1alpha1().TektonInstallerSets().
 				Update(ctx, installedTIS, metav1.UpdateOptions{}); err != nil {
@@ -265,6 +270,10 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tr *v1alpha1.TektonResul
 	// Mark InstallerSet Ready
 	tr.Status.MarkInstallerSetReady()
 
+	if renewIn > 0 {
+		// reconcile again to renew the generated certificate
+		return controller.NewRequeueAfter(renewIn)
+	}
 	return nil
 }
 
@@ -275,43 +284,47 @@ func (r *Reconciler) updateTektonResultsStatus(ctx context.Context, tr *v1alpha1
 	return nil
 }
 
-// TektonResults expects secrets to be created before installing
-func (r *Reconciler) validateSecretsAreCreated(ctx context.Context, tr *v1alpha1.TektonResult) error {
-	logger := logging.FromContext(ctx)
-	_, err := r.kubeClientSet.CoreV1().Secrets(tr.Spec.TargetNamespace).Get(ctx, DbSecretName, metav1.GetOptions{})
+// transform mutates the passed manifest to one with common, component
+// and platform transformations applied
+func (r *Reconciler) transform(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
+	instance := comp.(*v1alpha1.TektonResult)
+	targetNs := comp.GetSpec().GetTargetNamespace()
+	secretsHash, err := r.secretsHash(ctx, instance)
 	if err != nil {
-		if apierrors.IsNotFound(err) {
-			logger.Error(err)
-			tr.Status.MarkDependencyMissing(fmt.Sprintf("%s secret is missing", DbSecretName))
-			return err
-		}
-		logger.Error(err)
 		return err
 	}
-	_, err = r.kubeClientSet.CoreV1().Secrets(tr.Spec.TargetNamespace).Get(ctx, TlsSecretName, metav1.GetOptions{})
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			logger.Error(err)
-			tr.Status.MarkDependencyMissing(fmt.Sprintf("%s secret is missing", TlsSecretName))
+
+	if instance.Spec.Retention != nil {
+		cronJob, err := retentionCronJob(instance, postgresImage(*manifest))
+		if err != nil {
 			return err
 		}
-		logger.Error(err)
-		return err
+		retention, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{*cronJob}))
+		if err != nil {
+			return err
+		}
+		*manifest = manifest.Append(retention)
+	}
+	if !instance.Spec.DB.IsProvisioned() {
+		*manifest = manifest.Filter(mf.Not(isProvisionedDB))
 	}
-	return nil
-}
 
-// transform mutates the passed manifest to one with common, component
-// and platform transformations applied
-func (r *Reconciler) transform(ctx context.Context, manifest *mf.Manifest, comp v1alpha1.TektonComponent) error {
-	instance := comp.(*v1alpha1.TektonResult)
-	targetNs := comp.GetSpec().GetTargetNamespace()
 	extra := []mf.Transformer{
 		common.InjectOperandNameLabelOverwriteExisting(v1alpha1.OperandTektoncdPipeline),
 		common.ApplyProxySettings,
 		common.ReplaceNamespaceInDeploymentArgs(targetNs),
 		common.ReplaceNamespaceInDeploymentEnv(targetNs),
+		replaceSecretNames(map[string]string{
+			DbSecretName:  dbSecretName(instance.Spec.DB),
+			TlsSecretName: tlsSecretName(instance.Spec.TLS),
+		}),
+		updateAPIServer(instance.Spec.ResultsAPIProperties, targetNs, secretsHash),
+		updateWatcher(secretsHash),
+		updatePostgres(instance.Spec.DB),
+		common.AddConfiguration(instance.Spec.Config),
+		addWorkloadConfiguration(instance.Spec.Config, instance.Spec.Workloads),
 	}
 	extra = append(extra, r.extension.Transformers(instance)...)
+	extra = append(extra, common.AddOptions(instance.Spec.Options))
 	return common.Transform(ctx, manifest, instance, extra...)
 }
@@ -0,0 +1,438 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonresult
+
+import (
+	"fmt"
+	"strconv"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	apiDeployment       = "tekton-results-api"
+	watcherDeployment   = "tekton-results-watcher"
+	postgresStatefulSet = "tekton-results-postgres"
+	postgresService     = "tekton-results-postgres-service"
+	postgresConfigMap   = "tekton-results-postgres"
+
+	defaultDBPort        = 5432
+	defaultDBName        = v1alpha1.ResultsDefaultDBName
+	defaultDBSSLMode     = "disable"
+	defaultLogsPath      = "/logs"
+	defaultPostgresImage = "postgres:13"
+
+	logsVolume = "logs"
+
+	// RetentionCronJob deletes the results older than spec.retention
+	RetentionCronJob  = "tekton-results-retention"
+	retentionSchedule = "@daily"
+)
+
+// isProvisionedDB matches the resources of the in-cluster database
+var isProvisionedDB = mf.Any(
+	mf.All(mf.ByKind("StatefulSet"), mf.ByName(postgresStatefulSet)),
+	mf.All(mf.ByKind("Service"), mf.ByName(postgresService)),
+	mf.All(mf.ByKind("ConfigMap"), mf.ByName(postgresConfigMap)),
+)
+
+// dbAddress returns the host and port of the database of Results
+func dbAddress(db *v1alpha1.ResultsDB, targetNamespace string) (string, int32) {
+	port := int32(defaultDBPort)
+	if !db.IsProvisioned() {
+		if db.Port != nil {
+			port = *db.Port
+		}
+		return db.Host, port
+	}
+	return fmt.Sprintf("%s.%s.svc.cluster.local", postgresService, targetNamespace), port
+}
+
+func dbSSLMode(db *v1alpha1.ResultsDB) string {
+	if db == nil || db.SSLMode == "" {
+		return defaultDBSSLMode
+	}
+	return db.SSLMode
+}
+
+// updateAPIServer passes the database, logs and server settings of spec to
+// the API server as env vars, along with the volume of the logs
+func updateAPIServer(spec v1alpha1.ResultsAPIProperties, targetNamespace, secretsHash string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Deployment" || u.GetName() != apiDeployment {
+			return nil
+		}
+
+		d := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, d); err != nil {
+			return err
+		}
+
+		env := []corev1.EnvVar{}
+		setEnv := func(name, value string) {
+			if value != "" {
+				env = append(env, corev1.EnvVar{Name: name, Value: value})
+			}
+		}
+		if !spec.DB.IsProvisioned() {
+			host, port := dbAddress(spec.DB, targetNamespace)
+			setEnv("DB_ADDR", fmt.Sprintf("%s:%d", host, port))
+		}
+		if db := spec.DB; db != nil {
+			setEnv("DB_NAME", db.Name)
+			setEnv("DB_SSLMODE", db.SSLMode)
+		}
+		setEnv("LOG_LEVEL", spec.LogLevel)
+		if spec.AuthDisable {
+			setEnv("NO_AUTH", "true")
+		}
+
+		var envFrom []corev1.EnvFromSource
+		var volumes []corev1.Volume
+		var mounts []corev1.VolumeMount
+		if logs := spec.Logs; logs != nil {
+			setEnv("LOGS_API", strconv.FormatBool(logs.Enabled))
+			if logs.BufferSize != nil {
+				setEnv("LOGS_BUFFER_SIZE", strconv.FormatInt(*logs.BufferSize, 10))
+			}
+			switch {
+			case !logs.Enabled:
+			case logs.Type == v1alpha1.ResultsLogsTypeS3:
+				setEnv("LOGS_TYPE", v1alpha1.ResultsLogsTypeS3)
+				envFrom = append(envFrom, corev1.EnvFromSource{
+					SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: logs.S3SecretName}},
+				})
+			default:
+				path := logs.Path
+				if path == "" {
+					path = defaultLogsPath
+				}
+				setEnv("LOGS_TYPE", v1alpha1.ResultsLogsTypeFile)
+				setEnv("LOGS_PATH", path)
+				volumes = append(volumes, corev1.Volume{
+					Name: logsVolume,
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: logs.PVCName},
+					},
+				})
+				mounts = append(mounts, corev1.VolumeMount{Name: logsVolume, MountPath: path})
+			}
+		}
+
+		podSpec := &d.Spec.Template.Spec
+		for _, v := range volumes {
+			podSpec.Volumes = append(removeVolume(podSpec.Volumes, v.Name), v)
+		}
+		for i := range podSpec.Containers {
+			c := &podSpec.Containers[i]
+			for _, e := range env {
+				c.Env = append(removeEnv(c.Env, e.Name), e)
+			}
+			c.EnvFrom = append(c.EnvFrom, envFrom...)
+			for _, m := range mounts {
+				c.VolumeMounts = append(removeVolumeMount(c.VolumeMounts, m.Name), m)
+			}
+		}
+		setSecretsHash(&d.Spec.Template, secretsHash)
+
+		unstrObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(d)
+		if err != nil {
+			return err
+		}
+		u.SetUnstructuredContent(unstrObj)
+		return nil
+	}
+}
+
+// updateWatcher rolls out the watcher when the certificate it trusts changes
+func updateWatcher(secretsHash string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Deployment" || u.GetName() != watcherDeployment {
+			return nil
+		}
+
+		d := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, d); err != nil {
+			return err
+		}
+		setSecretsHash(&d.Spec.Template, secretsHash)
+
+		unstrObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(d)
+		if err != nil {
+			return err
+		}
+		u.SetUnstructuredContent(unstrObj)
+		return nil
+	}
+}
+
+// updatePostgres creates the database named in spec.db in the provisioned
+// Postgres
+func updatePostgres(db *v1alpha1.ResultsDB) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if db == nil || db.Name == "" || u.GetKind() != "StatefulSet" || u.GetName() != postgresStatefulSet {
+			return nil
+		}
+
+		ss := &appsv1.StatefulSet{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, ss); err != nil {
+			return err
+		}
+		for i := range ss.Spec.Template.Spec.Containers {
+			c := &ss.Spec.Template.Spec.Containers[i]
+			c.Env = append(removeEnv(c.Env, "POSTGRES_DB"), corev1.EnvVar{Name: "POSTGRES_DB", Value: db.Name})
+		}
+
+		unstrObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ss)
+		if err != nil {
+			return err
+		}
+		u.SetUnstructuredContent(unstrObj)
+		return nil
+	}
+}
+
+// replaceSecretNames replaces the names of the Secrets referenced by the
+// workloads of Results with the ones set in the spec
+func replaceSecretNames(names map[string]string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Deployment" && u.GetKind() != "StatefulSet" {
+			return nil
+		}
+		replace := func(name *string) {
+			if n, ok := names[*name]; ok && n != "" {
+				*name = n
+			}
+		}
+
+		templatePath := []string{"spec", "template"}
+		obj, found, err := unstructured.NestedMap(u.Object, templatePath...)
+		if err != nil || !found {
+			return err
+		}
+		template := &corev1.PodTemplateSpec{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, template); err != nil {
+			return err
+		}
+
+		podSpec := &template.Spec
+		for _, v := range podSpec.Volumes {
+			if v.Secret != nil {
+				replace(&v.Secret.SecretName)
+			}
+		}
+		for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+			for _, c := range containers {
+				for _, e := range c.Env {
+					if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+						replace(&e.ValueFrom.SecretKeyRef.Name)
+					}
+				}
+				for _, e := range c.EnvFrom {
+					if e.SecretRef != nil {
+						replace(&e.SecretRef.Name)
+					}
+				}
+			}
+		}
+
+		obj, err = runtime.DefaultUnstructuredConverter.ToUnstructured(template)
+		if err != nil {
+			return err
+		}
+		return unstructured.SetNestedMap(u.Object, obj, templatePath...)
+	}
+}
+
+// addWorkloadConfiguration sets spec.config on the workloads of Results, with
+// the fields overridden for each of them in spec.workloads. The Deployments
+// are also configured by common.AddConfiguration, Postgres and the retention
+// CronJob are not
+func addWorkloadConfiguration(config v1alpha1.Config, workloads v1alpha1.ResultsWorkloads) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		var override *v1alpha1.ResultsWorkload
+		templatePath := []string{"spec", "template"}
+		switch {
+		case u.GetKind() == "Deployment" && u.GetName() == apiDeployment:
+			override = workloads.API
+		case u.GetKind() == "Deployment" && u.GetName() == watcherDeployment:
+			override = workloads.Watcher
+		case u.GetKind() == "StatefulSet" && u.GetName() == postgresStatefulSet:
+			override = workloads.Postgres
+		case u.GetKind() == "CronJob" && u.GetName() == RetentionCronJob:
+			templatePath = []string{"spec", "jobTemplate", "spec", "template"}
+		default:
+			return nil
+		}
+
+		obj, found, err := unstructured.NestedMap(u.Object, templatePath...)
+		if err != nil || !found {
+			return err
+		}
+		template := &corev1.PodTemplateSpec{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, template); err != nil {
+			return err
+		}
+
+		c := config
+		if override != nil {
+			if len(override.NodeSelector) != 0 {
+				c.NodeSelector = override.NodeSelector
+			}
+			if len(override.Tolerations) != 0 {
+				c.Tolerations = override.Tolerations
+			}
+			if override.PriorityClassName != "" {
+				c.PriorityClassName = override.PriorityClassName
+			}
+		}
+		podSpec := &template.Spec
+		podSpec.NodeSelector = c.NodeSelector
+		podSpec.Tolerations = c.Tolerations
+		podSpec.PriorityClassName = c.PriorityClassName
+		if override != nil && override.Resources != nil {
+			for i := range podSpec.Containers {
+				podSpec.Containers[i].Resources = *override.Resources
+			}
+		}
+
+		obj, err = runtime.DefaultUnstructuredConverter.ToUnstructured(template)
+		if err != nil {
+			return err
+		}
+		return unstructured.SetNestedMap(u.Object, obj, templatePath...)
+	}
+}
+
+// retentionCronJob returns the CronJob deleting daily the results which were
+// not updated for spec.retention, with the psql client of image
+func retentionCronJob(tr *v1alpha1.TektonResult, image string) (*unstructured.Unstructured, error) {
+	db := tr.Spec.DB
+	host, port := dbAddress(db, tr.Spec.TargetNamespace)
+	secret := dbSecretName(db)
+	secretEnv := func(name, key string) corev1.EnvVar {
+		return corev1.EnvVar{Name: name, ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+				Key:                  key,
+			},
+		}}
+	}
+	// the records of the results are deleted along with them
+	query := fmt.Sprintf("DELETE FROM results WHERE updated_time < NOW() - INTERVAL '%d seconds'",
+		int64(tr.Spec.Retention.Duration.Seconds()))
+
+	cj := &batchv1.CronJob{
+		TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RetentionCronJob,
+			Namespace: tr.Spec.TargetNamespace,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          retentionSchedule,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyOnFailure,
+							Containers: []corev1.Container{{
+								Name:    "retention",
+								Image:   image,
+								Command: []string{"psql", "-c", query},
+								Env: []corev1.EnvVar{
+									{Name: "PGHOST", Value: host},
+									{Name: "PGPORT", Value: strconv.Itoa(int(port))},
+									{Name: "PGDATABASE", Value: db.DatabaseName()},
+									{Name: "PGSSLMODE", Value: dbSSLMode(db)},
+									secretEnv("PGUSER", dbUserKey),
+									secretEnv("PGPASSWORD", dbPasswordKey),
+								},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cj)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: obj}, nil
+}
+
+// postgresImage returns the image of the provisioned Postgres in manifest,
+// its client runs the retention
+func postgresImage(manifest mf.Manifest) string {
+	for _, u := range manifest.Filter(mf.ByKind("StatefulSet"), mf.ByName(postgresStatefulSet)).Resources() {
+		containers, _, _ := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "containers")
+		for _, c := range containers {
+			if image, ok := c.(map[string]interface{})["image"].(string); ok && image != "" {
+				return image
+			}
+		}
+	}
+	return defaultPostgresImage
+}
+
+func setSecretsHash(template *corev1.PodTemplateSpec, secretsHash string) {
+	if secretsHash == "" {
+		return
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[v1alpha1.SecretsHashKey] = secretsHash
+}
+
+func removeEnv(env []corev1.EnvVar, name string) []corev1.EnvVar {
+	res := env[:0]
+	for _, e := range env {
+		if e.Name != name {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+func removeVolume(volumes []corev1.Volume, name string) []corev1.Volume {
+	res := volumes[:0]
+	for _, v := range volumes {
+		if v.Name != name {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func removeVolumeMount(mounts []corev1.VolumeMount, name string) []corev1.VolumeMount {
+	res := mounts[:0]
+	for _, m := range mounts {
+		if m.Name != name {
+			res = append(res, m)
+		}
+	}
+	return res
+}
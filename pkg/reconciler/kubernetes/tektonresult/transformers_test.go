@@ -0,0 +1,217 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonresult
+
+import (
+	"testing"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/ptr"
+)
+
+func apiServer(t *testing.T) *unstructured.Unstructured {
+	t.Helper()
+	secretEnv := func(name, key string) corev1.EnvVar {
+		return corev1.EnvVar{Name: name, ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: DbSecretName},
+				Key:                  key,
+			},
+		}}
+	}
+	d := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: apiDeployment, Namespace: "tekton-pipelines"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{{
+						Name:         "tls",
+						VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: TlsSecretName}},
+					}},
+					Containers: []corev1.Container{{
+						Name: "api",
+						Env: []corev1.EnvVar{
+							secretEnv("DB_USER", dbUserKey),
+							secretEnv("DB_PASSWORD", dbPasswordKey),
+							{Name: "DB_ADDR", Value: "tekton-results-postgres-service.tekton-pipelines.svc.cluster.local:5432"},
+							{Name: "DB_NAME", Value: defaultDBName},
+						},
+					}},
+				},
+			},
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(d)
+	assert.NilError(t, err)
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func TestUpdateAPIServer(t *testing.T) {
+	props := v1alpha1.ResultsAPIProperties{
+		DB: &v1alpha1.ResultsDB{
+			Host:       "postgres.example.com",
+			Name:       "results",
+			SSLMode:    "require",
+			SecretName: "my-db",
+		},
+		TLS: &v1alpha1.ResultsTLS{SecretName: "my-tls"},
+		Logs: &v1alpha1.ResultsLogs{
+			Enabled: true,
+			PVCName: "logs",
+		},
+		LogLevel: "warn",
+	}
+	u := apiServer(t)
+
+	assert.NilError(t, replaceSecretNames(map[string]string{
+		DbSecretName:  dbSecretName(props.DB),
+		TlsSecretName: tlsSecretName(props.TLS),
+	})(u))
+	assert.NilError(t, updateAPIServer(props, "tekton-pipelines", "abc")(u))
+
+	got := &appsv1.Deployment{}
+	assert.NilError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, got))
+	podSpec := got.Spec.Template.Spec
+	assert.Equal(t, got.Spec.Template.Annotations[v1alpha1.SecretsHashKey], "abc")
+	assert.Equal(t, podSpec.Volumes[0].Secret.SecretName, "my-tls")
+	assert.DeepEqual(t, podSpec.Volumes[1].PersistentVolumeClaim, &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "logs"})
+	assert.DeepEqual(t, podSpec.Containers[0].VolumeMounts, []corev1.VolumeMount{{Name: logsVolume, MountPath: defaultLogsPath}})
+
+	env := podSpec.Containers[0].Env
+	assert.Equal(t, env[0].ValueFrom.SecretKeyRef.Name, "my-db")
+	assert.Equal(t, env[1].ValueFrom.SecretKeyRef.Name, "my-db")
+	assert.DeepEqual(t, env[2:], []corev1.EnvVar{
+		{Name: "DB_ADDR", Value: "postgres.example.com:5432"},
+		{Name: "DB_NAME", Value: "results"},
+		{Name: "DB_SSLMODE", Value: "require"},
+		{Name: "LOG_LEVEL", Value: "warn"},
+		{Name: "LOGS_API", Value: "true"},
+		{Name: "LOGS_TYPE", Value: v1alpha1.ResultsLogsTypeFile},
+		{Name: "LOGS_PATH", Value: defaultLogsPath},
+	})
+}
+
+func TestProvisionedDBFilter(t *testing.T) {
+	object := func(kind, name string) unstructured.Unstructured {
+		u := unstructured.Unstructured{}
+		u.SetKind(kind)
+		u.SetName(name)
+		return u
+	}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{
+		object("StatefulSet", postgresStatefulSet),
+		object("Service", postgresService),
+		object("ConfigMap", postgresConfigMap),
+		object("Deployment", apiDeployment),
+		object("Service", "tekton-results-api-service"),
+	}))
+	assert.NilError(t, err)
+
+	external := manifest.Filter(mf.Not(isProvisionedDB))
+	assert.Equal(t, len(external.Resources()), 2)
+	assert.Equal(t, postgresImage(manifest), defaultPostgresImage)
+}
+
+func TestRetentionCronJob(t *testing.T) {
+	tr := testResult(v1alpha1.ResultsAPIProperties{
+		DB: &v1alpha1.ResultsDB{
+			Host: "postgres.example.com",
+			Port: ptr.Int32(5433),
+		},
+		Retention: &metav1.Duration{Duration: 7 * 24 * time.Hour},
+	})
+
+	u, err := retentionCronJob(tr, "postgres:14")
+	assert.NilError(t, err)
+	cj := &batchv1.CronJob{}
+	assert.NilError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, cj))
+	assert.Equal(t, cj.Name, RetentionCronJob)
+	assert.Equal(t, cj.Spec.Schedule, retentionSchedule)
+
+	c := cj.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, c.Image, "postgres:14")
+	assert.DeepEqual(t, c.Command, []string{"psql", "-c", "DELETE FROM results WHERE updated_time < NOW() - INTERVAL '604800 seconds'"})
+	assert.DeepEqual(t, c.Env[:4], []corev1.EnvVar{
+		{Name: "PGHOST", Value: "postgres.example.com"},
+		{Name: "PGPORT", Value: "5433"},
+		{Name: "PGDATABASE", Value: defaultDBName},
+		{Name: "PGSSLMODE", Value: defaultDBSSLMode},
+	})
+	assert.Equal(t, c.Env[5].ValueFrom.SecretKeyRef.Name, DbSecretName)
+}
+
+func TestAddWorkloadConfiguration(t *testing.T) {
+	config := v1alpha1.Config{
+		NodeSelector:      map[string]string{"node-role.kubernetes.io/infra": ""},
+		PriorityClassName: "tekton",
+	}
+	resources := &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	workloads := v1alpha1.ResultsWorkloads{
+		Postgres: &v1alpha1.ResultsWorkload{
+			Config:    v1alpha1.Config{NodeSelector: map[string]string{"disktype": "ssd"}},
+			Resources: resources,
+		},
+	}
+	transform := addWorkloadConfiguration(config, workloads)
+
+	api := apiServer(t)
+	assert.NilError(t, transform(api))
+	d := &appsv1.Deployment{}
+	assert.NilError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(api.Object, d))
+	assert.DeepEqual(t, d.Spec.Template.Spec.NodeSelector, config.NodeSelector)
+	assert.Equal(t, d.Spec.Template.Spec.PriorityClassName, "tekton")
+
+	ss := &appsv1.StatefulSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+		ObjectMeta: metav1.ObjectMeta{Name: postgresStatefulSet},
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "postgres"}}},
+			},
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ss)
+	assert.NilError(t, err)
+	postgres := &unstructured.Unstructured{Object: obj}
+	assert.NilError(t, transform(postgres))
+	ss = &appsv1.StatefulSet{}
+	assert.NilError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(postgres.Object, ss))
+	assert.DeepEqual(t, ss.Spec.Template.Spec.NodeSelector, map[string]string{"disktype": "ssd"})
+	assert.Equal(t, ss.Spec.Template.Spec.PriorityClassName, "tekton")
+	assert.Assert(t, ss.Spec.Template.Spec.Containers[0].Resources.Requests.Memory().Equal(resource.MustParse("1Gi")))
+
+	tr := testResult(v1alpha1.ResultsAPIProperties{Retention: &metav1.Duration{Duration: time.Hour}})
+	retention, err := retentionCronJob(tr, defaultPostgresImage)
+	assert.NilError(t, err)
+	assert.NilError(t, transform(retention))
+	cj := &batchv1.CronJob{}
+	assert.NilError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(retention.Object, cj))
+	assert.DeepEqual(t, cj.Spec.JobTemplate.Spec.Template.Spec.NodeSelector, config.NodeSelector)
+}
@@ -70,9 +70,11 @@ func NewExtendedController(generator common.ExtensionGenerator) injection.Contro
 		c := &Reconciler{
 			pipelineInformer: tektonPipelineinformer.Get(ctx),
 			installerSetClient: client.NewInstallerSetClient(tisClient, &manifest,
-				operatorVer, triggersVer, v1alpha1.KindTektonTrigger, filterAndTransform(generator(ctx)), metrics),
+				operatorVer, triggersVer, v1alpha1.KindTektonTrigger, filterAndTransform(generator(ctx)), metrics).
+				WithHealthCheck(healthCheck(manifest.Client)),
 			extension:       generator(ctx),
 			manifest:        manifest,
+			ctrl:            ctrl,
 			triggersVersion: triggersVer,
 		}
 		impl := tektonTriggerreconciler.NewImpl(ctx, c)
@@ -43,6 +43,8 @@ type Reconciler struct {
 	manifest mf.Manifest
 	// Platform-specific behavior to affect the transform
 	extension common.Extension
+	// ctrl loads the manifest of version pinned in spec
+	ctrl common.Controller
 	// version of triggers which we are installing
 	triggersVersion string
 }
@@ -55,7 +57,6 @@ var _ tektontriggerreconciler.Interface = (*Reconciler)(nil)
 func (r *Reconciler) ReconcileKind(ctx context.Context, tt *v1alpha1.TektonTrigger) pkgreconciler.Event {
 	logger := logging.FromContext(ctx).With("name", tt.GetName())
 	tt.Status.InitializeConditions()
-	tt.Status.SetVersion(r.triggersVersion)
 
 	if tt.GetName() != v1alpha1.TriggerResourceName {
 		msg := fmt.Sprintf("Resource ignored, Expected Name: %s, Got Name: %s",
@@ -84,6 +85,15 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tt *v1alpha1.TektonTrigg
 	// Pass the object through defaulting
 	tt.SetDefaults(ctx)
 
+	// Install the version pinned in spec if any
+	manifest, triggersVersion, err := r.ctrl.TargetPayload(tt, r.manifest, r.triggersVersion)
+	if err != nil {
+		logger.Error(err)
+		tt.Status.MarkNotReady(err.Error())
+		return nil
+	}
+	installerSetClient := r.installerSetClient.WithManifest(&manifest, triggersVersion)
+
 	if err := r.extension.PreReconcile(ctx, tt); err != nil {
 		tt.Status.MarkPreReconcilerFailed(fmt.Sprintf("PreReconciliation failed: %s", err.Error()))
 		return err
@@ -92,11 +102,16 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tt *v1alpha1.TektonTrigg
 	//Mark PreReconcile Complete
 	tt.Status.MarkPreReconcilerComplete()
 
-	if err := r.installerSetClient.MainSet(ctx, tt); err != nil {
+	if err := installerSetClient.MainSet(ctx, tt); err != nil {
 		logger.Errorf("failed for main set: %v", err)
 		return err
 	}
 
+	// report the version once it is installed
+	if tt.Status.GetCondition(v1alpha1.InstallerSetReady).IsTrue() {
+		tt.Status.SetVersion(triggersVersion)
+	}
+
 	if err := r.extension.PostReconcile(ctx, tt); err != nil {
 		tt.Status.MarkPostReconcilerFailed(fmt.Sprintf("PostReconciliation failed: %s", err.Error()))
 		return err
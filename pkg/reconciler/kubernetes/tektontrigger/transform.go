@@ -47,6 +47,7 @@ func filterAndTransform(extension common.Extension) client.FilterAndTransform {
 			common.ApplyProxySettings,
 			common.DeploymentImages(triggerImages),
 			common.AddConfiguration(trigger.Spec.Config),
+			common.AddOptions(trigger.Spec.Options),
 		}
 		trns = append(trns, extra...)
 		if err := common.Transform(ctx, &filteredManifest, trigger, trns...); err != nil {
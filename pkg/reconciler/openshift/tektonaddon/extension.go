@@ -301,6 +301,7 @@ func getMiscellaneousManifest(ctx context.Context, addon *v1alpha1.TektonAddon,
 	extraTranformers := []mf.Transformer{
 		common.DeploymentImages(images),
 		common.AddConfiguration(addon.Spec.Config),
+		common.AddOptions(addon.Spec.Options),
 	}
 	if err := addonTransform(ctx, &miscellaneousManifest, addon, extraTranformers...); err != nil {
 		return mf.Manifest{}, err
@@ -72,7 +72,8 @@ func createAddon(ctx context.Context, clients op.TektonAddonInterface, config *v
 			Addon: v1alpha1.Addon{
 				Params: config.Spec.Addon.Params,
 			},
-			Config: config.Spec.Config,
+			Config:  config.Spec.Config,
+			Options: config.Spec.Options,
 		},
 	}
 	if _, err := clients.Create(ctx, taCR, metav1.CreateOptions{}); err != nil {
@@ -105,6 +106,11 @@ func updateAddon(ctx context.Context, taCR *v1alpha1.TektonAddon, config *v1alph
 		updated = true
 	}
 
+	if !reflect.DeepEqual(taCR.Spec.Options, config.Spec.Options) {
+		taCR.Spec.Options = config.Spec.Options
+		updated = true
+	}
+
 	if taCR.ObjectMeta.OwnerReferences == nil {
 		ownerRef := *metav1.NewControllerRef(config, config.GroupVersionKind())
 		taCR.ObjectMeta.OwnerReferences = []metav1.OwnerReference{ownerRef}
@@ -6,7 +6,9 @@ import (
 	"log"
 	"strings"
 
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	installer "github.com/tektoncd/operator/pkg/reconciler/shared/tektoninstallerset"
+	filteredinformerfactory "knative.dev/pkg/client/injection/kube/informers/factory/filtered"
 	"knative.dev/pkg/injection"
 	"knative.dev/pkg/injection/sharedmain"
 	"knative.dev/pkg/signals"
@@ -106,7 +108,10 @@ func startMain(p Platform, ctrls ControllerMap) {
 	pParams := p.PlatformParams()
 	cfg := injection.ParseAndGetRESTConfigOrDie()
 	cfg.QPS = 50
-	ctx, _ := injection.EnableInjectionOrDie(signals.NewContext(), cfg)
+	// the resources applied by installer sets are watched through informers
+	// filtered on their label, so that the others are not cached
+	ctx := filteredinformerfactory.WithSelectors(signals.NewContext(), v1alpha1.ManagedByInstallerSetKey)
+	ctx, _ = injection.EnableInjectionOrDie(ctx, cfg)
 	ctx = contextWithPlatformName(ctx, pParams.Name)
 	installer.InitTektonInstallerSetClient(ctx)
 	sharedmain.MainWithConfig(ctx,
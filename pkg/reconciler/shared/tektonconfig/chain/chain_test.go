@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	op "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/client/injection/client/fake"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ts "knative.dev/pkg/reconciler/testing"
+)
+
+func getTektonConfig() *v1alpha1.TektonConfig {
+	return &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: v1alpha1.ConfigResourceName,
+		},
+		Spec: v1alpha1.TektonConfigSpec{
+			Profile: v1alpha1.ProfileAll,
+			CommonSpec: v1alpha1.CommonSpec{
+				TargetNamespace: "tekton-pipelines",
+			},
+			Chain: v1alpha1.ChainConfig{
+				Enable: true,
+				Chain: v1alpha1.Chain{
+					ArtifactsTaskRunFormat: "in-toto",
+				},
+			},
+		},
+	}
+}
+
+func TestEnsureTektonChainExists(t *testing.T) {
+	ctx, _, _ := ts.SetupFakeContextWithCancel(t)
+	c := fake.Get(ctx)
+	tc := GetTektonChainCR(getTektonConfig())
+	util.AssertEqual(t, tc.Spec.ArtifactsTaskRunFormat, "in-toto")
+
+	// first invocation should create instance as it is non-existent and return RECONCILE_AGAIN_ERR
+	_, err := EnsureTektonChainExists(ctx, c.OperatorV1alpha1().TektonChains(), tc)
+	util.AssertEqual(t, err, v1alpha1.RECONCILE_AGAIN_ERR)
+
+	// next invocation should return RECONCILE_AGAIN_ERR as Chain is waiting for installation
+	_, err = EnsureTektonChainExists(ctx, c.OperatorV1alpha1().TektonChains(), tc)
+	util.AssertEqual(t, err, v1alpha1.RECONCILE_AGAIN_ERR)
+
+	// mark the instance ready
+	markChainReady(t, ctx, c.OperatorV1alpha1().TektonChains())
+
+	// next invocation should return nil error as the instance is ready
+	_, err = EnsureTektonChainExists(ctx, c.OperatorV1alpha1().TektonChains(), tc)
+	util.AssertEqual(t, err, nil)
+
+	// test update propagation from tektonConfig
+	tc.Spec.ArtifactsOCIFormat = "simplesigning"
+	_, err = EnsureTektonChainExists(ctx, c.OperatorV1alpha1().TektonChains(), tc)
+	util.AssertEqual(t, err, v1alpha1.RECONCILE_AGAIN_ERR)
+
+	_, err = EnsureTektonChainExists(ctx, c.OperatorV1alpha1().TektonChains(), tc)
+	util.AssertEqual(t, err, nil)
+}
+
+func TestEnsureTektonChainExistsCreatedByHand(t *testing.T) {
+	ctx, _, _ := ts.SetupFakeContextWithCancel(t)
+	c := fake.Get(ctx)
+
+	tc := GetTektonChainCR(getTektonConfig())
+	tc.OwnerReferences = nil
+	util.AssertEqual(t, CreateChain(ctx, c.OperatorV1alpha1().TektonChains(), tc), nil)
+	markChainReady(t, ctx, c.OperatorV1alpha1().TektonChains())
+
+	// a TektonChain not created by TektonConfig is neither adopted nor updated
+	config := GetTektonChainCR(getTektonConfig())
+	config.Spec.ArtifactsOCIFormat = "simplesigning"
+	_, err := EnsureTektonChainExists(ctx, c.OperatorV1alpha1().TektonChains(), config)
+	util.AssertEqual(t, err, nil)
+
+	onCluster, err := GetChain(ctx, c.OperatorV1alpha1().TektonChains(), v1alpha1.ChainResourceName)
+	util.AssertEqual(t, err, nil)
+	util.AssertEqual(t, len(onCluster.OwnerReferences), 0)
+	util.AssertEqual(t, onCluster.Spec.ArtifactsOCIFormat, "")
+}
+
+func TestEnsureTektonChainCRNotExists(t *testing.T) {
+	ctx, _, _ := ts.SetupFakeContextWithCancel(t)
+	c := fake.Get(ctx)
+
+	// when no instance exists, nil error is returned immediately
+	err := EnsureTektonChainCRNotExists(ctx, c.OperatorV1alpha1().TektonChains())
+	util.AssertEqual(t, err, nil)
+
+	// create an instance for testing other cases
+	tc := GetTektonChainCR(getTektonConfig())
+	_, err = EnsureTektonChainExists(ctx, c.OperatorV1alpha1().TektonChains(), tc)
+	util.AssertEqual(t, err, v1alpha1.RECONCILE_AGAIN_ERR)
+
+	// when an instance exists the first invocation should make the delete API call and
+	// return RECONCILE_AGAIN_ERR. So that the deletion can be confirmed in a subsequent invocation
+	err = EnsureTektonChainCRNotExists(ctx, c.OperatorV1alpha1().TektonChains())
+	util.AssertEqual(t, err, v1alpha1.RECONCILE_AGAIN_ERR)
+
+	// when the instance is completely removed from a cluster, the function should return nil error
+	err = EnsureTektonChainCRNotExists(ctx, c.OperatorV1alpha1().TektonChains())
+	util.AssertEqual(t, err, nil)
+}
+
+func TestEnsureTektonChainCRNotExistsCreatedByHand(t *testing.T) {
+	ctx, _, _ := ts.SetupFakeContextWithCancel(t)
+	c := fake.Get(ctx)
+
+	tc := GetTektonChainCR(getTektonConfig())
+	tc.OwnerReferences = nil
+	util.AssertEqual(t, CreateChain(ctx, c.OperatorV1alpha1().TektonChains(), tc), nil)
+
+	// a TektonChain not created by TektonConfig is kept
+	err := EnsureTektonChainCRNotExists(ctx, c.OperatorV1alpha1().TektonChains())
+	util.AssertEqual(t, err, nil)
+	_, err = GetChain(ctx, c.OperatorV1alpha1().TektonChains(), v1alpha1.ChainResourceName)
+	util.AssertEqual(t, err, nil)
+}
+
+func markChainReady(t *testing.T, ctx context.Context, c op.TektonChainInterface) {
+	t.Helper()
+	tc, err := c.Get(ctx, v1alpha1.ChainResourceName, metav1.GetOptions{})
+	util.AssertEqual(t, err, nil)
+	tc.Status.MarkDependenciesInstalled()
+	tc.Status.MarkPreReconcilerComplete()
+	tc.Status.MarkInstallerSetAvailable()
+	tc.Status.MarkInstallerSetReady()
+	tc.Status.MarkPostReconcilerComplete()
+	_, err = c.UpdateStatus(ctx, tc, metav1.UpdateOptions{})
+	util.AssertEqual(t, err, nil)
+}
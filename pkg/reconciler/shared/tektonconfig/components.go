@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonconfig
+
+import (
+	"context"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	clientset "github.com/tektoncd/operator/pkg/client/clientset/versioned"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"github.com/tektoncd/operator/pkg/reconciler/shared/tektonconfig/chain"
+	"github.com/tektoncd/operator/pkg/reconciler/shared/tektonconfig/pipeline"
+	"github.com/tektoncd/operator/pkg/reconciler/shared/tektonconfig/result"
+	"github.com/tektoncd/operator/pkg/reconciler/shared/tektonconfig/trigger"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/logging"
+)
+
+// component is a component whose CR is created by TektonConfig when it is
+// enabled and removed when it is not
+type component struct {
+	// name is the name of the component in spec.components
+	name string
+	// kind is the kind of the CR of the component
+	kind string
+	// ensure creates or updates the CR and returns nil once it is ready
+	ensure func(context.Context, *v1alpha1.TektonConfig) error
+	// remove deletes the CR and returns nil once it is gone
+	remove func(context.Context) error
+	// get returns the CR
+	get func(context.Context) (v1alpha1.TektonComponent, error)
+}
+
+// components returns the components managed by the shared reconciler in
+// dependency order, the platform specific ones are managed by the extensions
+func components(operatorClientSet clientset.Interface) []component {
+	clients := operatorClientSet.OperatorV1alpha1()
+	return []component{
+		{
+			name: v1alpha1.ComponentPipeline,
+			kind: v1alpha1.KindTektonPipeline,
+			ensure: func(ctx context.Context, tc *v1alpha1.TektonConfig) error {
+				_, err := pipeline.EnsureTektonPipelineExists(ctx, clients.TektonPipelines(), pipeline.GetTektonPipelineCR(tc))
+				return err
+			},
+			remove: func(ctx context.Context) error {
+				return pipeline.EnsureTektonPipelineCRNotExists(ctx, clients.TektonPipelines())
+			},
+			get: func(ctx context.Context) (v1alpha1.TektonComponent, error) {
+				return clients.TektonPipelines().Get(ctx, v1alpha1.PipelineResourceName, metav1.GetOptions{})
+			},
+		},
+		{
+			name: v1alpha1.ComponentTrigger,
+			kind: v1alpha1.KindTektonTrigger,
+			ensure: func(ctx context.Context, tc *v1alpha1.TektonConfig) error {
+				_, err := trigger.EnsureTektonTriggerExists(ctx, clients.TektonTriggers(), trigger.GetTektonTriggerCR(tc))
+				return err
+			},
+			remove: func(ctx context.Context) error {
+				return trigger.EnsureTektonTriggerCRNotExists(ctx, clients.TektonTriggers())
+			},
+			get: func(ctx context.Context) (v1alpha1.TektonComponent, error) {
+				return clients.TektonTriggers().Get(ctx, v1alpha1.TriggerResourceName, metav1.GetOptions{})
+			},
+		},
+		{
+			name: v1alpha1.ComponentChain,
+			kind: v1alpha1.KindTektonChain,
+			ensure: func(ctx context.Context, tc *v1alpha1.TektonConfig) error {
+				_, err := chain.EnsureTektonChainExists(ctx, clients.TektonChains(), chain.GetTektonChainCR(tc))
+				return err
+			},
+			remove: func(ctx context.Context) error {
+				return chain.EnsureTektonChainCRNotExists(ctx, clients.TektonChains())
+			},
+			get: func(ctx context.Context) (v1alpha1.TektonComponent, error) {
+				return clients.TektonChains().Get(ctx, v1alpha1.ChainResourceName, metav1.GetOptions{})
+			},
+		},
+		{
+			name: v1alpha1.ComponentResult,
+			kind: v1alpha1.KindTektonResult,
+			ensure: func(ctx context.Context, tc *v1alpha1.TektonConfig) error {
+				_, err := result.EnsureTektonResultExists(ctx, clients.TektonResults(), result.GetTektonResultCR(tc))
+				return err
+			},
+			remove: func(ctx context.Context) error {
+				return result.EnsureTektonResultCRNotExists(ctx, clients.TektonResults())
+			},
+			get: func(ctx context.Context) (v1alpha1.TektonComponent, error) {
+				return clients.TektonResults().Get(ctx, v1alpha1.ResultResourceName, metav1.GetOptions{})
+			},
+		},
+	}
+}
+
+// observedComponents returns the components reported in status.components,
+// the ones of the shared reconciler followed by the one installed by the
+// extension of the platform
+func observedComponents(operatorClientSet clientset.Interface) []component {
+	clients := operatorClientSet.OperatorV1alpha1()
+	if v1alpha1.IsOpenShiftPlatform() {
+		return append(components(operatorClientSet), component{
+			name: v1alpha1.ComponentAddon,
+			kind: v1alpha1.KindTektonAddon,
+			get: func(ctx context.Context) (v1alpha1.TektonComponent, error) {
+				return clients.TektonAddons().Get(ctx, v1alpha1.AddonResourceName, metav1.GetOptions{})
+			},
+		})
+	}
+	return append(components(operatorClientSet), component{
+		name: v1alpha1.ComponentDashboard,
+		kind: v1alpha1.KindTektonDashboard,
+		get: func(ctx context.Context) (v1alpha1.TektonComponent, error) {
+			return clients.TektonDashboards().Get(ctx, v1alpha1.DashboardResourceName, metav1.GetOptions{})
+		},
+	})
+}
+
+// updateComponentsStatus refreshes status.components from the CRs of the
+// enabled components, which in turn sets ComponentsReady
+func (r *Reconciler) updateComponentsStatus(ctx context.Context, tc *v1alpha1.TektonConfig) {
+	var statuses []v1alpha1.ComponentStatus
+	for _, c := range observedComponents(r.operatorClientSet) {
+		if tc.Spec.ComponentEnabled(c.name) {
+			statuses = append(statuses, r.componentStatus(ctx, c))
+		}
+	}
+	tc.Status.SetComponents(statuses)
+	r.metrics.LogComponents(tc.Status.Components, logging.FromContext(ctx))
+}
+
+func (r *Reconciler) componentStatus(ctx context.Context, c component) v1alpha1.ComponentStatus {
+	now := metav1.Now()
+	status := v1alpha1.ComponentStatus{
+		Name:               c.name,
+		Kind:               c.kind,
+		Ready:              corev1.ConditionUnknown,
+		LastTransitionTime: &now,
+	}
+
+	cr, err := c.get(ctx)
+	if err != nil {
+		status.Reason = "Error"
+		if errors.IsNotFound(err) {
+			status.Reason = "NotFound"
+		}
+		status.Message = err.Error()
+		return status
+	}
+
+	// the addon is released with the operator
+	status.DesiredVersion = r.operatorVersion
+	if c.kind != v1alpha1.KindTektonAddon {
+		status.DesiredVersion = common.DesiredVersion(cr)
+	}
+	status.Version = cr.GetStatus().GetVersion()
+
+	ready := cr.GetStatus().GetCondition(apis.ConditionReady)
+	if ready == nil {
+		status.Reason = "Installing"
+		return status
+	}
+	status.Ready = ready.Status
+	if !ready.LastTransitionTime.Inner.IsZero() {
+		status.LastTransitionTime = ready.LastTransitionTime.Inner.DeepCopy()
+	}
+	if !ready.IsTrue() {
+		status.Reason = ready.Reason
+		status.Message = ready.Message
+	}
+	return status
+}
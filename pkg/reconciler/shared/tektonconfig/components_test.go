@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonconfig
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+func TestUpdateComponentsStatus(t *testing.T) {
+	tp := &v1alpha1.TektonPipeline{ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.PipelineResourceName}}
+	tp.Spec.Version = "0.40.2"
+	tp.Status.InitializeConditions()
+	tp.Status.MarkPreReconcilerComplete()
+	tp.Status.MarkInstallerSetAvailable()
+	tp.Status.MarkInstallerSetReady()
+	tp.Status.MarkPostReconcilerComplete()
+	tp.Status.SetVersion("0.40.2")
+
+	tt := &v1alpha1.TektonTrigger{ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.TriggerResourceName}}
+	tt.Status.InitializeConditions()
+	tt.Status.MarkDependencyMissing("tekton-pipelines does not exist")
+
+	r := &Reconciler{
+		operatorClientSet: fake.NewSimpleClientset(tp, tt),
+		operatorVersion:   "devel",
+	}
+	tc := &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ConfigResourceName},
+		Spec: v1alpha1.TektonConfigSpec{
+			Profile: v1alpha1.ProfileBasic,
+			Chain:   v1alpha1.ChainConfig{Enable: true},
+		},
+	}
+	tc.Status.InitializeConditions()
+
+	r.updateComponentsStatus(context.Background(), tc)
+
+	assert.Equal(t, len(tc.Status.Components), 3)
+	pipeline, trigger, chain := tc.Status.Components[0], tc.Status.Components[1], tc.Status.Components[2]
+	assert.Equal(t, pipeline.Name, v1alpha1.ComponentPipeline)
+	assert.Equal(t, pipeline.Ready, corev1.ConditionTrue)
+	assert.Equal(t, pipeline.DesiredVersion, "0.40.2")
+	assert.Equal(t, pipeline.Version, "0.40.2")
+	assert.Equal(t, pipeline.Reason, "")
+
+	assert.Equal(t, trigger.Kind, v1alpha1.KindTektonTrigger)
+	assert.Equal(t, trigger.Ready, corev1.ConditionFalse)
+	assert.Equal(t, trigger.Reason, "Error")
+
+	assert.Equal(t, chain.Kind, v1alpha1.KindTektonChain)
+	assert.Equal(t, chain.Ready, corev1.ConditionUnknown)
+	assert.Equal(t, chain.Reason, "NotFound")
+
+	ready := tc.Status.GetCondition(apis.ConditionReady)
+	assert.Equal(t, ready.Status, corev1.ConditionFalse)
+	assert.Equal(t, ready.Reason, "ComponentsNotReady")
+	assert.Equal(t, ready.Message, "Components not in ready state: "+
+		"TektonTrigger: Error: "+trigger.Message+"; "+
+		`TektonChain: NotFound: tektonchains.operator.tekton.dev "chain" not found`)
+}
+
+func TestUpdateComponentsStatusStagedUpgrade(t *testing.T) {
+	tp := &v1alpha1.TektonPipeline{ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.PipelineResourceName}}
+	tp.Status.InitializeConditions()
+	tp.Status.MarkPreReconcilerComplete()
+	tp.Status.MarkInstallerSetAvailable()
+	tp.Status.MarkInstallerSetNotReady(v1alpha1.UpgradeWaiting + " at stage webhook, installer set pipeline-main-deployment-x not ready")
+
+	r := &Reconciler{
+		operatorClientSet: fake.NewSimpleClientset(tp),
+		operatorVersion:   "devel",
+	}
+	tc := &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ConfigResourceName},
+		Spec:       v1alpha1.TektonConfigSpec{Profile: v1alpha1.ProfileLite},
+	}
+	tc.Status.InitializeConditions()
+
+	r.updateComponentsStatus(context.Background(), tc)
+
+	// the stage waiting is reported along with the component blocking it
+	ready := tc.Status.GetCondition(apis.ConditionReady)
+	assert.Equal(t, ready.Status, corev1.ConditionFalse)
+	assert.Assert(t, strings.Contains(ready.Message, "TektonPipeline: Error: Installer set not ready: "+
+		v1alpha1.UpgradeWaiting+" at stage webhook, installer set pipeline-main-deployment-x not ready"), ready.Message)
+}
@@ -20,18 +20,24 @@ import (
 	"context"
 	"os"
 	"regexp"
+	"time"
 
 	"github.com/go-logr/zapr"
 	mfc "github.com/manifestival/client-go-client"
 	mf "github.com/manifestival/manifestival"
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	operatorclient "github.com/tektoncd/operator/pkg/client/injection/client"
+	tektonChaininformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonchain"
 	tektonConfiginformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonconfig"
 	tektonInstallerinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektoninstallerset"
 	tektonPipelineinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonpipeline"
+	tektonPrunerPolicyinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonprunerpolicy"
+	tektonResultinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektonresult"
 	tektonTriggerinformer "github.com/tektoncd/operator/pkg/client/injection/informers/operator/v1alpha1/tektontrigger"
 	tektonConfigreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektonconfig"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"github.com/tektoncd/operator/pkg/reconciler/shared/tektonconfig/pruner"
+	pipelineclient "github.com/tektoncd/pipeline/pkg/client/injection/client"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
@@ -65,14 +71,24 @@ func NewExtensibleController(generator common.ExtensionGenerator) injection.Cont
 			logger.Fatal(err)
 		}
 
+		metrics, err := NewRecorder()
+		if err != nil {
+			logger.Errorf("Failed to create TektonConfig metrics recorder %v", err)
+		}
+
 		c := &Reconciler{
 			kubeClientSet:     kubeclient.Get(ctx),
 			operatorClientSet: operatorclient.Get(ctx),
 			extension:         generator(ctx),
 			manifest:          manifest,
 			operatorVersion:   operatorVer,
+			metrics:           metrics,
 		}
 		impl := tektonConfigreconciler.NewImpl(ctx, c)
+		c.pruner = pruner.New(ctx, kubeclient.Get(ctx), pipelineclient.Get(ctx), operatorclient.Get(ctx),
+			tektonPrunerPolicyinformer.Get(ctx).Lister(), func(delay time.Duration) {
+				impl.EnqueueKeyAfter(types.NamespacedName{Namespace: "", Name: v1alpha1.ConfigResourceName}, delay)
+			})
 
 		logger.Info("Setting up event handlers for TektonConfig")
 
@@ -88,6 +104,16 @@ func NewExtensibleController(generator common.ExtensionGenerator) injection.Cont
 			Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 		})
 
+		tektonChaininformer.Get(ctx).Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+			FilterFunc: controller.FilterController(&v1alpha1.TektonConfig{}),
+			Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+		})
+
+		tektonResultinformer.Get(ctx).Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+			FilterFunc: controller.FilterController(&v1alpha1.TektonConfig{}),
+			Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+		})
+
 		tektonInstallerinformer.Get(ctx).Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 			FilterFunc: controller.FilterController(&v1alpha1.TektonConfig{}),
 			Handler:    controller.HandleAll(impl.EnqueueControllerOf),
@@ -95,6 +121,12 @@ func NewExtensibleController(generator common.ExtensionGenerator) injection.Cont
 
 		namespaceinformer.Get(ctx).Informer().AddEventHandler(controller.HandleAll(enqueueCustomName(impl, v1alpha1.ConfigResourceName)))
 
+		// policies of any namespace are reconciled along with TektonConfig, even the
+		// ones in ignored namespaces, so that their status tells they are not applied
+		tektonPrunerPolicyinformer.Get(ctx).Informer().AddEventHandler(controller.HandleAll(func(interface{}) {
+			impl.EnqueueKey(types.NamespacedName{Namespace: "", Name: v1alpha1.ConfigResourceName})
+		}))
+
 		if os.Getenv("AUTOINSTALL_COMPONENTS") == "true" {
 			// try to ensure that there is an instance of tektonConfig
 			newTektonConfig(operatorclient.Get(ctx), kubeclient.Get(ctx)).ensureInstance(ctx)
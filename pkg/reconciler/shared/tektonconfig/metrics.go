@@ -0,0 +1,216 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonconfig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/metrics"
+)
+
+var (
+	componentReady = stats.Int64("component_ready",
+		"whether a component installed by TektonConfig is ready (1) or not (0)",
+		stats.UnitDimensionless)
+	componentInfo = stats.Int64("component_info",
+		"installed and desired version of a component installed by TektonConfig",
+		stats.UnitDimensionless)
+	upgradeDuration = stats.Float64("upgrade_duration_seconds",
+		"time taken by the latest upgrade of the operator until TektonConfig was ready",
+		stats.UnitSeconds)
+)
+
+// Recorder holds keys for the TektonConfig metrics
+type Recorder struct {
+	initialized    bool
+	component      tag.Key
+	kind           tag.Key
+	version        tag.Key
+	desiredVersion tag.Key
+
+	// components are the components recorded last, with their versions
+	mu         sync.Mutex
+	components []v1alpha1.ComponentStatus
+}
+
+// NewRecorder creates a new metrics recorder instance
+// to log the TektonConfig related metrics
+func NewRecorder() (*Recorder, error) {
+	r := &Recorder{
+		initialized: true,
+	}
+
+	keys := map[string]*tag.Key{
+		"component":       &r.component,
+		"kind":            &r.kind,
+		"version":         &r.version,
+		"desired_version": &r.desiredVersion,
+	}
+	for name, key := range keys {
+		k, err := tag.NewKey(name)
+		if err != nil {
+			return nil, err
+		}
+		*key = k
+	}
+
+	err := view.Register(append(r.componentViews(),
+		&view.View{
+			Description: upgradeDuration.Description(),
+			Measure:     upgradeDuration,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{r.version},
+		},
+	)...)
+
+	if err != nil {
+		r.initialized = false
+		return r, err
+	}
+
+	return r, nil
+}
+
+// componentViews returns the views of the component gauges, named so that
+// they can be unregistered to drop their rows
+func (r *Recorder) componentViews() []*view.View {
+	return []*view.View{
+		{
+			Name:        componentReady.Name(),
+			Description: componentReady.Description(),
+			Measure:     componentReady,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{r.component, r.kind},
+		},
+		{
+			Name:        componentInfo.Name(),
+			Description: componentInfo.Description(),
+			Measure:     componentInfo,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{r.component, r.kind, r.version, r.desiredVersion},
+		},
+	}
+}
+
+// RecordComponents logs the readiness and the versions of the components.
+// The series of the components which are removed or upgraded since the last
+// call are dropped, so that they do not keep reporting their last value.
+func (r *Recorder) RecordComponents(components []v1alpha1.ComponentStatus) error {
+	if r == nil || !r.initialized {
+		return fmt.Errorf(
+			"ignoring the metrics recording for TektonConfig, failed to initialize the metrics recorder")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.components != nil && !sameComponents(r.components, components) {
+		views := r.componentViews()
+		view.Unregister(views...)
+		if err := view.Register(views...); err != nil {
+			r.initialized = false
+			return err
+		}
+	}
+	r.components = components
+
+	for _, c := range components {
+		ctx, err := tag.New(
+			context.Background(),
+			tag.Insert(r.component, c.Name),
+			tag.Insert(r.kind, c.Kind),
+		)
+		if err != nil {
+			return err
+		}
+		ready := int64(0)
+		if c.Ready == corev1.ConditionTrue {
+			ready = 1
+		}
+		metrics.Record(ctx, componentReady.M(ready))
+
+		ctx, err = tag.New(ctx,
+			tag.Insert(r.version, c.Version),
+			tag.Insert(r.desiredVersion, c.DesiredVersion),
+		)
+		if err != nil {
+			return err
+		}
+		metrics.Record(ctx, componentInfo.M(1))
+	}
+	return nil
+}
+
+// sameComponents returns true if both lists have the same components with
+// the same versions
+func sameComponents(old, new []v1alpha1.ComponentStatus) bool {
+	key := func(components []v1alpha1.ComponentStatus) []string {
+		keys := make([]string, 0, len(components))
+		for _, c := range components {
+			keys = append(keys, c.Name+"/"+c.Kind+"/"+c.Version+"/"+c.DesiredVersion)
+		}
+		return keys
+	}
+	return reflect.DeepEqual(key(old), key(new))
+}
+
+// RecordUpgrade logs the time taken by an upgrade to version
+func (r *Recorder) RecordUpgrade(version string, duration time.Duration) error {
+	if r == nil || !r.initialized {
+		return fmt.Errorf(
+			"ignoring the metrics recording for TektonConfig, failed to initialize the metrics recorder")
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.version, version),
+	)
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, upgradeDuration.M(duration.Seconds()))
+	return nil
+}
+
+func (r *Recorder) LogComponents(components []v1alpha1.ComponentStatus, logger *zap.SugaredLogger) {
+	if r == nil {
+		return
+	}
+	if err := r.RecordComponents(components); err != nil {
+		logger.Warnf("%v: Failed to log the metrics : %v", v1alpha1.KindTektonConfig, err)
+	}
+}
+
+func (r *Recorder) LogUpgrade(version string, duration time.Duration, logger *zap.SugaredLogger) {
+	if r == nil {
+		return
+	}
+	if err := r.RecordUpgrade(version, duration); err != nil {
+		logger.Warnf("%v: Failed to log the metrics : %v", v1alpha1.KindTektonConfig, err)
+	}
+}
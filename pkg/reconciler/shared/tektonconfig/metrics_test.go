@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonconfig
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"go.opencensus.io/stats/view"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/metrics"
+)
+
+// gaugeRows returns the rows of a gauge as "tag=value,...: value"
+func gaugeRows(t *testing.T, name string) []string {
+	t.Helper()
+	rows, err := view.RetrieveData(name)
+	assert.NilError(t, err)
+	var res []string
+	for _, row := range rows {
+		s := ""
+		for _, tag := range row.Tags {
+			s += tag.Key.Name() + "=" + tag.Value + ","
+		}
+		data := row.Data.(*view.LastValueData)
+		res = append(res, s+" "+map[bool]string{true: "1", false: "0"}[data.Value == 1])
+	}
+	sort.Strings(res)
+	return res
+}
+
+func TestRecordComponents(t *testing.T) {
+	metrics.InitForTesting()
+	r, err := NewRecorder()
+	assert.NilError(t, err)
+
+	assert.NilError(t, r.RecordComponents([]v1alpha1.ComponentStatus{
+		{Name: "pipeline", Kind: "TektonPipeline", Version: "0.40.2", DesiredVersion: "0.40.2", Ready: corev1.ConditionTrue},
+		{Name: "trigger", Kind: "TektonTrigger", DesiredVersion: "0.21.0", Ready: corev1.ConditionUnknown},
+	}))
+	assert.DeepEqual(t, gaugeRows(t, "component_ready"), []string{
+		"component=pipeline,kind=TektonPipeline, 1",
+		"component=trigger,kind=TektonTrigger, 0",
+	})
+
+	// the trigger is removed and the pipeline upgraded, their series are dropped
+	assert.NilError(t, r.RecordComponents([]v1alpha1.ComponentStatus{
+		{Name: "pipeline", Kind: "TektonPipeline", Version: "0.40.2", DesiredVersion: "0.41.0", Ready: corev1.ConditionFalse},
+	}))
+	assert.DeepEqual(t, gaugeRows(t, "component_ready"), []string{
+		"component=pipeline,kind=TektonPipeline, 0",
+	})
+	assert.DeepEqual(t, gaugeRows(t, "component_info"), []string{
+		"component=pipeline,desired_version=0.41.0,kind=TektonPipeline,version=0.40.2, 1",
+	})
+}
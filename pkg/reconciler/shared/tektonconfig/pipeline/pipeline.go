@@ -76,6 +76,8 @@ func GetTektonPipelineCR(config *v1alpha1.TektonConfig) *v1alpha1.TektonPipeline
 			},
 			Pipeline: config.Spec.Pipeline,
 			Config:   config.Spec.Config,
+			Options:  config.Spec.Options,
+			Upgrade:  config.Spec.Upgrade,
 		},
 	}
 }
@@ -104,6 +106,16 @@ func UpdatePipeline(ctx context.Context, old *v1alpha1.TektonPipeline, new *v1al
 		updated = true
 	}
 
+	if !reflect.DeepEqual(old.Spec.Options, new.Spec.Options) {
+		old.Spec.Options = new.Spec.Options
+		updated = true
+	}
+
+	if !reflect.DeepEqual(old.Spec.Upgrade, new.Spec.Upgrade) {
+		old.Spec.Upgrade = new.Spec.Upgrade
+		updated = true
+	}
+
 	if old.ObjectMeta.OwnerReferences == nil {
 		old.ObjectMeta.OwnerReferences = new.ObjectMeta.OwnerReferences
 		updated = true
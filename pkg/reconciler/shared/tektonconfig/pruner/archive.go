@@ -0,0 +1,301 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// archiveDirEnv overrides the directory of the pvc sink, where a
+	// PersistentVolumeClaim is expected to be mounted in the operator pod
+	archiveDirEnv     = "PRUNER_ARCHIVE_DIR"
+	defaultArchiveDir = "/var/lib/tekton-pruner/archive"
+
+	// resultsRecordAnnotation is set by the Tekton Results watcher once
+	// a run is stored
+	resultsRecordAnnotation = "results.tekton.dev/record"
+
+	s3AccessKeyID     = "AWS_ACCESS_KEY_ID"
+	s3SecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+	s3DefaultRegion   = "us-east-1"
+	s3Timeout         = 30 * time.Second
+)
+
+// archiver archives the runs before they are deleted
+type archiver interface {
+	// archive returns whether the run is archived and may be deleted
+	archive(ctx context.Context, ns string, r run) (bool, error)
+}
+
+// objectStore stores the archived objects under a key
+type objectStore interface {
+	put(ctx context.Context, key string, data []byte) error
+}
+
+// newArchiver returns the archiver of a prune config, or nil if runs are
+// not archived. The credentials of the sink are read from secretNamespace.
+func (p *Pruner) newArchiver(ctx context.Context, secretNamespace string, archive *v1alpha1.PruneArchive) (archiver, error) {
+	if archive == nil {
+		return nil, nil
+	}
+	switch archive.Sink {
+	case v1alpha1.PruneArchiveSinkResults:
+		return resultsArchiver{}, nil
+	case v1alpha1.PruneArchiveSinkPVC:
+		dir := os.Getenv(archiveDirEnv)
+		if dir == "" {
+			dir = defaultArchiveDir
+		}
+		// without a volume the files would be lost with the operator pod
+		mounted, err := p.isMountPoint(dir)
+		if err != nil {
+			return nil, err
+		}
+		if !mounted {
+			return nil, fmt.Errorf("no volume is mounted at %s in the operator pod", dir)
+		}
+		return &storeArchiver{pruner: p, store: dirStore{dir: dir}, logs: archive.Logs}, nil
+	case v1alpha1.PruneArchiveSinkS3:
+		store, err := p.newS3Store(ctx, secretNamespace, archive.S3)
+		if err != nil {
+			return nil, err
+		}
+		return &storeArchiver{pruner: p, store: store, logs: archive.Logs}, nil
+	default:
+		return nil, fmt.Errorf("unsupported archive sink %q", archive.Sink)
+	}
+}
+
+// resultsArchiver leaves the runs to the Tekton Results watcher and only
+// lets the runs it already stored be deleted
+type resultsArchiver struct{}
+
+func (resultsArchiver) archive(_ context.Context, _ string, r run) (bool, error) {
+	_, ok := r.object.GetAnnotations()[resultsRecordAnnotation]
+	return ok, nil
+}
+
+// storeArchiver writes the runs, the TaskRuns of the PipelineRuns and
+// optionally the logs of their steps to an object store. The objects of a
+// run are stored under <namespace>/<pipelineruns|taskruns>/<name>/.
+type storeArchiver struct {
+	pruner *Pruner
+	store  objectStore
+	logs   bool
+}
+
+func (a *storeArchiver) archive(ctx context.Context, ns string, r run) (bool, error) {
+	switch obj := r.object.(type) {
+	case *v1beta1.PipelineRun:
+		prefix := path.Join(ns, "pipelineruns", obj.Name)
+		if err := a.pruner.limiter.Wait(ctx); err != nil {
+			return false, err
+		}
+		trs, err := a.pruner.pipelineClientSet.TektonV1beta1().TaskRuns(ns).List(ctx, metav1.ListOptions{
+			LabelSelector: pipeline.PipelineRunLabelKey + "=" + obj.Name,
+		})
+		if err != nil {
+			return false, err
+		}
+		for i := range trs.Items {
+			if err := a.archiveTaskRun(ctx, path.Join(prefix, "taskruns", trs.Items[i].Name), &trs.Items[i]); err != nil {
+				return false, err
+			}
+		}
+		pr := obj.DeepCopy()
+		pr.TypeMeta = metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: "PipelineRun"}
+		return true, a.putYAML(ctx, path.Join(prefix, "pipelinerun.yaml"), pr)
+	case *v1beta1.TaskRun:
+		return true, a.archiveTaskRun(ctx, path.Join(ns, "taskruns", obj.Name), obj)
+	default:
+		return false, fmt.Errorf("unsupported run %T", r.object)
+	}
+}
+
+func (a *storeArchiver) archiveTaskRun(ctx context.Context, prefix string, tr *v1beta1.TaskRun) error {
+	if a.logs && tr.Status.PodName != "" {
+		for _, step := range tr.Status.Steps {
+			logs, err := a.stepLogs(ctx, tr.Namespace, tr.Status.PodName, step.ContainerName)
+			if err != nil {
+				return err
+			}
+			if logs == nil {
+				continue
+			}
+			if err := a.store.put(ctx, path.Join(prefix, step.Name+".log"), logs); err != nil {
+				return err
+			}
+		}
+	}
+	tr = tr.DeepCopy()
+	tr.TypeMeta = metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: "TaskRun"}
+	return a.putYAML(ctx, path.Join(prefix, "taskrun.yaml"), tr)
+}
+
+// stepLogs returns the logs of a step, or nil once its pod is gone
+func (a *storeArchiver) stepLogs(ctx context.Context, ns, pod, container string) ([]byte, error) {
+	if err := a.pruner.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	logs, err := a.pruner.kubeClientSet.CoreV1().Pods(ns).GetLogs(pod, &corev1.PodLogOptions{Container: container}).DoRaw(ctx)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	return logs, err
+}
+
+func (a *storeArchiver) putYAML(ctx context.Context, key string, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return a.store.put(ctx, key, data)
+}
+
+// isMountPoint returns true if a filesystem is mounted at dir, according
+// to the mount table of the process
+func isMountPoint(dir string) (bool, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return false, err
+	}
+	mountInfo, err := os.ReadFile("/proc/self/mountinfo")
+	if err != nil {
+		return false, err
+	}
+	for _, line := range strings.Split(string(mountInfo), "\n") {
+		// the mount point is the 5th field, with spaces escaped as \040
+		fields := strings.Fields(line)
+		if len(fields) > 4 && strings.ReplaceAll(fields[4], "\\040", " ") == dir {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// dirStore stores objects as files under a directory
+type dirStore struct {
+	dir string
+}
+
+func (s dirStore) put(_ context.Context, key string, data []byte) error {
+	name := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	// written aside first so that a failure never leaves a truncated file
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// s3Store stores objects in an S3 compatible bucket
+type s3Store struct {
+	client      *http.Client
+	endpoint    *url.URL
+	bucket      string
+	prefix      string
+	region      string
+	credentials aws.Credentials
+	now         func() time.Time
+}
+
+func (p *Pruner) newS3Store(ctx context.Context, secretNamespace string, config *v1alpha1.PruneArchiveS3) (*s3Store, error) {
+	if config == nil {
+		return nil, fmt.Errorf("missing s3 sink config")
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := p.kubeClientSet.CoreV1().Secrets(secretNamespace).Get(ctx, config.SecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range []string{s3AccessKeyID, s3SecretAccessKey} {
+		if len(secret.Data[key]) == 0 {
+			return nil, fmt.Errorf("secret %s/%s has no %s", secretNamespace, config.SecretName, key)
+		}
+	}
+	region := config.Region
+	if region == "" {
+		region = s3DefaultRegion
+	}
+	return &s3Store{
+		client:   &http.Client{Timeout: s3Timeout},
+		endpoint: endpoint,
+		bucket:   config.Bucket,
+		prefix:   config.Prefix,
+		region:   region,
+		credentials: aws.Credentials{
+			AccessKeyID:     string(secret.Data[s3AccessKeyID]),
+			SecretAccessKey: string(secret.Data[s3SecretAccessKey]),
+		},
+		now: p.now,
+	}, nil
+}
+
+func (s *s3Store) put(ctx context.Context, key string, data []byte) error {
+	u := *s.endpoint
+	u.Path = "/" + path.Join(strings.Trim(u.Path, "/"), s.bucket, s.prefix, key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	payloadHash := hex.EncodeToString(sum[:])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if err := v4.NewSigner().SignHTTP(ctx, s.credentials, req, payloadHash, "s3", s.region, s.now()); err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("storing %s in bucket %s: %s: %s", key, s.bucket, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func pipelineRunNames(t *testing.T, p *Pruner, ns string) []string {
+	t.Helper()
+	prs, err := p.pipelineClientSet.TektonV1beta1().PipelineRuns(ns).List(context.Background(), metav1.ListOptions{})
+	assert.NilError(t, err)
+	var n []string
+	for _, pr := range prs.Items {
+		n = append(n, pr.Name)
+	}
+	sort.Strings(n)
+	return n
+}
+
+func TestArchivePVC(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(archiveDirEnv, dir)
+	keep := uint(1)
+	child := testTaskRun("ns-one", "pr-2-build", 2*time.Minute, map[string]string{pipeline.PipelineRunLabelKey: "pr-2"})
+	child.Status.PodName = "pr-2-build-pod"
+	child.Status.Steps = []v1beta1.StepState{{Name: "compile", ContainerName: "step-compile"}}
+	p, _, _ := newTestPruner(t, nil, []runtime.Object{
+		testPipelineRun("ns-one", "pr-1", time.Minute),
+		testPipelineRun("ns-one", "pr-2", 2*time.Minute),
+		child,
+	})
+	p.isMountPoint = func(d string) (bool, error) { return d == dir, nil }
+
+	status := p.pruneNamespace(context.Background(), "ns-one", v1alpha1.Prune{
+		Resources: []string{"pipelinerun"},
+		Keep:      &keep,
+		Archive:   &v1alpha1.PruneArchive{Sink: "pvc", Logs: true},
+	}, "")
+	assert.Equal(t, status.Errors, int32(0))
+	assert.DeepEqual(t, status.Deleted, map[string]int32{"pipelinerun": 1})
+	assert.DeepEqual(t, pipelineRunNames(t, p, "ns-one"), []string{"pr-1"})
+
+	pr, err := os.ReadFile(filepath.Join(dir, "ns-one/pipelineruns/pr-2/pipelinerun.yaml"))
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(pr), "kind: PipelineRun\n"))
+	assert.Assert(t, strings.Contains(string(pr), "name: pr-2\n"))
+	_, err = os.Stat(filepath.Join(dir, "ns-one/pipelineruns/pr-2/taskruns/pr-2-build/taskrun.yaml"))
+	assert.NilError(t, err)
+	logs, err := os.ReadFile(filepath.Join(dir, "ns-one/pipelineruns/pr-2/taskruns/pr-2-build/compile.log"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(logs), "fake logs")
+}
+
+func TestArchivePVCNotMounted(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(archiveDirEnv, dir)
+	keep := uint(1)
+	p, _, _ := newTestPruner(t, nil, []runtime.Object{
+		testPipelineRun("ns-one", "pr-1", time.Minute),
+		testPipelineRun("ns-one", "pr-2", 2*time.Minute),
+	})
+
+	// nothing is deleted while no volume is mounted for the archive
+	status := p.pruneNamespace(context.Background(), "ns-one", v1alpha1.Prune{
+		Resources: []string{"pipelinerun"},
+		Keep:      &keep,
+		Archive:   &v1alpha1.PruneArchive{Sink: "pvc"},
+	}, "")
+	assert.Equal(t, status.Errors, int32(1))
+	assert.Equal(t, status.Error, "archive: no volume is mounted at "+dir+" in the operator pod")
+	assert.DeepEqual(t, pipelineRunNames(t, p, "ns-one"), []string{"pr-1", "pr-2"})
+
+	mounted, err := isMountPoint("/")
+	assert.NilError(t, err)
+	assert.Assert(t, mounted)
+}
+
+func TestArchiveS3(t *testing.T) {
+	var mu sync.Mutex
+	stored := map[string]string{}
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failing {
+			http.Error(w, "AccessDenied", http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodPut || !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key-id/") {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		stored[r.URL.Path] = string(body)
+	}))
+	defer server.Close()
+
+	keep := uint(1)
+	config := v1alpha1.Prune{
+		Resources: []string{"taskrun"},
+		Keep:      &keep,
+		Archive: &v1alpha1.PruneArchive{Sink: "s3", S3: &v1alpha1.PruneArchiveS3{
+			Endpoint:   server.URL,
+			Bucket:     "runs",
+			Prefix:     "cluster-a",
+			SecretName: "s3-credentials",
+		}},
+	}
+	p, _, _ := newTestPruner(t,
+		[]runtime.Object{&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "s3-credentials", Namespace: "tekton-pipelines"},
+			Data: map[string][]byte{
+				"AWS_ACCESS_KEY_ID":     []byte("key-id"),
+				"AWS_SECRET_ACCESS_KEY": []byte("secret"),
+			},
+		}},
+		[]runtime.Object{
+			testTaskRun("ns-one", "tr-1", time.Minute, nil),
+			testTaskRun("ns-one", "tr-2", 2*time.Minute, nil),
+			testTaskRun("ns-one", "tr-3", 3*time.Minute, nil),
+		},
+	)
+
+	// nothing is deleted when the bucket refuses the runs
+	failing = true
+	status := p.pruneNamespace(context.Background(), "ns-one", config, "tekton-pipelines")
+	assert.Equal(t, status.Errors, int32(2))
+	assert.Assert(t, strings.Contains(status.Error, "403 Forbidden: AccessDenied"), status.Error)
+	assert.DeepEqual(t, status.Deleted, map[string]int32{"taskrun": 0})
+
+	failing = false
+	status = p.pruneNamespace(context.Background(), "ns-one", config, "tekton-pipelines")
+	assert.Equal(t, status.Errors, int32(0))
+	assert.DeepEqual(t, status.Deleted, map[string]int32{"taskrun": 2})
+	var keys []string
+	for key := range stored {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	assert.DeepEqual(t, keys, []string{
+		"/runs/cluster-a/ns-one/taskruns/tr-2/taskrun.yaml",
+		"/runs/cluster-a/ns-one/taskruns/tr-3/taskrun.yaml",
+	})
+
+	// the secret of TektonConfig is not available in other namespaces
+	status = p.pruneNamespace(context.Background(), "ns-one", config, "ns-one")
+	assert.Equal(t, status.Errors, int32(1))
+	assert.Equal(t, status.Error, `archive: secrets "s3-credentials" not found`)
+}
+
+func TestArchiveResults(t *testing.T) {
+	keep := uint(1)
+	recorded := testPipelineRun("ns-one", "pr-recorded", 3*time.Minute)
+	recorded.Annotations = map[string]string{"results.tekton.dev/record": "ns-one/results/1/records/1"}
+	p, _, _ := newTestPruner(t, nil, []runtime.Object{
+		testPipelineRun("ns-one", "pr-1", time.Minute),
+		testPipelineRun("ns-one", "pr-not-recorded", 2*time.Minute),
+		recorded,
+	})
+
+	status := p.pruneNamespace(context.Background(), "ns-one", v1alpha1.Prune{
+		Resources: []string{"pipelinerun"},
+		Keep:      &keep,
+		Archive:   &v1alpha1.PruneArchive{Sink: "results"},
+	}, "")
+	assert.Equal(t, status.Errors, int32(0))
+	assert.DeepEqual(t, status.Deleted, map[string]int32{"pipelinerun": 1})
+	assert.DeepEqual(t, status.NotArchived, map[string]int32{"pipelinerun": 1})
+	assert.DeepEqual(t, pipelineRunNames(t, p, "ns-one"), []string{"pr-1", "pr-not-recorded"})
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"context"
+	"fmt"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+	"knative.dev/pkg/metrics"
+)
+
+var (
+	prunedCount = stats.Int64("pruner_resources_deleted_count",
+		"number of resources deleted by the pruner",
+		stats.UnitDimensionless)
+	notArchivedCount = stats.Int64("pruner_resources_not_archived_count",
+		"number of expired resources kept by the pruner as they are not archived yet",
+		stats.UnitDimensionless)
+	pruneErrorCount = stats.Int64("pruner_errors_count",
+		"number of failures while pruning resources",
+		stats.UnitDimensionless)
+)
+
+// Recorder holds keys for the pruner metrics
+type Recorder struct {
+	initialized bool
+	namespace   tag.Key
+	resource    tag.Key
+}
+
+// NewRecorder creates a new metrics recorder instance
+// to log the pruner related metrics
+func NewRecorder() (*Recorder, error) {
+	r := &Recorder{
+		initialized: true,
+	}
+
+	namespace, err := tag.NewKey("namespace")
+	if err != nil {
+		return nil, err
+	}
+	r.namespace = namespace
+
+	resource, err := tag.NewKey("resource")
+	if err != nil {
+		return nil, err
+	}
+	r.resource = resource
+
+	err = view.Register(
+		&view.View{
+			Description: prunedCount.Description(),
+			Measure:     prunedCount,
+			Aggregation: view.Sum(),
+			TagKeys:     []tag.Key{r.namespace, r.resource},
+		},
+		&view.View{
+			Description: notArchivedCount.Description(),
+			Measure:     notArchivedCount,
+			Aggregation: view.Sum(),
+			TagKeys:     []tag.Key{r.namespace, r.resource},
+		},
+		&view.View{
+			Description: pruneErrorCount.Description(),
+			Measure:     pruneErrorCount,
+			Aggregation: view.Sum(),
+			TagKeys:     []tag.Key{r.namespace, r.resource},
+		},
+	)
+
+	if err != nil {
+		r.initialized = false
+		return r, err
+	}
+
+	return r, nil
+}
+
+// Record logs the number of resources deleted, the number of resources not
+// archived yet and the number of failures of a pruner run in a namespace
+func (r *Recorder) Record(namespace, resource string, deleted, notArchived, errors int64) error {
+	if r == nil || !r.initialized {
+		return fmt.Errorf(
+			"ignoring the metrics recording for pruner, failed to initialize the metrics recorder")
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.namespace, namespace),
+		tag.Insert(r.resource, resource),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, prunedCount.M(deleted))
+	metrics.Record(ctx, notArchivedCount.M(notArchived))
+	metrics.Record(ctx, pruneErrorCount.M(errors))
+	return nil
+}
+
+func (r *Recorder) LogMetrics(namespace, resource string, deleted, notArchived, errors int64, logger *zap.SugaredLogger) {
+	err := r.Record(namespace, resource, deleted, notArchived, errors)
+	if err != nil {
+		logger.Warnf("pruner: Failed to log the metrics : %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	reasonNamespaceIgnored  = "NamespaceIgnored"
+	reasonSuperseded        = "Superseded"
+	reasonNoSchedule        = "NoSchedule"
+	reasonArchiveNotAllowed = "ArchiveNotAllowed"
+	reasonResultsNotEnabled = "ResultsNotEnabled"
+
+	// policyRetryDelay is the delay after which the status of the
+	// policies is written again when an update failed
+	policyRetryDelay = 30 * time.Second
+)
+
+// inactivePolicy records why a policy does not prune its namespace
+type inactivePolicy struct {
+	reason, message string
+}
+
+// applyPolicies replaces the prune config of the namespaces having a
+// TektonPrunerPolicy with the spec of the policy, which takes precedence
+// over the prune annotations, prune.skip included. A policy without a
+// schedule uses the schedule of TektonConfig. When a namespace has more
+// than one policy, the oldest one is applied. A policy archiving to Tekton
+// Results is not applied unless Results is enabled. It returns the policies
+// which are not applied, keyed by namespace/name.
+func applyPolicies(configs map[string]v1alpha1.Prune, policies []*v1alpha1.TektonPrunerPolicy, defaultSchedule string, resultsEnabled bool) map[string]inactivePolicy {
+	sort.Slice(policies, func(i, j int) bool {
+		ti, tj := policies[i].CreationTimestamp, policies[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return policies[i].Name < policies[j].Name
+	})
+
+	re := regexp.MustCompile(common.NamespaceIgnorePattern)
+	inactive := map[string]inactivePolicy{}
+	applied := map[string]string{}
+	for _, policy := range policies {
+		key := policy.Namespace + "/" + policy.Name
+		if re.MatchString(policy.Namespace) {
+			inactive[key] = inactivePolicy{reasonNamespaceIgnored,
+				fmt.Sprintf("namespace %s is never pruned", policy.Namespace)}
+			continue
+		}
+		if name, ok := applied[policy.Namespace]; ok {
+			inactive[key] = inactivePolicy{reasonSuperseded,
+				fmt.Sprintf("namespace %s is pruned following policy %s", policy.Namespace, name)}
+			continue
+		}
+		// policies created before the webhook denied these sinks
+		if a := policy.Spec.Archive; a != nil && !isPolicyArchiveSink(a.Sink) {
+			inactive[key] = inactivePolicy{reasonArchiveNotAllowed,
+				fmt.Sprintf("the %s archive sink may only be used by the pruner of TektonConfig", a.Sink)}
+			continue
+		}
+		// the runs would never be recorded and so never deleted
+		if a := policy.Spec.Archive; a != nil && a.Sink == v1alpha1.PruneArchiveSinkResults && !resultsEnabled {
+			inactive[key] = inactivePolicy{reasonResultsNotEnabled,
+				"the results archive sink requires Tekton Results, which is not enabled in TektonConfig"}
+			continue
+		}
+		config := *policy.Spec.Prune.DeepCopy()
+		if config.Schedule == "" {
+			config.Schedule = defaultSchedule
+		}
+		if config.Schedule == "" {
+			inactive[key] = inactivePolicy{reasonNoSchedule,
+				"the policy has no schedule and TektonConfig has no pruner schedule"}
+			continue
+		}
+		applied[policy.Namespace] = policy.Name
+		configs[policy.Namespace] = config
+	}
+	return inactive
+}
+
+func isPolicyArchiveSink(sink string) bool {
+	for _, s := range v1alpha1.PolicyArchiveSinks {
+		if s == sink {
+			return true
+		}
+	}
+	return false
+}
+
+// updatePolicies writes the state and the outcome of the latest run of the
+// namespace to the status of the policies. A policy whose status fails to be
+// updated doesn't hold back the others, TektonConfig is queued again to retry.
+func (p *Pruner) updatePolicies(ctx context.Context, policies []*v1alpha1.TektonPrunerPolicy, inactive map[string]inactivePolicy,
+	statuses map[string]v1alpha1.NamespacePruneStatus, next map[string]time.Time) {
+	logger := logging.FromContext(ctx)
+	failed := false
+	for _, policy := range policies {
+		updated := policy.DeepCopy()
+		status := &updated.Status
+		status.InitializeConditions()
+		status.ObservedGeneration = policy.Generation
+
+		if reason, ok := inactive[policy.Namespace+"/"+policy.Name]; ok {
+			status.MarkInactive(reason.reason, reason.message)
+			status.LastPruneTime, status.NextPruneTime = nil, nil
+			status.Deleted, status.NotArchived, status.Errors, status.Error = nil, nil, 0, ""
+		} else {
+			ns := statuses[policy.Namespace]
+			status.MarkActive()
+			status.LastPruneTime = ns.LastPruneTime
+			status.Deleted, status.NotArchived, status.Errors, status.Error = ns.Deleted, ns.NotArchived, ns.Errors, ns.Error
+			// the next run is unknown while the pruner is running
+			if t, ok := next[policy.Namespace]; ok {
+				nextTime := metav1.NewTime(t)
+				status.NextPruneTime = &nextTime
+			}
+		}
+
+		if equality.Semantic.DeepEqual(policy.Status, updated.Status) {
+			continue
+		}
+		_, err := p.operatorClientSet.OperatorV1alpha1().TektonPrunerPolicies(policy.Namespace).
+			UpdateStatus(ctx, updated, metav1.UpdateOptions{})
+		if err != nil {
+			failed = true
+			logger.Errorw("pruner: failed to update the status of policy", "namespace", policy.Namespace,
+				"name", policy.Name, zap.Error(err))
+		}
+	}
+	if failed {
+		p.enqueueAfter(policyRetryDelay)
+	}
+}
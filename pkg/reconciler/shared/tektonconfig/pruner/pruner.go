@@ -0,0 +1,532 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	clientset "github.com/tektoncd/operator/pkg/client/clientset/versioned"
+	listers "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	pipelineclientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/flowcontrol"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	pipelineRun = "pipelinerun"
+	taskRun     = "taskrun"
+
+	// apiQPS and apiBurst rate limit the list and delete calls
+	// made by the pruner to the API server
+	apiQPS   = 10
+	apiBurst = 20
+)
+
+// Pruner deletes completed PipelineRuns and TaskRuns through the API,
+// following the prune config of TektonConfig, the prune annotations of
+// the namespaces and the TektonPrunerPolicies. Runs are started from the
+// TektonConfig reconciler and happen in the background, the outcome is
+// reported in the status of TektonConfig, of the TektonPrunerPolicies and
+// in metrics.
+type Pruner struct {
+	ctx               context.Context
+	kubeClientSet     kubernetes.Interface
+	pipelineClientSet pipelineclientset.Interface
+	operatorClientSet clientset.Interface
+	policyLister      listers.TektonPrunerPolicyLister
+	limiter           flowcontrol.RateLimiter
+	metrics           *Recorder
+	// enqueueAfter queues TektonConfig to be reconciled after a delay
+	enqueueAfter func(time.Duration)
+	now          func() time.Time
+	// isMountPoint checks that a volume is mounted for the pvc sink
+	isMountPoint func(dir string) (bool, error)
+
+	mu      sync.Mutex
+	running bool
+	started time.Time
+	// results holds the outcome of the runs per namespace until it
+	// is written to the status of TektonConfig
+	results map[string]v1alpha1.NamespacePruneStatus
+}
+
+// New returns a Pruner whose runs last as long as ctx
+func New(ctx context.Context, kc kubernetes.Interface, pc pipelineclientset.Interface, oc clientset.Interface,
+	policyLister listers.TektonPrunerPolicyLister, enqueueAfter func(time.Duration)) *Pruner {
+	metrics, err := NewRecorder()
+	if err != nil {
+		logging.FromContext(ctx).Errorf("Failed to create pruner metrics recorder %v", err)
+	}
+	return &Pruner{
+		ctx:               ctx,
+		kubeClientSet:     kc,
+		pipelineClientSet: pc,
+		operatorClientSet: oc,
+		policyLister:      policyLister,
+		limiter:           flowcontrol.NewTokenBucketRateLimiter(apiQPS, apiBurst),
+		metrics:           metrics,
+		enqueueAfter:      enqueueAfter,
+		now:               time.Now,
+		isMountPoint:      isMountPoint,
+		started:           time.Now(),
+		results:           map[string]v1alpha1.NamespacePruneStatus{},
+	}
+}
+
+// Reconcile reports the outcome of the finished runs in the status of
+// TektonConfig and of the TektonPrunerPolicies, and starts a run for the
+// namespaces whose schedule is due. TektonConfig is queued again when the
+// run finishes or, if nothing is due, when the next schedule comes up.
+func (p *Pruner) Reconcile(ctx context.Context, tc *v1alpha1.TektonConfig) error {
+	configs := map[string]v1alpha1.Prune{}
+	if len(tc.Spec.Pruner.Resources) != 0 && tc.Spec.Pruner.Schedule != "" {
+		var err error
+		if configs, err = common.PruneConfigs(ctx, p.kubeClientSet, tc.Spec.Pruner); err != nil {
+			return err
+		}
+	}
+
+	policies, err := p.policyLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	inactive := applyPolicies(configs, policies, tc.Spec.Pruner.Schedule, tc.Spec.ComponentEnabled(v1alpha1.ComponentResult))
+
+	// the secrets of a config are read from the namespace it is defined in,
+	// a policy never gets to use the secrets of TektonConfig
+	secretNamespaces := map[string]string{}
+	for ns := range configs {
+		secretNamespaces[ns] = tc.Spec.TargetNamespace
+	}
+	for _, policy := range policies {
+		if _, ok := inactive[policy.Namespace+"/"+policy.Name]; !ok {
+			secretNamespaces[policy.Namespace] = policy.Namespace
+		}
+	}
+
+	for _, result := range p.unreported(tc.Status.Pruner) {
+		if result.Errors != 0 {
+			common.RecordEvent(ctx, tc, corev1.EventTypeWarning, common.EventPruneFailed,
+				"Pruning namespace %s failed %d times: %s", result.Namespace, result.Errors, result.Error)
+		}
+		var notArchived int32
+		for _, count := range result.NotArchived {
+			notArchived += count
+		}
+		if notArchived != 0 {
+			common.RecordEvent(ctx, tc, corev1.EventTypeWarning, common.EventPruneNotArchived,
+				"Pruning namespace %s kept %d expired runs which are not archived yet", result.Namespace, notArchived)
+		}
+	}
+
+	statuses, next := p.schedule(tc.Status.Pruner, configs, secretNamespaces)
+	tc.Status.Pruner = toPrunerStatus(statuses)
+
+	p.updatePolicies(ctx, policies, inactive, statuses, next)
+	return nil
+}
+
+// schedule merges the outcome of the finished runs with the current status
+// and starts a run for the namespaces whose schedule is due. It returns the
+// status and the time of the next run of every namespace.
+func (p *Pruner) schedule(current *v1alpha1.PrunerStatus, configs map[string]v1alpha1.Prune,
+	secretNamespaces map[string]string) (map[string]v1alpha1.NamespacePruneStatus, map[string]time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	statuses := p.statuses(current, configs)
+	nextRuns := map[string]time.Time{}
+
+	// a running pruner queues TektonConfig once it is done
+	if p.running {
+		return statuses, nextRuns
+	}
+
+	now := p.now()
+	due := map[string]v1alpha1.Prune{}
+	var wait time.Duration
+	for ns, config := range configs {
+		sched, err := parseSchedule(config.Schedule)
+		if err != nil {
+			status := statuses[ns]
+			status.Error = err.Error()
+			statuses[ns] = status
+			continue
+		}
+		last := p.started
+		if status, ok := statuses[ns]; ok && status.LastPruneTime != nil {
+			last = status.LastPruneTime.Time
+		}
+		next := sched.Next(last.UTC())
+		if next.IsZero() {
+			continue
+		}
+		if !next.After(now) {
+			due[ns] = config
+			continue
+		}
+		nextRuns[ns] = next
+		if d := next.Sub(now); wait == 0 || d < wait {
+			wait = d
+		}
+	}
+
+	if len(due) > 0 {
+		p.running = true
+		go p.run(due, secretNamespaces)
+	} else if wait > 0 {
+		p.enqueueAfter(wait)
+	}
+	return statuses, nextRuns
+}
+
+// statuses merges the outcome of the runs with the status of TektonConfig,
+// dropping the namespaces which are not pruned anymore
+func (p *Pruner) statuses(current *v1alpha1.PrunerStatus, configs map[string]v1alpha1.Prune) map[string]v1alpha1.NamespacePruneStatus {
+	statuses := map[string]v1alpha1.NamespacePruneStatus{}
+	if current != nil {
+		for _, status := range current.Namespaces {
+			if _, ok := configs[status.Namespace]; ok {
+				statuses[status.Namespace] = status
+			}
+		}
+	}
+	for ns, status := range p.results {
+		if _, ok := configs[ns]; !ok {
+			delete(p.results, ns)
+			continue
+		}
+		statuses[ns] = status
+	}
+	for ns := range configs {
+		if _, ok := statuses[ns]; !ok {
+			statuses[ns] = v1alpha1.NamespacePruneStatus{Namespace: ns}
+		}
+	}
+	return statuses
+}
+
+// unreported returns the outcome of the runs which failed or kept runs not
+// archived yet and are not in the status of TektonConfig yet, so that each
+// run is reported once
+func (p *Pruner) unreported(current *v1alpha1.PrunerStatus) []v1alpha1.NamespacePruneStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	reported := map[string]*metav1.Time{}
+	if current != nil {
+		for _, status := range current.Namespaces {
+			reported[status.Namespace] = status.LastPruneTime
+		}
+	}
+
+	var unreported []v1alpha1.NamespacePruneStatus
+	for ns, result := range p.results {
+		if result.Errors == 0 && len(result.NotArchived) == 0 {
+			continue
+		}
+		if last := reported[ns]; last != nil && result.LastPruneTime != nil && last.Equal(result.LastPruneTime) {
+			continue
+		}
+		unreported = append(unreported, result)
+	}
+	sort.Slice(unreported, func(i, j int) bool {
+		return unreported[i].Namespace < unreported[j].Namespace
+	})
+	return unreported
+}
+
+func toPrunerStatus(statuses map[string]v1alpha1.NamespacePruneStatus) *v1alpha1.PrunerStatus {
+	if len(statuses) == 0 {
+		return nil
+	}
+	status := &v1alpha1.PrunerStatus{}
+	for _, s := range statuses {
+		status.Namespaces = append(status.Namespaces, s)
+	}
+	sort.Slice(status.Namespaces, func(i, j int) bool {
+		return status.Namespaces[i].Namespace < status.Namespaces[j].Namespace
+	})
+	return status
+}
+
+func (p *Pruner) run(configs map[string]v1alpha1.Prune, secretNamespaces map[string]string) {
+	results := map[string]v1alpha1.NamespacePruneStatus{}
+	for ns, config := range configs {
+		results[ns] = p.pruneNamespace(p.ctx, ns, config, secretNamespaces[ns])
+	}
+
+	p.mu.Lock()
+	for ns, result := range results {
+		p.results[ns] = result
+	}
+	p.running = false
+	p.mu.Unlock()
+
+	p.enqueueAfter(0)
+}
+
+func (p *Pruner) pruneNamespace(ctx context.Context, ns string, config v1alpha1.Prune, secretNamespace string) v1alpha1.NamespacePruneStatus {
+	logger := logging.FromContext(ctx)
+	status := v1alpha1.NamespacePruneStatus{
+		Namespace: ns,
+		Deleted:   map[string]int32{},
+	}
+	// the time is set before any return, the next run is scheduled from it
+	// the API keeps seconds, a finer time would never match the status read back
+	status.LastPruneTime = &metav1.Time{Time: p.now().Truncate(time.Second)}
+
+	archiver, err := p.newArchiver(ctx, secretNamespace, config.Archive)
+	if err != nil {
+		// nothing is deleted when runs cannot be archived
+		status.Errors++
+		status.Error = fmt.Sprintf("archive: %v", err)
+		logger.Errorw("pruner: failed to set up the archive", "namespace", ns, zap.Error(err))
+		return status
+	}
+
+	for _, resource := range config.Resources {
+		deleted, notArchived, failed, err := p.pruneResource(ctx, ns, resource, config, archiver)
+		if err != nil {
+			status.Error = fmt.Sprintf("%s: %v", resource, err)
+			logger.Errorw("pruner: failed to prune resources", "namespace", ns, "resource", resource, zap.Error(err))
+		}
+		status.Deleted[resource] += int32(deleted)
+		if notArchived != 0 {
+			if status.NotArchived == nil {
+				status.NotArchived = map[string]int32{}
+			}
+			status.NotArchived[resource] += int32(notArchived)
+		}
+		status.Errors += int32(failed)
+		p.metrics.LogMetrics(ns, resource, int64(deleted), int64(notArchived), int64(failed), logger)
+	}
+	return status
+}
+
+// run is a completed PipelineRun or TaskRun
+type run struct {
+	name           string
+	completionTime time.Time
+	// status is succeeded, failed or cancelled
+	status string
+	// group is the name of the Pipeline or Task of the run
+	group string
+	// object is the PipelineRun or TaskRun, which is archived
+	object metav1.Object
+}
+
+func newRun(object metav1.Object, completionTime *metav1.Time, condition *apis.Condition, cancelledReason, group string) run {
+	r := run{
+		name:           object.GetName(),
+		completionTime: object.GetCreationTimestamp().Time,
+		status:         v1alpha1.PruneStatusFailed,
+		group:          group,
+		object:         object,
+	}
+	if completionTime != nil {
+		r.completionTime = completionTime.Time
+	}
+	if condition.IsTrue() {
+		r.status = v1alpha1.PruneStatusSucceeded
+	} else if condition.Reason == cancelledReason {
+		r.status = v1alpha1.PruneStatusCancelled
+	}
+	return r
+}
+
+func newPipelineRun(pr *v1beta1.PipelineRun) run {
+	group := pr.Labels[pipeline.PipelineLabelKey]
+	if group == "" && pr.Spec.PipelineRef != nil {
+		group = pr.Spec.PipelineRef.Name
+	}
+	return newRun(pr, pr.Status.CompletionTime, pr.Status.GetCondition(apis.ConditionSucceeded),
+		v1beta1.PipelineRunReasonCancelled.String(), group)
+}
+
+func newTaskRun(tr *v1beta1.TaskRun) run {
+	group := tr.Labels[pipeline.TaskLabelKey]
+	if group == "" && tr.Spec.TaskRef != nil {
+		group = tr.Spec.TaskRef.Name
+	}
+	return newRun(tr, tr.Status.CompletionTime, tr.Status.GetCondition(apis.ConditionSucceeded),
+		v1beta1.TaskRunReasonCancelled.String(), group)
+}
+
+// pruneResource deletes the expired runs of a resource in a namespace, once
+// archived when an archiver is given, and returns the number of deleted
+// runs, the number of runs kept as they are not archived yet, the number of
+// failures and the last failure
+func (p *Pruner) pruneResource(ctx context.Context, ns, resource string, config v1alpha1.Prune, archiver archiver) (int, int, int, error) {
+	if err := p.limiter.Wait(ctx); err != nil {
+		return 0, 0, 1, err
+	}
+
+	selector := labels.Everything()
+	if config.Selector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(config.Selector); err != nil {
+			return 0, 0, 1, err
+		}
+	}
+
+	var runs []run
+	var deleteRun func(context.Context, string, metav1.DeleteOptions) error
+	switch resource {
+	case pipelineRun:
+		prs, err := p.pipelineClientSet.TektonV1beta1().PipelineRuns(ns).List(ctx, metav1.ListOptions{
+			LabelSelector: selector.String(),
+		})
+		if err != nil {
+			return 0, 0, 1, err
+		}
+		for i := range prs.Items {
+			if prs.Items[i].IsDone() {
+				runs = append(runs, newPipelineRun(&prs.Items[i]))
+			}
+		}
+		deleteRun = p.pipelineClientSet.TektonV1beta1().PipelineRuns(ns).Delete
+	case taskRun:
+		// TaskRuns of a PipelineRun are deleted along with their PipelineRun
+		notInPipelineRun, err := labels.NewRequirement(pipeline.PipelineRunLabelKey, selection.DoesNotExist, nil)
+		if err != nil {
+			return 0, 0, 1, err
+		}
+		trs, err := p.pipelineClientSet.TektonV1beta1().TaskRuns(ns).List(ctx, metav1.ListOptions{
+			LabelSelector: selector.Add(*notInPipelineRun).String(),
+		})
+		if err != nil {
+			return 0, 0, 1, err
+		}
+		for i := range trs.Items {
+			if trs.Items[i].IsDone() {
+				runs = append(runs, newTaskRun(&trs.Items[i]))
+			}
+		}
+		deleteRun = p.pipelineClientSet.TektonV1beta1().TaskRuns(ns).Delete
+	default:
+		return 0, 0, 1, fmt.Errorf("unsupported resource %q", resource)
+	}
+
+	var deleted, notArchived, failed int
+	var lastErr error
+	propagation := metav1.DeletePropagationBackground
+	byName := map[string]run{}
+	for _, r := range runs {
+		byName[r.name] = r
+	}
+	for _, name := range expired(runs, config, p.now()) {
+		if archiver != nil {
+			archived, err := archiver.archive(ctx, ns, byName[name])
+			if err != nil {
+				failed++
+				lastErr = fmt.Errorf("archiving %s: %w", name, err)
+				continue
+			}
+			// kept until it is archived
+			if !archived {
+				notArchived++
+				continue
+			}
+		}
+		if err := p.limiter.Wait(ctx); err != nil {
+			return deleted, notArchived, failed + 1, err
+		}
+		err := deleteRun(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if err != nil && !errors.IsNotFound(err) {
+			failed++
+			lastErr = err
+			continue
+		}
+		deleted++
+	}
+	return deleted, notArchived, failed, lastErr
+}
+
+// expired returns the names of the runs to delete. Runs are counted
+// separately for each status with its own retention and, when grouped by
+// name, for each Pipeline or Task.
+func expired(runs []run, config v1alpha1.Prune, now time.Time) []string {
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].completionTime.After(runs[j].completionTime)
+	})
+
+	var keys []string
+	groups := map[string][]run{}
+	retentions := map[string]v1alpha1.PruneRetention{}
+	for _, r := range runs {
+		if !config.Prunes(r.status) {
+			continue
+		}
+		retention, own := config.Retention(r.status)
+		key := ""
+		if own {
+			key = r.status
+		}
+		if config.GroupBy == v1alpha1.PruneGroupByName {
+			key += "/" + r.group
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+			retentions[key] = retention
+		}
+		groups[key] = append(groups[key], r)
+	}
+
+	var names []string
+	for _, key := range keys {
+		names = append(names, expiredWithin(groups[key], retentions[key], now)...)
+	}
+	return names
+}
+
+// expiredWithin returns the names of the runs beyond the newest `keep`
+// ones or, without `keep`, the runs completed more than `keep-since`
+// minutes ago
+func expiredWithin(runs []run, retention v1alpha1.PruneRetention, now time.Time) []string {
+	var names []string
+	switch {
+	case retention.Keep != nil:
+		for i := int(*retention.Keep); i < len(runs); i++ {
+			names = append(names, runs[i].name)
+		}
+	case retention.KeepSince != nil:
+		cutoff := now.Add(-time.Duration(*retention.KeepSince) * time.Minute)
+		for _, r := range runs {
+			if r.completionTime.Before(cutoff) {
+				names = append(names, r.name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,412 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	operatorfake "github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	"github.com/tektoncd/operator/pkg/client/informers/externalversions"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	pipelinefake "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+)
+
+var now = time.Date(2022, time.June, 15, 10, 7, 0, 0, time.UTC)
+
+func testPipelineRun(ns, name string, completed time.Duration) *v1beta1.PipelineRun {
+	pr := &v1beta1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}
+	if completed == 0 {
+		return pr
+	}
+	pr.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue})
+	pr.Status.CompletionTime = &metav1.Time{Time: now.Add(-completed)}
+	return pr
+}
+
+func testTaskRun(ns, name string, completed time.Duration, labels map[string]string) *v1beta1.TaskRun {
+	tr := &v1beta1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: labels}}
+	tr.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse})
+	tr.Status.CompletionTime = &metav1.Time{Time: now.Add(-completed)}
+	return tr
+}
+
+func newTestPruner(t *testing.T, kubeObjects []runtime.Object, pipelineObjects []runtime.Object,
+	policies ...*v1alpha1.TektonPrunerPolicy) (*Pruner, *pipelinefake.Clientset, chan time.Duration) {
+	t.Helper()
+	enqueued := make(chan time.Duration, 10)
+	pc := pipelinefake.NewSimpleClientset(pipelineObjects...)
+	oc := operatorfake.NewSimpleClientset()
+	informer := externalversions.NewSharedInformerFactory(oc, 0).Operator().V1alpha1().TektonPrunerPolicies()
+	for _, policy := range policies {
+		_, err := oc.OperatorV1alpha1().TektonPrunerPolicies(policy.Namespace).Create(context.Background(), policy, metav1.CreateOptions{})
+		assert.NilError(t, err)
+		assert.NilError(t, informer.Informer().GetIndexer().Add(policy))
+	}
+	p := New(context.Background(), fake.NewSimpleClientset(kubeObjects...), pc, oc, informer.Lister(), func(d time.Duration) {
+		enqueued <- d
+	})
+	p.now = func() time.Time { return now }
+	p.started = now.Add(-time.Hour)
+	return p, pc, enqueued
+}
+
+func names(t *testing.T, list func() ([]string, error)) []string {
+	t.Helper()
+	n, err := list()
+	assert.NilError(t, err)
+	sort.Strings(n)
+	return n
+}
+
+func TestPrunerReconcile(t *testing.T) {
+	keep := uint(2)
+	tc := &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ConfigResourceName},
+		Spec: v1alpha1.TektonConfigSpec{
+			Pruner: v1alpha1.Prune{
+				Resources: []string{"pipelinerun", "taskrun"},
+				Keep:      &keep,
+				Schedule:  "0 * * * *",
+			},
+		},
+	}
+	p, pc, enqueued := newTestPruner(t,
+		[]runtime.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-one"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-two", Annotations: map[string]string{
+				"operator.tekton.dev/prune.resources": "taskrun",
+			}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-three", Annotations: map[string]string{
+				"operator.tekton.dev/prune.schedule": "invalid",
+			}}},
+		},
+		[]runtime.Object{
+			testPipelineRun("ns-one", "pr-1", time.Minute),
+			testPipelineRun("ns-one", "pr-2", 2*time.Minute),
+			testPipelineRun("ns-one", "pr-3", 3*time.Minute),
+			testPipelineRun("ns-one", "pr-4", 4*time.Minute),
+			testPipelineRun("ns-one", "pr-running", 0),
+			testTaskRun("ns-one", "tr-1", time.Minute, nil),
+			testTaskRun("ns-two", "tr-1", time.Minute, nil),
+			testTaskRun("ns-two", "tr-2", 2*time.Minute, nil),
+			testTaskRun("ns-two", "tr-3", 3*time.Minute, nil),
+			testTaskRun("ns-two", "tr-of-pr", 4*time.Minute, map[string]string{pipeline.PipelineRunLabelKey: "pr"}),
+			testPipelineRun("ns-two", "pr-1", time.Minute),
+			testPipelineRun("ns-two", "pr-2", 2*time.Minute),
+			testPipelineRun("ns-two", "pr-3", 3*time.Minute),
+		},
+	)
+
+	// the schedule came up since the pruner started
+	assert.NilError(t, p.Reconcile(context.Background(), tc))
+	assert.Equal(t, <-enqueued, time.Duration(0))
+
+	assert.NilError(t, p.Reconcile(context.Background(), tc))
+	assert.DeepEqual(t, tc.Status.Pruner, &v1alpha1.PrunerStatus{
+		Namespaces: []v1alpha1.NamespacePruneStatus{
+			{
+				Namespace:     "ns-one",
+				LastPruneTime: &metav1.Time{Time: now},
+				Deleted:       map[string]int32{"pipelinerun": 2, "taskrun": 0},
+			},
+			{
+				Namespace: "ns-three",
+				Error:     `invalid schedule "invalid": expected exactly 5 fields, found 1: [invalid]`,
+			},
+			{
+				Namespace:     "ns-two",
+				LastPruneTime: &metav1.Time{Time: now},
+				Deleted:       map[string]int32{"taskrun": 1},
+			},
+		},
+	})
+	// nothing is due until the next hour
+	assert.Equal(t, <-enqueued, 53*time.Minute)
+
+	assert.DeepEqual(t, names(t, func() ([]string, error) {
+		prs, err := pc.TektonV1beta1().PipelineRuns("").List(context.Background(), metav1.ListOptions{})
+		var n []string
+		for _, pr := range prs.Items {
+			n = append(n, fmt.Sprintf("%s/%s", pr.Namespace, pr.Name))
+		}
+		return n, err
+	}), []string{"ns-one/pr-1", "ns-one/pr-2", "ns-one/pr-running", "ns-two/pr-1", "ns-two/pr-2", "ns-two/pr-3"})
+	assert.DeepEqual(t, names(t, func() ([]string, error) {
+		trs, err := pc.TektonV1beta1().TaskRuns("").List(context.Background(), metav1.ListOptions{})
+		var n []string
+		for _, tr := range trs.Items {
+			n = append(n, fmt.Sprintf("%s/%s", tr.Namespace, tr.Name))
+		}
+		return n, err
+	}), []string{"ns-one/tr-1", "ns-two/tr-1", "ns-two/tr-2", "ns-two/tr-of-pr"})
+}
+
+func TestPrunerRemovedFromTektonConfig(t *testing.T) {
+	tc := &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ConfigResourceName},
+		Status: v1alpha1.TektonConfigStatus{
+			Pruner: &v1alpha1.PrunerStatus{
+				Namespaces: []v1alpha1.NamespacePruneStatus{{Namespace: "ns-one"}},
+			},
+		},
+	}
+	p, _, enqueued := newTestPruner(t, nil, nil)
+
+	assert.NilError(t, p.Reconcile(context.Background(), tc))
+	assert.Assert(t, tc.Status.Pruner == nil)
+	assert.Equal(t, len(enqueued), 0)
+}
+
+func TestExpired(t *testing.T) {
+	keep := uint(1)
+	keepSince := uint(90)
+	runs := []run{
+		{name: "three", completionTime: now.Add(-3 * time.Hour)},
+		{name: "one", completionTime: now.Add(-time.Hour)},
+		{name: "two", completionTime: now.Add(-2 * time.Hour)},
+	}
+
+	assert.DeepEqual(t, expired(runs, v1alpha1.Prune{Keep: &keep}, now), []string{"two", "three"})
+	assert.DeepEqual(t, expired(runs, v1alpha1.Prune{KeepSince: &keepSince}, now), []string{"two", "three"})
+	// keep takes precedence, like it did with tkn
+	assert.DeepEqual(t, expired(runs, v1alpha1.Prune{Keep: &keep, KeepSince: &keepSince}, now), []string{"two", "three"})
+	keep = 5
+	assert.Assert(t, expired(runs, v1alpha1.Prune{Keep: &keep}, now) == nil)
+}
+
+func TestExpiredPerStatusAndGroup(t *testing.T) {
+	keep := uint(1)
+	keepFailed := uint(2)
+	keepSince := uint(90)
+	runs := []run{
+		{name: "build-ok-1", completionTime: now.Add(-1 * time.Hour), status: "succeeded", group: "build"},
+		{name: "build-ok-2", completionTime: now.Add(-2 * time.Hour), status: "succeeded", group: "build"},
+		{name: "build-failed-1", completionTime: now.Add(-3 * time.Hour), status: "failed", group: "build"},
+		{name: "build-failed-2", completionTime: now.Add(-4 * time.Hour), status: "failed", group: "build"},
+		{name: "build-failed-3", completionTime: now.Add(-5 * time.Hour), status: "failed", group: "build"},
+		{name: "deploy-ok-1", completionTime: now.Add(-6 * time.Hour), status: "succeeded", group: "deploy"},
+		{name: "deploy-cancelled-1", completionTime: now.Add(-7 * time.Hour), status: "cancelled", group: "deploy"},
+	}
+
+	// failed runs are counted apart from the others
+	assert.DeepEqual(t, expired(runs, v1alpha1.Prune{
+		Keep:   &keep,
+		Failed: &v1alpha1.PruneRetention{Keep: &keepFailed},
+	}, now), []string{"build-ok-2", "deploy-ok-1", "deploy-cancelled-1", "build-failed-3"})
+
+	// the last run of each pipeline is kept
+	assert.DeepEqual(t, expired(runs, v1alpha1.Prune{
+		Keep:    &keep,
+		GroupBy: "name",
+	}, now), []string{"build-ok-2", "build-failed-1", "build-failed-2", "build-failed-3", "deploy-cancelled-1"})
+
+	// only cancelled runs are pruned
+	assert.DeepEqual(t, expired(runs, v1alpha1.Prune{
+		Statuses:  []string{"cancelled"},
+		Cancelled: &v1alpha1.PruneRetention{KeepSince: &keepSince},
+	}, now), []string{"deploy-cancelled-1"})
+}
+
+func TestPruneResourceSelector(t *testing.T) {
+	keep := uint(1)
+	kept := testPipelineRun("ns-one", "pr-kept", 3*time.Minute)
+	kept.Labels = map[string]string{"keep": "true"}
+	cancelled := testPipelineRun("ns-one", "pr-cancelled", 4*time.Minute)
+	cancelled.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse, Reason: "Cancelled"})
+	p, pc, _ := newTestPruner(t, nil, []runtime.Object{
+		testPipelineRun("ns-one", "pr-1", time.Minute),
+		testPipelineRun("ns-one", "pr-2", 2*time.Minute),
+		kept,
+		cancelled,
+	})
+
+	deleted, notArchived, failed, err := p.pruneResource(context.Background(), "ns-one", "pipelinerun", v1alpha1.Prune{
+		Keep: &keep,
+		Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key: "keep", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"true"},
+		}}},
+		Statuses: []string{"succeeded", "failed"},
+	}, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, deleted, 1)
+	assert.Equal(t, notArchived, 0)
+	assert.Equal(t, failed, 0)
+
+	prs, err := pc.TektonV1beta1().PipelineRuns("ns-one").List(context.Background(), metav1.ListOptions{})
+	assert.NilError(t, err)
+	var n []string
+	for _, pr := range prs.Items {
+		n = append(n, pr.Name)
+	}
+	sort.Strings(n)
+	assert.DeepEqual(t, n, []string{"pr-1", "pr-cancelled", "pr-kept"})
+}
+
+func testPolicy(ns, name string, created time.Duration, spec v1alpha1.Prune) *v1alpha1.TektonPrunerPolicy {
+	return &v1alpha1.TektonPrunerPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         ns,
+			Generation:        1,
+			CreationTimestamp: metav1.Time{Time: now.Add(-created)},
+		},
+		Spec: v1alpha1.TektonPrunerPolicySpec{Prune: spec},
+	}
+}
+
+func TestPrunerPolicies(t *testing.T) {
+	keep := uint(2)
+	keepOne := uint(1)
+	tc := &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ConfigResourceName},
+		Spec: v1alpha1.TektonConfigSpec{
+			Pruner: v1alpha1.Prune{
+				Resources: []string{"pipelinerun"},
+				Keep:      &keep,
+				Schedule:  "0 * * * *",
+			},
+		},
+	}
+	p, pc, enqueued := newTestPruner(t,
+		[]runtime.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-one", Annotations: map[string]string{
+				"operator.tekton.dev/prune.skip": "true",
+			}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-two"}},
+		},
+		[]runtime.Object{
+			testPipelineRun("ns-one", "pr-1", time.Minute),
+			testPipelineRun("ns-one", "pr-2", 2*time.Minute),
+			testPipelineRun("ns-one", "pr-3", 3*time.Minute),
+			testPipelineRun("ns-two", "pr-1", time.Minute),
+			testPipelineRun("ns-two", "pr-2", 2*time.Minute),
+			testPipelineRun("ns-two", "pr-3", 3*time.Minute),
+		},
+		// the policy overrides prune.skip and takes the schedule of TektonConfig
+		testPolicy("ns-one", "oldest", 2*time.Hour, v1alpha1.Prune{Resources: []string{"pipelinerun"}, Keep: &keepOne}),
+		testPolicy("ns-one", "newest", time.Hour, v1alpha1.Prune{Resources: []string{"pipelinerun"}, Keep: &keep}),
+		testPolicy("kube-system", "ignored", time.Hour, v1alpha1.Prune{Resources: []string{"pipelinerun"}, Keep: &keep}),
+		// a policy may not archive to the volume of the operator
+		testPolicy("ns-three", "archive", time.Hour, v1alpha1.Prune{Resources: []string{"pipelinerun"}, Keep: &keep,
+			Archive: &v1alpha1.PruneArchive{Sink: "pvc"}}),
+		// the runs are never recorded without Tekton Results
+		testPolicy("ns-four", "results", time.Hour, v1alpha1.Prune{Resources: []string{"pipelinerun"}, Keep: &keep,
+			Archive: &v1alpha1.PruneArchive{Sink: "results"}}),
+	)
+
+	assert.NilError(t, p.Reconcile(context.Background(), tc))
+	assert.Equal(t, <-enqueued, time.Duration(0))
+	assert.NilError(t, p.Reconcile(context.Background(), tc))
+	assert.Equal(t, <-enqueued, 53*time.Minute)
+
+	prs, err := pc.TektonV1beta1().PipelineRuns("").List(context.Background(), metav1.ListOptions{})
+	assert.NilError(t, err)
+	var n []string
+	for _, pr := range prs.Items {
+		n = append(n, fmt.Sprintf("%s/%s", pr.Namespace, pr.Name))
+	}
+	sort.Strings(n)
+	assert.DeepEqual(t, n, []string{"ns-one/pr-1", "ns-two/pr-1", "ns-two/pr-2"})
+
+	policy := func(ns, name string) *v1alpha1.TektonPrunerPolicyStatus {
+		tp, err := p.operatorClientSet.OperatorV1alpha1().TektonPrunerPolicies(ns).Get(context.Background(), name, metav1.GetOptions{})
+		assert.NilError(t, err)
+		return &tp.Status
+	}
+	oldest := policy("ns-one", "oldest")
+	assert.Assert(t, oldest.IsReady())
+	assert.Equal(t, oldest.ObservedGeneration, int64(1))
+	assert.DeepEqual(t, oldest.LastPruneTime, &metav1.Time{Time: now})
+	assert.DeepEqual(t, oldest.NextPruneTime, &metav1.Time{Time: now.Add(53 * time.Minute)})
+	assert.DeepEqual(t, oldest.Deleted, map[string]int32{"pipelinerun": 2})
+	assert.Equal(t, policy("ns-one", "newest").GetCondition(apis.ConditionReady).Reason, "Superseded")
+	assert.Equal(t, policy("kube-system", "ignored").GetCondition(apis.ConditionReady).Reason, "NamespaceIgnored")
+	assert.Equal(t, policy("ns-three", "archive").GetCondition(apis.ConditionReady).Reason, "ArchiveNotAllowed")
+	assert.Equal(t, policy("ns-four", "results").GetCondition(apis.ConditionReady).Reason, "ResultsNotEnabled")
+}
+
+func TestPrunerPolicyWithoutSchedule(t *testing.T) {
+	keep := uint(1)
+	tc := &v1alpha1.TektonConfig{ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ConfigResourceName}}
+	p, _, enqueued := newTestPruner(t, nil, nil,
+		testPolicy("ns-one", "policy", time.Hour, v1alpha1.Prune{Resources: []string{"pipelinerun"}, Keep: &keep}))
+
+	assert.NilError(t, p.Reconcile(context.Background(), tc))
+	assert.Assert(t, tc.Status.Pruner == nil)
+	assert.Equal(t, len(enqueued), 0)
+
+	tp, err := p.operatorClientSet.OperatorV1alpha1().TektonPrunerPolicies("ns-one").Get(context.Background(), "policy", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, tp.Status.GetCondition(apis.ConditionReady).Reason, "NoSchedule")
+}
+
+func TestPrunerPolicyUpdateFailure(t *testing.T) {
+	keep := uint(1)
+	tc := &v1alpha1.TektonConfig{ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ConfigResourceName}}
+	p, _, enqueued := newTestPruner(t, nil, nil,
+		testPolicy("ns-one", "conflicting", time.Hour, v1alpha1.Prune{Resources: []string{"pipelinerun"}, Keep: &keep}),
+		testPolicy("ns-two", "policy", time.Hour, v1alpha1.Prune{Resources: []string{"pipelinerun"}, Keep: &keep}))
+	p.operatorClientSet.(*operatorfake.Clientset).PrependReactor("update", "tektonprunerpolicies",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetNamespace() != "ns-one" {
+				return false, nil, nil
+			}
+			return true, nil, apierrors.NewConflict(v1alpha1.Resource("tektonprunerpolicies"), "conflicting",
+				fmt.Errorf("the object has been modified"))
+		})
+
+	// the failed policy doesn't fail the pruner nor hold back the other policy
+	assert.NilError(t, p.Reconcile(context.Background(), tc))
+	assert.Equal(t, <-enqueued, policyRetryDelay)
+
+	tp, err := p.operatorClientSet.OperatorV1alpha1().TektonPrunerPolicies("ns-two").Get(context.Background(), "policy", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, tp.Status.GetCondition(apis.ConditionReady).Reason, "NoSchedule")
+}
+
+func TestPrunerUnreported(t *testing.T) {
+	p, _, _ := newTestPruner(t, nil, nil)
+	last := &metav1.Time{Time: now}
+	p.results = map[string]v1alpha1.NamespacePruneStatus{
+		"ns-one":   {Namespace: "ns-one", LastPruneTime: last, Errors: 2, Error: "forbidden"},
+		"ns-two":   {Namespace: "ns-two", LastPruneTime: last},
+		"ns-three": {Namespace: "ns-three", LastPruneTime: last, NotArchived: map[string]int32{"pipelinerun": 1}},
+	}
+
+	unreported := p.unreported(nil)
+	assert.Equal(t, len(unreported), 2)
+	assert.Equal(t, unreported[0].Namespace, "ns-one")
+	assert.Equal(t, unreported[1].Namespace, "ns-three")
+
+	// a run is reported once, the status already holds the same run
+	reported := &v1alpha1.PrunerStatus{Namespaces: []v1alpha1.NamespacePruneStatus{p.results["ns-one"], p.results["ns-three"]}}
+	assert.Equal(t, len(p.unreported(reported)), 0)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruner
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// a Wednesday
+	from := time.Date(2022, time.June, 15, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		schedule string
+		want     time.Time
+	}{
+		{"* * * * *", time.Date(2022, time.June, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/5 * * * *", time.Date(2022, time.June, 15, 10, 10, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2022, time.June, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2022, time.June, 16, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2022, time.June, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * MON", time.Date(2022, time.June, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2022, time.June, 15, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2022, time.June, 16, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2022, time.June, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 9-17/4 * * 1-5", time.Date(2022, time.June, 15, 13, 0, 0, 0, time.UTC)},
+		{"15,45 10 * * *", time.Date(2022, time.June, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		// restricted day of month and day of week match when either does
+		{"0 0 20 * 5", time.Date(2022, time.June, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, test := range tests {
+		t.Run(test.schedule, func(t *testing.T) {
+			s, err := parseSchedule(test.schedule)
+			assert.NilError(t, err)
+			assert.Equal(t, s.Next(from), test.want)
+		})
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, schedule := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@fortnightly",
+	} {
+		t.Run(schedule, func(t *testing.T) {
+			_, err := parseSchedule(schedule)
+			assert.Assert(t, err != nil)
+		})
+	}
+}
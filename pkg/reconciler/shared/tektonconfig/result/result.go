@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	op "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/logging"
+)
+
+func EnsureTektonResultExists(ctx context.Context, clients op.TektonResultInterface, tc *v1alpha1.TektonResult) (*v1alpha1.TektonResult, error) {
+	trCR, err := GetResult(ctx, clients, v1alpha1.ResultResourceName)
+
+	if err != nil {
+		if !apierrs.IsNotFound(err) {
+			return nil, err
+		}
+		if err := CreateResult(ctx, clients, tc); err != nil {
+			return nil, err
+		}
+		return nil, v1alpha1.RECONCILE_AGAIN_ERR
+	}
+
+	// a TektonResult created by hand is neither adopted nor updated
+	if !isControlledByTektonConfig(trCR) {
+		logging.FromContext(ctx).Debugf("TektonResult %q is not created by TektonConfig, leaving it alone", trCR.Name)
+	} else {
+		trCR, err = UpdateResult(ctx, trCR, tc, clients)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ok, err := isTektonResultReady(trCR, err)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, v1alpha1.RECONCILE_AGAIN_ERR
+	}
+
+	return trCR, err
+}
+
+func GetResult(ctx context.Context, clients op.TektonResultInterface, name string) (*v1alpha1.TektonResult, error) {
+	return clients.Get(ctx, name, metav1.GetOptions{})
+}
+
+func GetTektonResultCR(config *v1alpha1.TektonConfig) *v1alpha1.TektonResult {
+	ownerRef := *metav1.NewControllerRef(config, config.GroupVersionKind())
+	return &v1alpha1.TektonResult{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            v1alpha1.ResultResourceName,
+			OwnerReferences: []metav1.OwnerReference{ownerRef},
+		},
+		Spec: v1alpha1.TektonResultSpec{
+			CommonSpec: v1alpha1.CommonSpec{
+				TargetNamespace: config.Spec.TargetNamespace,
+			},
+			ResultsAPIProperties: config.Spec.Result.ResultsAPIProperties,
+			Config:               config.Spec.Config,
+			Options:              config.Spec.Options,
+		},
+	}
+}
+
+func CreateResult(ctx context.Context, clients op.TektonResultInterface, tc *v1alpha1.TektonResult) error {
+	_, err := clients.Create(ctx, tc, metav1.CreateOptions{})
+	return err
+}
+
+func UpdateResult(ctx context.Context, old *v1alpha1.TektonResult, new *v1alpha1.TektonResult, clients op.TektonResultInterface) (*v1alpha1.TektonResult, error) {
+	// if the result spec is changed then update the instance
+	updated := false
+
+	if new.Spec.TargetNamespace != old.Spec.TargetNamespace {
+		old.Spec.TargetNamespace = new.Spec.TargetNamespace
+		updated = true
+	}
+
+	if !reflect.DeepEqual(old.Spec.ResultsAPIProperties, new.Spec.ResultsAPIProperties) {
+		old.Spec.ResultsAPIProperties = new.Spec.ResultsAPIProperties
+		updated = true
+	}
+
+	if !reflect.DeepEqual(old.Spec.Config, new.Spec.Config) {
+		old.Spec.Config = new.Spec.Config
+		updated = true
+	}
+
+	if !reflect.DeepEqual(old.Spec.Options, new.Spec.Options) {
+		old.Spec.Options = new.Spec.Options
+		updated = true
+	}
+
+	if updated {
+		_, err := clients.Update(ctx, old, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return nil, v1alpha1.RECONCILE_AGAIN_ERR
+	}
+	return old, nil
+}
+
+// isTektonResultReady will check the status conditions of the TektonResult and return true if the TektonResult is ready.
+func isTektonResultReady(s *v1alpha1.TektonResult, err error) (bool, error) {
+	if s.GetStatus() != nil && s.GetStatus().GetCondition(apis.ConditionReady) != nil {
+		if strings.Contains(s.GetStatus().GetCondition(apis.ConditionReady).Message, v1alpha1.UpgradePending) {
+			return false, v1alpha1.DEPENDENCY_UPGRADE_PENDING_ERR
+		}
+	}
+	return s.Status.IsReady(), err
+}
+
+// EnsureTektonResultCRNotExists deletes the TektonResult created by TektonConfig,
+// a TektonResult created by hand is left alone
+func EnsureTektonResultCRNotExists(ctx context.Context, clients op.TektonResultInterface) error {
+	trCR, err := GetResult(ctx, clients, v1alpha1.ResultResourceName)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			// TektonResult CR is gone, hence return nil
+			return nil
+		}
+		return err
+	}
+	if !isControlledByTektonConfig(trCR) {
+		return nil
+	}
+	// if the Get was successful, try deleting the CR
+	if err := clients.Delete(ctx, v1alpha1.ResultResourceName, metav1.DeleteOptions{}); err != nil {
+		if apierrs.IsNotFound(err) {
+			// TektonResult CR is gone, hence return nil
+			return nil
+		}
+		return fmt.Errorf("TektonResult %q failed to delete: %v", v1alpha1.ResultResourceName, err)
+	}
+	// if the Delete API call was success,
+	// then return requeue_event
+	// so that in a subsequent reconcile call the absence of the CR is verified by one of the 2 checks above
+	return v1alpha1.RECONCILE_AGAIN_ERR
+}
+
+// isControlledByTektonConfig tells whether the TektonResult was created by TektonConfig
+func isControlledByTektonConfig(trCR *v1alpha1.TektonResult) bool {
+	owner := metav1.GetControllerOf(trCR)
+	return owner != nil && owner.Kind == v1alpha1.KindTektonConfig
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	op "github.com/tektoncd/operator/pkg/client/clientset/versioned/typed/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/client/injection/client/fake"
+	util "github.com/tektoncd/operator/pkg/reconciler/common/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ts "knative.dev/pkg/reconciler/testing"
+)
+
+func getTektonConfig() *v1alpha1.TektonConfig {
+	return &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: v1alpha1.ConfigResourceName,
+		},
+		Spec: v1alpha1.TektonConfigSpec{
+			Profile: v1alpha1.ProfileAll,
+			CommonSpec: v1alpha1.CommonSpec{
+				TargetNamespace: "tekton-pipelines",
+			},
+			Result: v1alpha1.ResultConfig{
+				Enable: true,
+			},
+		},
+	}
+}
+
+func TestEnsureTektonResultExists(t *testing.T) {
+	ctx, _, _ := ts.SetupFakeContextWithCancel(t)
+	c := fake.Get(ctx)
+	tr := GetTektonResultCR(getTektonConfig())
+
+	// first invocation should create instance as it is non-existent and return RECONCILE_AGAIN_ERR
+	_, err := EnsureTektonResultExists(ctx, c.OperatorV1alpha1().TektonResults(), tr)
+	util.AssertEqual(t, err, v1alpha1.RECONCILE_AGAIN_ERR)
+
+	// next invocation should return RECONCILE_AGAIN_ERR as Result is waiting for installation
+	_, err = EnsureTektonResultExists(ctx, c.OperatorV1alpha1().TektonResults(), tr)
+	util.AssertEqual(t, err, v1alpha1.RECONCILE_AGAIN_ERR)
+
+	// mark the instance ready
+	markResultReady(t, ctx, c.OperatorV1alpha1().TektonResults())
+
+	// next invocation should return nil error as the instance is ready
+	_, err = EnsureTektonResultExists(ctx, c.OperatorV1alpha1().TektonResults(), tr)
+	util.AssertEqual(t, err, nil)
+
+	// test update propagation from tektonConfig
+	tr.Spec.TargetNamespace = "foobar"
+	_, err = EnsureTektonResultExists(ctx, c.OperatorV1alpha1().TektonResults(), tr)
+	util.AssertEqual(t, err, v1alpha1.RECONCILE_AGAIN_ERR)
+
+	_, err = EnsureTektonResultExists(ctx, c.OperatorV1alpha1().TektonResults(), tr)
+	util.AssertEqual(t, err, nil)
+
+	// the results configuration is propagated as well
+	tr.Spec.ResultsAPIProperties = v1alpha1.ResultsAPIProperties{LogLevel: "debug"}
+	tr.Spec.Config = v1alpha1.Config{PriorityClassName: "tekton"}
+	_, err = EnsureTektonResultExists(ctx, c.OperatorV1alpha1().TektonResults(), tr)
+	util.AssertEqual(t, err, v1alpha1.RECONCILE_AGAIN_ERR)
+
+	got, err := GetResult(ctx, c.OperatorV1alpha1().TektonResults(), v1alpha1.ResultResourceName)
+	util.AssertEqual(t, err, nil)
+	util.AssertEqual(t, got.Spec.LogLevel, "debug")
+	util.AssertEqual(t, got.Spec.Config.PriorityClassName, "tekton")
+}
+
+func TestEnsureTektonResultExistsCreatedByHand(t *testing.T) {
+	ctx, _, _ := ts.SetupFakeContextWithCancel(t)
+	c := fake.Get(ctx)
+
+	tr := GetTektonResultCR(getTektonConfig())
+	tr.OwnerReferences = nil
+	util.AssertEqual(t, CreateResult(ctx, c.OperatorV1alpha1().TektonResults(), tr), nil)
+	markResultReady(t, ctx, c.OperatorV1alpha1().TektonResults())
+
+	// a TektonResult not created by TektonConfig is neither adopted nor updated
+	config := GetTektonResultCR(getTektonConfig())
+	config.Spec.TargetNamespace = "foobar"
+	_, err := EnsureTektonResultExists(ctx, c.OperatorV1alpha1().TektonResults(), config)
+	util.AssertEqual(t, err, nil)
+
+	onCluster, err := GetResult(ctx, c.OperatorV1alpha1().TektonResults(), v1alpha1.ResultResourceName)
+	util.AssertEqual(t, err, nil)
+	util.AssertEqual(t, len(onCluster.OwnerReferences), 0)
+	util.AssertEqual(t, onCluster.Spec.TargetNamespace, "tekton-pipelines")
+}
+
+func TestEnsureTektonResultCRNotExists(t *testing.T) {
+	ctx, _, _ := ts.SetupFakeContextWithCancel(t)
+	c := fake.Get(ctx)
+
+	// when no instance exists, nil error is returned immediately
+	err := EnsureTektonResultCRNotExists(ctx, c.OperatorV1alpha1().TektonResults())
+	util.AssertEqual(t, err, nil)
+
+	// create an instance for testing other cases
+	tr := GetTektonResultCR(getTektonConfig())
+	_, err = EnsureTektonResultExists(ctx, c.OperatorV1alpha1().TektonResults(), tr)
+	util.AssertEqual(t, err, v1alpha1.RECONCILE_AGAIN_ERR)
+
+	// when an instance exists the first invocation should make the delete API call and
+	// return RECONCILE_AGAIN_ERR. So that the deletion can be confirmed in a subsequent invocation
+	err = EnsureTektonResultCRNotExists(ctx, c.OperatorV1alpha1().TektonResults())
+	util.AssertEqual(t, err, v1alpha1.RECONCILE_AGAIN_ERR)
+
+	// when the instance is completely removed from a cluster, the function should return nil error
+	err = EnsureTektonResultCRNotExists(ctx, c.OperatorV1alpha1().TektonResults())
+	util.AssertEqual(t, err, nil)
+}
+
+func markResultReady(t *testing.T, ctx context.Context, c op.TektonResultInterface) {
+	t.Helper()
+	tr, err := c.Get(ctx, v1alpha1.ResultResourceName, metav1.GetOptions{})
+	util.AssertEqual(t, err, nil)
+	tr.Status.MarkDependenciesInstalled()
+	tr.Status.MarkInstallerSetAvailable()
+	tr.Status.MarkInstallerSetReady()
+	_, err = c.UpdateStatus(ctx, tr, metav1.UpdateOptions{})
+	util.AssertEqual(t, err, nil)
+}
@@ -19,14 +19,15 @@ package tektonconfig
 import (
 	"context"
 	"fmt"
+	"time"
 
 	mf "github.com/manifestival/manifestival"
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
 	clientset "github.com/tektoncd/operator/pkg/client/clientset/versioned"
 	tektonConfigreconciler "github.com/tektoncd/operator/pkg/client/injection/reconciler/operator/v1alpha1/tektonconfig"
 	"github.com/tektoncd/operator/pkg/reconciler/common"
-	"github.com/tektoncd/operator/pkg/reconciler/shared/tektonconfig/pipeline"
-	"github.com/tektoncd/operator/pkg/reconciler/shared/tektonconfig/trigger"
+	"github.com/tektoncd/operator/pkg/reconciler/shared/tektonconfig/pruner"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -45,6 +46,10 @@ type Reconciler struct {
 	extension       common.Extension
 	manifest        mf.Manifest
 	operatorVersion string
+	// pruner deletes completed PipelineRuns and TaskRuns
+	pruner *pruner.Pruner
+	// metrics reports the health of the components
+	metrics *Recorder
 }
 
 // Check that our Reconciler implements controller.Reconciler
@@ -59,14 +64,13 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, original *v1alpha1.Tekton
 		logger.Error("Failed to finalize platform resources", err)
 	}
 
-	if original.Spec.Profile == v1alpha1.ProfileLite {
-		return pipeline.EnsureTektonPipelineCRNotExists(ctx, r.operatorClientSet.OperatorV1alpha1().TektonPipelines())
-	} else {
-		// TektonPipeline and TektonTrigger is common for profile type basic and all
-		if err := trigger.EnsureTektonTriggerCRNotExists(ctx, r.operatorClientSet.OperatorV1alpha1().TektonTriggers()); err != nil {
-			return err
+	// remove the CRs of the enabled components in the reverse dependency order
+	all := components(r.operatorClientSet)
+	for i := len(all) - 1; i >= 0; i-- {
+		if !original.Spec.ComponentEnabled(all[i].name) {
+			continue
 		}
-		if err := pipeline.EnsureTektonPipelineCRNotExists(ctx, r.operatorClientSet.OperatorV1alpha1().TektonPipelines()); err != nil {
+		if err := all[i].remove(ctx); err != nil {
 			return err
 		}
 	}
@@ -112,54 +116,66 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, tc *v1alpha1.TektonConfi
 
 	tc.Status.MarkPreInstallComplete()
 
-	// Ensure if the pipeline CR already exists, if not create Pipeline CR
-	tektonpipeline := pipeline.GetTektonPipelineCR(tc)
-	// Ensure it exists
-	if _, err := pipeline.EnsureTektonPipelineExists(ctx, r.operatorClientSet.OperatorV1alpha1().TektonPipelines(), tektonpipeline); err != nil {
-		tc.Status.MarkComponentNotReady(fmt.Sprintf("TektonPipeline: %s", err.Error()))
-		if err == v1alpha1.RECONCILE_AGAIN_ERR {
-			return v1alpha1.REQUEUE_EVENT_AFTER
-		}
-		return nil
-	}
-
-	// Create TektonTrigger CR if the profile is all or basic
-	if tc.Spec.Profile == v1alpha1.ProfileAll || tc.Spec.Profile == v1alpha1.ProfileBasic {
-		tektontrigger := trigger.GetTektonTriggerCR(tc)
-		if _, err := trigger.EnsureTektonTriggerExists(ctx, r.operatorClientSet.OperatorV1alpha1().TektonTriggers(), tektontrigger); err != nil {
-			tc.Status.MarkComponentNotReady(fmt.Sprintf("TektonTrigger: %s", err.Error()))
-			return v1alpha1.REQUEUE_EVENT_AFTER
+	// Create the CRs of the enabled components, in dependency order, and
+	// remove the ones which are disabled
+	for _, c := range components(r.operatorClientSet) {
+		var err error
+		if tc.Spec.ComponentEnabled(c.name) {
+			err = c.ensure(ctx, tc)
+		} else {
+			err = c.remove(ctx)
 		}
-	} else {
-		if err := trigger.EnsureTektonTriggerCRNotExists(ctx, r.operatorClientSet.OperatorV1alpha1().TektonTriggers()); err != nil {
-			tc.Status.MarkComponentNotReady(fmt.Sprintf("TektonTrigger: %s", err.Error()))
+		if err != nil {
+			r.updateComponentsStatus(ctx, tc)
+			// a component being removed or failing to be updated does not
+			// show in the status of the components
+			if tc.Status.GetCondition(v1alpha1.ComponentsReady).IsTrue() {
+				tc.Status.MarkComponentNotReady(fmt.Sprintf("%s: %s", c.kind, err.Error()))
+			}
 			return v1alpha1.REQUEUE_EVENT_AFTER
 		}
 	}
 
-	if err := common.Prune(ctx, r.kubeClientSet, tc); err != nil {
-		tc.Status.MarkComponentNotReady(fmt.Sprintf("tekton-resource-pruner: %s", err.Error()))
+	// the pruner used to run in CronJobs, remove the ones left by older versions
+	if err := common.DeletePruneCronJobs(ctx, r.kubeClientSet, tc.Spec.TargetNamespace); err != nil {
 		logger.Error(err)
 	}
-
-	tc.Status.MarkComponentsReady()
+	prunerErr := r.pruner.Reconcile(ctx, tc)
+	if prunerErr != nil {
+		logger.Error(prunerErr)
+	}
 
 	if err := r.extension.PostReconcile(ctx, tc); err != nil {
+		r.updateComponentsStatus(ctx, tc)
 		tc.Status.MarkPostInstallFailed(err.Error())
 		return v1alpha1.REQUEUE_EVENT_AFTER
 	}
 
 	tc.Status.MarkPostInstallComplete()
 
+	// TektonConfig is ready once all the components it installed are, the
+	// pruner is not one of them so its failure is marked on top
+	r.updateComponentsStatus(ctx, tc)
+	if prunerErr != nil {
+		tc.Status.MarkComponentNotReady(fmt.Sprintf("tekton-resource-pruner: %s", prunerErr.Error()))
+	}
+
 	if err := r.deleteObsoleteTargetNamespaces(ctx, tc); err != nil {
 		logger.Error(err)
 	}
 
+	if tc.Status.IsReady() {
+		r.reportUpgrade(ctx, tc)
+	}
+
 	// Update the object for any spec changes
 	if _, err := r.operatorClientSet.OperatorV1alpha1().TektonConfigs().Update(ctx, tc, v1.UpdateOptions{}); err != nil {
 		return err
 	}
 
+	if !tc.Status.IsReady() {
+		return v1alpha1.REQUEUE_EVENT_AFTER
+	}
 	return nil
 }
 
@@ -231,6 +247,19 @@ func (r *Reconciler) markUpgrade(ctx context.Context, tc *v1alpha1.TektonConfig)
 		tc.Status.MarkPreInstallFailed(v1alpha1.UpgradePending)
 		tc.Status.MarkPostInstallFailed(v1alpha1.UpgradePending)
 		tc.Status.MarkNotReady("Upgrade Pending")
+		// the duration of the upgrade is reported once TektonConfig is ready
+		annotations := tc.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[v1alpha1.UpgradeStartTimeKey] = time.Now().UTC().Format(time.RFC3339)
+		tc.SetAnnotations(annotations)
+		common.RecordEvent(ctx, tc, corev1.EventTypeNormal, common.EventUpgradeStarted,
+			"Upgrading from release %s to %s", ver, r.operatorVersion)
+	}
+	if !ok {
+		common.RecordEvent(ctx, tc, corev1.EventTypeNormal, common.EventInstallStarted,
+			"Installing release %s with profile %s", r.operatorVersion, tc.Spec.Profile)
 	}
 	if labels == nil {
 		labels = map[string]string{}
@@ -245,6 +274,28 @@ func (r *Reconciler) markUpgrade(ctx context.Context, tc *v1alpha1.TektonConfig)
 	return v1alpha1.RECONCILE_AGAIN_ERR
 }
 
+// reportUpgrade reports the duration of the upgrade which completed, if any
+func (r *Reconciler) reportUpgrade(ctx context.Context, tc *v1alpha1.TektonConfig) {
+	annotations := tc.GetAnnotations()
+	started, ok := annotations[v1alpha1.UpgradeStartTimeKey]
+	if !ok {
+		return
+	}
+	delete(annotations, v1alpha1.UpgradeStartTimeKey)
+	tc.SetAnnotations(annotations)
+
+	logger := logging.FromContext(ctx)
+	start, err := time.Parse(time.RFC3339, started)
+	if err != nil {
+		logger.Warnf("invalid %s annotation: %v", v1alpha1.UpgradeStartTimeKey, err)
+		return
+	}
+	duration := time.Since(start)
+	r.metrics.LogUpgrade(r.operatorVersion, duration, logger)
+	common.RecordEvent(ctx, tc, corev1.EventTypeNormal, common.EventUpgradeSucceeded,
+		"Upgraded to release %s in %v, all components are ready", r.operatorVersion, duration.Round(time.Second))
+}
+
 func (r *Reconciler) addTargetNamespaceLabel(ctx context.Context, targetNamespace string) error {
 	ns, err := r.kubeClientSet.CoreV1().Namespaces().Get(ctx, targetNamespace, v1.GetOptions{})
 	if err != nil {
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tektonconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/client/clientset/versioned/fake"
+	listers "github.com/tektoncd/operator/pkg/client/listers/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
+	"github.com/tektoncd/operator/pkg/reconciler/shared/tektonconfig/pipeline"
+	"github.com/tektoncd/operator/pkg/reconciler/shared/tektonconfig/pruner"
+	pipelinefake "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/apis"
+)
+
+// failingPolicyLister fails to list the TektonPrunerPolicies
+type failingPolicyLister struct {
+	listers.TektonPrunerPolicyLister
+}
+
+func (failingPolicyLister) List(labels.Selector) ([]*v1alpha1.TektonPrunerPolicy, error) {
+	return nil, errors.New("informer is not synced")
+}
+
+func TestReconcileKindPrunerFailure(t *testing.T) {
+	ctx := context.Background()
+	tc := &v1alpha1.TektonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   v1alpha1.ConfigResourceName,
+			Labels: map[string]string{v1alpha1.ReleaseVersionKey: "devel"},
+		},
+		Spec: v1alpha1.TektonConfigSpec{
+			Profile:    v1alpha1.ProfileLite,
+			CommonSpec: v1alpha1.CommonSpec{TargetNamespace: "tekton-pipelines"},
+		},
+	}
+	tc.SetDefaults(ctx)
+
+	tp := pipeline.GetTektonPipelineCR(tc)
+	tp.Status.InitializeConditions()
+	tp.Status.MarkPreReconcilerComplete()
+	tp.Status.MarkInstallerSetAvailable()
+	tp.Status.MarkInstallerSetReady()
+	tp.Status.MarkPostReconcilerComplete()
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "tekton-pipelines",
+		Labels: map[string]string{"operator.tekton.dev/targetNamespace": "true"},
+	}}
+	kubeClient := kubefake.NewSimpleClientset(ns)
+	operatorClient := fake.NewSimpleClientset(tc, tp)
+
+	r := &Reconciler{
+		kubeClientSet:     kubeClient,
+		operatorClientSet: operatorClient,
+		extension:         common.NoExtension(ctx),
+		operatorVersion:   "devel",
+		pruner: pruner.New(ctx, kubeClient, pipelinefake.NewSimpleClientset(), operatorClient,
+			failingPolicyLister{}, func(time.Duration) {}),
+	}
+
+	err := r.ReconcileKind(ctx, tc)
+	assert.Equal(t, err, v1alpha1.REQUEUE_EVENT_AFTER)
+
+	// the pipeline is ready but the pruner is not
+	assert.Equal(t, len(tc.Status.Components), 1)
+	assert.Equal(t, tc.Status.Components[0].Ready, corev1.ConditionTrue)
+	assert.Assert(t, !tc.Status.IsReady())
+	ready := tc.Status.GetCondition(apis.ConditionReady)
+	assert.Equal(t, ready.Status, corev1.ConditionFalse)
+	assert.Equal(t, ready.Message, "Components not in ready state: tekton-resource-pruner: informer is not synced")
+}
@@ -75,6 +75,8 @@ func GetTektonTriggerCR(config *v1alpha1.TektonConfig) *v1alpha1.TektonTrigger {
 			},
 			Config:  config.Spec.Config,
 			Trigger: config.Spec.Trigger,
+			Options: config.Spec.Options,
+			Upgrade: config.Spec.Upgrade,
 		},
 	}
 }
@@ -103,6 +105,16 @@ func UpdateTrigger(ctx context.Context, old *v1alpha1.TektonTrigger, new *v1alph
 		updated = true
 	}
 
+	if !reflect.DeepEqual(old.Spec.Options, new.Spec.Options) {
+		old.Spec.Options = new.Spec.Options
+		updated = true
+	}
+
+	if !reflect.DeepEqual(old.Spec.Upgrade, new.Spec.Upgrade) {
+		old.Spec.Upgrade = new.Spec.Upgrade
+		updated = true
+	}
+
 	if old.ObjectMeta.OwnerReferences == nil {
 		old.ObjectMeta.OwnerReferences = new.ObjectMeta.OwnerReferences
 		updated = true
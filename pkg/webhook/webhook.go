@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"github.com/tektoncd/operator/pkg/reconciler/common"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
@@ -31,11 +32,12 @@ import (
 )
 
 var types = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
-	v1alpha1.SchemeGroupVersion.WithKind("TektonConfig"):   &v1alpha1.TektonConfig{},
-	v1alpha1.SchemeGroupVersion.WithKind("TektonPipeline"): &v1alpha1.TektonPipeline{},
-	v1alpha1.SchemeGroupVersion.WithKind("TektonTrigger"):  &v1alpha1.TektonTrigger{},
-	v1alpha1.SchemeGroupVersion.WithKind("TektonHub"):      &v1alpha1.TektonHub{},
-	v1alpha1.SchemeGroupVersion.WithKind("TektonChain"):    &v1alpha1.TektonChain{},
+	v1alpha1.SchemeGroupVersion.WithKind("TektonConfig"):       &v1alpha1.TektonConfig{},
+	v1alpha1.SchemeGroupVersion.WithKind("TektonPipeline"):     &v1alpha1.TektonPipeline{},
+	v1alpha1.SchemeGroupVersion.WithKind("TektonTrigger"):      &v1alpha1.TektonTrigger{},
+	v1alpha1.SchemeGroupVersion.WithKind("TektonHub"):          &v1alpha1.TektonHub{},
+	v1alpha1.SchemeGroupVersion.WithKind("TektonChain"):        &v1alpha1.TektonChain{},
+	v1alpha1.SchemeGroupVersion.WithKind("TektonPrunerPolicy"): &v1alpha1.TektonPrunerPolicy{},
 }
 
 func SetTypes(platform string) {
@@ -43,6 +45,7 @@ func SetTypes(platform string) {
 		types[v1alpha1.SchemeGroupVersion.WithKind("TektonAddon")] = &v1alpha1.TektonAddon{}
 	} else {
 		types[v1alpha1.SchemeGroupVersion.WithKind("TektonDashboard")] = &v1alpha1.TektonDashboard{}
+		types[v1alpha1.SchemeGroupVersion.WithKind("TektonResult")] = &v1alpha1.TektonResult{}
 	}
 }
 
@@ -68,6 +71,9 @@ func NewDefaultingAdmissionController(ctx context.Context, cmw configmap.Watcher
 }
 
 func NewValidationAdmissionController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	// The versions shipped with the operator to validate spec.version of components
+	shippedVersions := common.ShippedVersions()
+
 	return validation.NewAdmissionController(ctx,
 
 		// Name of the resource webhook.
@@ -81,7 +87,7 @@ func NewValidationAdmissionController(ctx context.Context, cmw configmap.Watcher
 
 		// A function that infuses the context passed to Validate/SetDefaults with custom metadata.
 		func(ctx context.Context) context.Context {
-			return ctx
+			return v1alpha1.WithShippedVersions(ctx, shippedVersions)
 		},
 
 		// Whether to disallow unknown fields.